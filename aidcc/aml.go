@@ -0,0 +1,364 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/shopspring/decimal"
+	"github.com/vishal3152/AIDChaincode/aidcc/events"
+)
+
+// Asset model for anti-money-laundering limits. All asset models are kept in
+// private scope i.e. they are not exported and ramin invisible to other packages
+// Limits are configured by compliance officers and enforced whenever a donation is written. A donation
+// over any of the limits is parked for review and reaches project funds only once approved. A zero
+// limit is not enforced.
+type amlLimitsBase struct {
+	SingleLimit  decimal.Decimal `json:"singleLimit"`  // single donation limit
+	RollingLimit decimal.Decimal `json:"rollingLimit"` // donor total over the rolling window
+	AnonymousCap decimal.Decimal `json:"anonymousCap"` // single anonymous donation limit
+	Currency     string          `json:"currency"`     // ISO 4217 code of the limits
+	UpdatedBy    string          `json:"updatedBy"`
+	UpdatedDt    time.Time       `json:"updatedDt"`
+}
+
+type amlLimits struct {
+	ObjectType string        `json:"docType"` // AML limits Type 'AMLCFG'
+	versioned                // schema version of the document
+	Data       amlLimitsBase `json:"data"` // composition
+}
+
+// key - ledger key of the AML limits
+func (al *amlLimits) key() string {
+	return AMLKEY
+}
+
+// docType - doc type of AML limits assets
+func (al *amlLimits) docType() string {
+	return AMLCFG
+}
+
+// validate - check AML limits invariants before it is written
+func (al *amlLimits) validate() *chainError {
+	return checkIdentity(al, al.ObjectType)
+}
+
+// amlReview - reasons a donation is parked for review and its outcome
+type amlReview struct {
+	Reasons    []string  `json:"reasons"`
+	ReviewedBy string    `json:"reviewedBy,omitempty"`
+	ReviewedDt time.Time `json:"reviewedDt"`
+	Remarks    string    `json:"remarks,omitempty"`
+}
+
+// Write AML limits to ledger, limits in force are replaced
+func (al *amlLimits) putState(stub shim.ChaincodeStubInterface) pb.Response {
+
+	cErr := newRepository(stub, "putAMLLimits").save(al)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	// Emit transaction event for listeners
+	cErr = emitEvent(stub, "putAMLLimits", AMLKEY, events.AMLLimitsSet, &events.AMLLimits{SingleLimit: al.Data.SingleLimit, RollingLimit: al.Data.RollingLimit,
+		AnonymousCap: al.Data.AnonymousCap, Currency: al.Data.Currency, UpdatedBy: al.Data.UpdatedBy})
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	r := response{CODEALLAOK, AMLKEY, nil}
+	return shim.Success((r.formatResponse(stub)))
+}
+
+// Read AML limits in force
+func (al *amlLimits) getState(stub shim.ChaincodeStubInterface) pb.Response {
+
+	aml, cErr := queryAsset(stub, AMLKEY)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	r := response{CODEALLAOK, "OK", aml}
+	return shim.Success((r.formatResponse(stub)))
+}
+
+// anonymous - check if donor identifier denotes an anonymous donation
+func anonymous(donor string) bool {
+	return strings.EqualFold(strings.TrimSpace(donor), ANONDNR)
+}
+
+// checkLimits - evaluate the donation against AML limits in force. Reasons for review are returned when
+// the donation is over any limit, no reason is returned when no limits are configured
+func checkLimits(stub shim.ChaincodeStubInterface, d *donation) ([]string, *chainError) {
+
+	reasons := []string{}
+	al := &amlLimits{}
+	amlBytes, cErr := queryAsset(stub, AMLKEY)
+	if cErr != nil {
+		if cErr.code == CODENOTFOUND {
+			return reasons, nil
+		}
+		return nil, cErr
+	}
+	err := json.Unmarshal(amlBytes, al)
+	if err != nil {
+		return nil, &chainError{"checkLimits", d.TxnID, CODEGENEXCEPTION, err}
+	}
+
+	// limits are evaluated in their currency, donation is parked when it can not be converted
+	amount, cErr := amountIn(stub, d.Data.Currency, al.Data.Currency, d.Data.TimeStamp, d.Data.Amount)
+	if cErr != nil {
+		if cErr.code == CODENOTFOUND {
+			return append(reasons, "No FX rate to evaluate limits in "+al.Data.Currency), nil
+		}
+		return nil, cErr
+	}
+
+	if al.Data.SingleLimit.IsPositive() && amount.GreaterThan(al.Data.SingleLimit) {
+		reasons = append(reasons, "Donation exceeds single donation limit of "+al.Data.SingleLimit.String()+" "+al.Data.Currency)
+	}
+	if al.Data.AnonymousCap.IsPositive() && anonymous(d.Donor) && amount.GreaterThan(al.Data.AnonymousCap) {
+		reasons = append(reasons, "Anonymous donation exceeds cap of "+al.Data.AnonymousCap.String()+" "+al.Data.Currency)
+	}
+	if al.Data.RollingLimit.IsPositive() {
+		total, cErr := donorTotal(stub, d.Donor, al.Data.Currency, d.Data.TimeStamp.AddDate(0, 0, -AMLWINDOW))
+		if cErr != nil {
+			if cErr.code == CODENOTFOUND {
+				return append(reasons, "No FX rate to evaluate limits in "+al.Data.Currency), nil
+			}
+			return nil, cErr
+		}
+		if total.Add(amount).GreaterThan(al.Data.RollingLimit) {
+			reasons = append(reasons, "Donor total exceeds rolling limit of "+al.Data.RollingLimit.String()+" "+al.Data.Currency)
+		}
+	}
+	return reasons, nil
+}
+
+// admit - evaluate the donation against AML limits in force & add it to its donor's history, every donation
+// of a donor is admitted before it is written. Reasons for review are returned when over any limit
+func (d *donation) admit(stub shim.ChaincodeStubInterface, fcn string) ([]string, *chainError) {
+
+	reasons, cErr := checkLimits(stub, d)
+	if cErr != nil {
+		return nil, cErr
+	}
+	return reasons, putDonorTotal(stub, fcn, d, d.Data.Amount)
+}
+
+// amountIn - amount converted to another currency at the rate effective on the date
+func amountIn(stub shim.ChaincodeStubInterface, from string, to string, date time.Time, amount decimal.Decimal) (decimal.Decimal, *chainError) {
+
+	if from == to {
+		return amount, nil
+	}
+	rate, cErr := rateAt(stub, from, to, date)
+	if cErr != nil {
+		return amount, cErr
+	}
+	if rate.Inverted {
+		return amount.Div(rate.Rate), nil
+	}
+	return amount.Mul(rate.Rate), nil
+}
+
+// putDonorTotal - add a donation to the donor's history, rejected donations are added with a negative amount
+func putDonorTotal(stub shim.ChaincodeStubInterface, fcn string, d *donation, amount decimal.Decimal) *chainError {
+
+	timeStamp := d.Data.TimeStamp.UTC().Format(time.RFC3339)
+	donor := strings.ToLower(strings.TrimSpace(d.Donor))
+	indexKey, err := stub.CreateCompositeKey(DNRIDX, []string{donor, timeStamp, d.Data.Currency, d.TxnID, amount.String()})
+	if err != nil {
+		return &chainError{fcn, d.TxnID, CODEGENEXCEPTION, err}
+	}
+	err = stub.PutState(indexKey, []byte{0x00})
+	if err != nil {
+		return &chainError{fcn, d.TxnID, CODEGENEXCEPTION, err}
+	}
+	return nil
+}
+
+// donorTotal - sum of donor's donations since the given time, converted to the currency
+func donorTotal(stub shim.ChaincodeStubInterface, donor string, currency string, since time.Time) (decimal.Decimal, *chainError) {
+
+	total, _ := decimal.NewFromString("0")
+	cutoff := since.UTC().Format(time.RFC3339)
+	dnrItr, err := stub.GetStateByPartialCompositeKey(DNRIDX, []string{strings.ToLower(strings.TrimSpace(donor))})
+	if err != nil {
+		return total, &chainError{"donorTotal", donor, CODEGENEXCEPTION, err}
+	}
+	//Close itrerator when done reading
+	defer dnrItr.Close()
+	for dnrItr.HasNext() {
+		rangeItem, err := dnrItr.Next()
+		if err != nil {
+			return total, &chainError{"donorTotal", donor, CODEGENEXCEPTION, err}
+		}
+		_, compositeKeyParts, err := stub.SplitCompositeKey(rangeItem.Key)
+		if err != nil {
+			return total, &chainError{"donorTotal", donor, CODEGENEXCEPTION, err}
+		}
+		// compositeKeyParts[1] represents timestamp, [2] currency & [4] amount. RFC3339 UTC timestamps sort as strings
+		if compositeKeyParts[1] < cutoff {
+			continue
+		}
+		timeStamp, err := time.Parse(time.RFC3339, compositeKeyParts[1])
+		if err != nil {
+			return total, &chainError{"donorTotal", donor, CODEGENEXCEPTION, err}
+		}
+		amount, _ := decimal.NewFromString(compositeKeyParts[4])
+		amount, cErr := amountIn(stub, compositeKeyParts[2], currency, timeStamp, amount)
+		if cErr != nil {
+			return total, cErr
+		}
+		total = total.Add(amount)
+	}
+	return total, nil
+}
+
+// park - write donation pending review to ledger & the review queue. Parked donation does not reach
+// project funds
+func (d *donation) park(stub shim.ChaincodeStubInterface, reasons []string) *chainError {
+
+	d.Status = DONRVW
+	d.Review = &amlReview{Reasons: reasons}
+	cErr := d.store(stub, "putDonation")
+	if cErr != nil {
+		return cErr
+	}
+
+	indexKey, err := stub.CreateCompositeKey(RVWIDX, []string{d.Data.ProjectID, d.TxnID})
+	if err != nil {
+		return &chainError{"putDonation", d.TxnID, CODEGENEXCEPTION, err}
+	}
+	err = stub.PutState(indexKey, []byte{0x00})
+	if err != nil {
+		return &chainError{"putDonation", d.TxnID, CODEGENEXCEPTION, err}
+	}
+	return nil
+}
+
+// reviewState - approve or reject a donation pending review. Approved donation is recorded under
+// project funds, rejected donation is removed from donor's history
+func (d *donation) reviewState(stub shim.ChaincodeStubInterface, approve bool, remarks string, callerID string, timeStamp time.Time) pb.Response {
+
+	fcn := "approveDonation"
+	if !approve {
+		fcn = "rejectDonation"
+	}
+	donBytes, cErr := queryAsset(stub, d.TxnID)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	err := json.Unmarshal(donBytes, d)
+	if err != nil {
+		cErr = &chainError{fcn, d.TxnID, CODEGENEXCEPTION, err}
+		return errorResponse(stub, cErr)
+	}
+	if d.ObjectType != DONIN || d.Status != DONRVW {
+		cErr = &chainError{fcn, d.TxnID, CODENOTALLWD, errors.New("Donation is not pending review")}
+		return errorResponse(stub, cErr)
+	}
+
+	d.Review.ReviewedBy = callerID
+	d.Review.ReviewedDt = timeStamp
+	d.Review.Remarks = remarks
+	if approve && len(d.MatchOf) != 0 {
+		// sponsor match is written as is, it is not split for overhead or matched again
+		d.Status = DONAPRV
+		cErr = d.write(stub, fcn)
+		if cErr == nil {
+			cErr = emitEvent(stub, fcn, d.TxnID, events.DonationRecorded, d.event())
+		}
+	} else if approve {
+		d.Status = DONAPRV
+		cErr = d.record(stub, fcn)
+		if cErr == nil {
+			cErr = emitEvent(stub, fcn, d.TxnID, events.DonationRecorded, d.event())
+		}
+	} else {
+		d.Status = DONRJCT
+		cErr = putDonorTotal(stub, fcn, d, d.Data.Amount.Neg())
+		if cErr == nil {
+			cErr = d.store(stub, fcn)
+		}
+		// rejected sponsor match returns its amount to the campaign cap
+		if cErr == nil && len(d.MatchOf) != 0 {
+			cErr = unmatch(stub, fcn, d)
+		}
+	}
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	// Remove donation from the review queue
+	indexKey, err := stub.CreateCompositeKey(RVWIDX, []string{d.Data.ProjectID, d.TxnID})
+	if err != nil {
+		cErr = &chainError{fcn, d.TxnID, CODEGENEXCEPTION, err}
+		return errorResponse(stub, cErr)
+	}
+	err = stub.DelState(indexKey)
+	if err != nil {
+		cErr = &chainError{fcn, d.TxnID, CODEGENEXCEPTION, err}
+		return errorResponse(stub, cErr)
+	}
+
+	// Emit transaction event for listeners
+	cErr = emitEvent(stub, fcn, d.TxnID, events.DonationReviewed, &events.DonationReview{TxnID: d.TxnID, ProjectID: d.Data.ProjectID, Status: d.Status, ReviewedBy: callerID, Remarks: remarks})
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	r := response{CODEALLAOK, d.TxnID, nil}
+	return shim.Success((r.formatResponse(stub)))
+}
+
+// reviewQueueState - read donations pending review, optionally of a single project
+func reviewQueueState(stub shim.ChaincodeStubInterface, projectID string) pb.Response {
+
+	keys := []string{}
+	if len(projectID) != 0 {
+		keys = append(keys, projectID)
+	}
+	rvwItr, err := stub.GetStateByPartialCompositeKey(RVWIDX, keys)
+	if err != nil {
+		cErr := &chainError{"readReviewQueue", projectID, CODEGENEXCEPTION, err}
+		return errorResponse(stub, cErr)
+	}
+	//Close itrerator when done reading
+	defer rvwItr.Close()
+	queue := []donation{}
+	for rvwItr.HasNext() {
+		rangeItem, err := rvwItr.Next()
+		if err != nil {
+			cErr := &chainError{"readReviewQueue", projectID, CODEGENEXCEPTION, err}
+			return errorResponse(stub, cErr)
+		}
+		_, compositeKeyParts, err := stub.SplitCompositeKey(rangeItem.Key)
+		if err != nil {
+			cErr := &chainError{"readReviewQueue", projectID, CODEGENEXCEPTION, err}
+			return errorResponse(stub, cErr)
+		}
+		// compositeKeyParts[1] represents txnID
+		donBytes, cErr := queryAsset(stub, compositeKeyParts[1])
+		if cErr != nil {
+			return errorResponse(stub, cErr)
+		}
+		d := donation{}
+		err = json.Unmarshal(donBytes, &d)
+		if err != nil {
+			cErr = &chainError{"readReviewQueue", compositeKeyParts[1], CODEGENEXCEPTION, err}
+			return errorResponse(stub, cErr)
+		}
+		queue = append(queue, d)
+	}
+
+	b, err := json.Marshal(queue)
+	if err != nil {
+		cErr := &chainError{"readReviewQueue", projectID, CODEGENEXCEPTION, err}
+		return errorResponse(stub, cErr)
+	}
+	r := response{CODEALLAOK, "OK", b}
+	return shim.Success((r.formatResponse(stub)))
+}
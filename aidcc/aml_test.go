@@ -0,0 +1,364 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// Verifies scenarios related to AML limits and review of donations over the limits
+func TestAMLRW(t *testing.T) {
+	fmt.Println("Executing Test - AMLRW")
+	defer os.Unsetenv("ROLE")
+
+	// Test data - Refer to test narratives for test description
+	var limitsTable = []struct {
+		role           string
+		singleLimit    string
+		rollingLimit   string
+		anonymousCap   string
+		currency       string
+		expectedStatus int32
+		testNarrative  string
+	}{
+		{"donor", "1000", "1500", "200", "", 500, "Check for caller authorized as compliance officer"},
+		{ROLECMPL, "-1", "1500", "200", "", 500, "Check for negative limit"},
+		{ROLECMPL, "1000", "abc", "200", "", 500, "Check for limit format"},
+		{ROLECMPL, "1000", "1500", "200", "ABC", 500, "Check for unsupported currency"},
+		{ROLECMPL, "1000", "1500", "200", "", 200, "Happy scenario"},
+	}
+	// Donations are executed in order
+	var donationTable = []struct {
+		txnID          string
+		donor          string
+		amount         string
+		expectedStatus string
+		testNarrative  string
+	}{
+		{"D101", "vishal", "500", "", "Donation within limits"},
+		{"D102", "vishal", "1200", DONRVW, "Donation over single donation limit"},
+		{"D103", "Anonymous", "300", DONRVW, "Anonymous donation over cap"},
+		{"D104", "Vishal", "900", DONRVW, "Donor total over rolling limit regardless of case"},
+		{"D105", "anonymous", "100", "", "Anonymous donation within cap"},
+	}
+	// Reviews are executed in order
+	var reviewTable = []struct {
+		function       string
+		txnID          string
+		remarks        string
+		expectedStatus int32
+		testNarrative  string
+	}{
+		{ApproveDonation, "D102", "", 200, "Happy scenario"},
+		{ApproveDonation, "D102", "", 500, "Check for donation already approved"},
+		{ApproveDonation, "D101", "", 500, "Check for donation not pending review"},
+		{RejectDonation, "D103", "", 500, "Check for missing rejection remarks"},
+		{RejectDonation, "D103", "Source of funds not established", 200, "Happy scenario"},
+	}
+
+	// struct for parsing the shim APIs response
+	type dResp struct {
+		Code    string   `json:"code"`
+		Message string   `json:"message"`
+		Payload donation `json:"payload"`
+	}
+	type qResp struct {
+		Code    string     `json:"code"`
+		Message string     `json:"message"`
+		Payload []donation `json:"payload"`
+	}
+	type pResp struct {
+		Code    string  `json:"code"`
+		Message string  `json:"message"`
+		Payload project `json:"payload"`
+	}
+	assert := assert.New(t)
+
+	// Instantiate mockStub using AidChaincode as the target chaincode to unit test
+	stub := shim.NewMockStub("TestStub", new(AidChaincode))
+	assert.NotNil(stub, "Stub is nil, Test stub creation failed")
+
+	uid := uuid.New().String()
+
+	// Add test project & item to state.
+	result := stub.MockInvoke(uid,
+		[][]byte{[]byte(AddProject),
+			[]byte("P101"),
+			[]byte("Prj101")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Saving test project to state failed.")
+
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(AddItem),
+			[]byte("Itm001"),
+			[]byte("Item001"),
+			[]byte("Medicine")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Saving test item to state failed.")
+
+	// Executing limits tests
+	for _, test := range limitsTable {
+		os.Setenv("ROLE", test.role)
+		result := stub.MockInvoke(uid,
+			[][]byte{[]byte(SetAMLLimits),
+				[]byte(test.singleLimit),
+				[]byte(test.rollingLimit),
+				[]byte(test.anonymousCap),
+				[]byte(test.currency)})
+
+		assert.Equal(test.expectedStatus, result.GetStatus(), test.testNarrative+" failed - "+(result.GetMessage()))
+	}
+
+	// Executing donation tests
+	for _, test := range donationTable {
+		result := stub.MockInvoke(uid,
+			[][]byte{[]byte(AddDonation),
+				[]byte(test.txnID),
+				[]byte(test.donor),
+				[]byte("P101"),
+				[]byte("Itm001"),
+				[]byte(test.amount)})
+		assert.EqualValues(shim.OK, result.GetStatus(), test.testNarrative+" failed - "+(result.GetMessage()))
+
+		result = stub.MockInvoke(uid,
+			[][]byte{[]byte(GetDonation),
+				[]byte(test.txnID)})
+		d := &dResp{}
+		err := json.Unmarshal(result.GetPayload(), d)
+		if err != nil {
+			panic(err)
+		}
+		assert.Equal(test.expectedStatus, d.Payload.Status, test.testNarrative+" failed - donation status mismatch")
+	}
+
+	// Verify review queue of the project
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(GetReviewQueue),
+			[]byte("P101")})
+	assert.EqualValues(shim.OK, result.GetStatus(), GetReviewQueue+" failed to read the review queue")
+
+	q := &qResp{}
+	err := json.Unmarshal(result.GetPayload(), q)
+	if err != nil {
+		panic(err)
+	}
+	assert.Len(q.Payload, 3, "Donations pending review mismatch")
+
+	// Executing review tests
+	for _, test := range reviewTable {
+		result := stub.MockInvoke(uid,
+			[][]byte{[]byte(test.function),
+				[]byte(test.txnID),
+				[]byte(test.remarks)})
+
+		assert.Equal(test.expectedStatus, result.GetStatus(), test.testNarrative+" failed - "+(result.GetMessage()))
+	}
+
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(GetReviewQueue)})
+	assert.EqualValues(shim.OK, result.GetStatus(), GetReviewQueue+" failed to read the review queue")
+
+	q = &qResp{}
+	err = json.Unmarshal(result.GetPayload(), q)
+	if err != nil {
+		panic(err)
+	}
+	assert.Len(q.Payload, 1, "Donations pending review mismatch after review")
+	assert.Equal("D104", q.Payload[0].TxnID, "Donation pending review mismatch")
+
+	// Verify only donations within limits & approved donations reach project funds
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(GetProject),
+			[]byte("P101")})
+	assert.EqualValues(shim.OK, result.GetStatus(), GetProject+" failed to read the project data")
+
+	p := &pResp{}
+	err = json.Unmarshal(result.GetPayload(), p)
+	if err != nil {
+		panic(err)
+	}
+	expectedAvl, _ := decimal.NewFromString("1800")
+	assert.True(expectedAvl.Equal(p.Payload.Data.AvlFund), "Available fund mismatch")
+}
+
+// Verifies linked donations are held to AML limits, sponsor matches on their own & overhead as part of its
+// donation
+func TestAMLLinked(t *testing.T) {
+	fmt.Println("Executing Test - AMLLinked")
+	defer os.Unsetenv("ROLE")
+
+	endDt := time.Now().UTC().AddDate(0, 0, 30).Format(DATEFMT)
+
+	// Test data - Refer to test narratives for test description. P101 takes 10% overhead for P900, sponsor
+	// matches donations to P102 at 2:1. Invokes are executed in order
+	var linkedTable = []struct {
+		args           []string
+		role           string
+		queryTxnID     string
+		expectedStatus string
+		expectedFound  bool
+		testNarrative  string
+	}{
+		{[]string{AddDonation, "D101", "vishal", "P101", "Itm001", "600"}, "", overheadKey("D101"), "", true, "Happy scenario - overhead of donation within limits"},
+		{[]string{AddDonation, "D102", "vishal", "P101", "Itm001", "880"}, "", "D102", "", true, "Happy scenario - overhead not counted twice under donor total"},
+		{[]string{AddDonation, "D103", "vishal", "P101", "Itm001", "100"}, "", overheadKey("D103"), "", false, "Check for overhead of donation over limits held back"},
+		{[]string{AddDonation, "D201", "donor1", "P102", "Itm001", "400"}, "", matchKey("D201", "C101"), "", true, "Happy scenario - sponsor match within limits"},
+		{[]string{AddDonation, "D202", "donor2", "P102", "Itm001", "300"}, "", matchKey("D202", "C101"), "", true, "Happy scenario - sponsor match within limits"},
+		{[]string{AddDonation, "D203", "donor3", "P102", "Itm001", "400"}, "", matchKey("D203", "C101"), DONRVW, true, "Check for sponsor match over rolling limit"},
+		{[]string{RejectDonation, matchKey("D203", "C101"), "Source of funds not established"}, ROLECMPL, matchKey("D203", "C101"), DONRJCT, true, "Happy scenario - sponsor match rejected"},
+	}
+
+	// struct for parsing the shim APIs response
+	type dResp struct {
+		Payload donation `json:"payload"`
+	}
+	type mResp struct {
+		Payload matchingCampaign `json:"payload"`
+	}
+	type pResp struct {
+		Payload project `json:"payload"`
+	}
+	assert := assert.New(t)
+
+	// Instantiate mockStub using AidChaincode as the target chaincode to unit test
+	stub := shim.NewMockStub("TestStub", new(AidChaincode))
+	assert.NotNil(stub, "Stub is nil, Test stub creation failed")
+
+	// Add test projects, item, campaign & AML limits to state.
+	setup := []struct {
+		args []string
+		role string
+	}{
+		{[]string{AddProject, "P900", "Overhead"}, ""},
+		{[]string{AddProject, "P101", "Prj101", "", "10", "P900"}, ""},
+		{[]string{AddProject, "P102", "Prj102"}, ""},
+		{[]string{AddItem, "Itm001", "Item001", "Medicine"}, ""},
+		{[]string{AddMatchingCampaign, "C101", "Sponsor", "P102", "2", "5000", "2020-01-01", endDt}, ""},
+		{[]string{SetAMLLimits, "1000", "1500", "200"}, ROLECMPL},
+	}
+	for _, step := range setup {
+		args := make([][]byte, len(step.args))
+		for i, arg := range step.args {
+			args[i] = []byte(arg)
+		}
+		os.Setenv("ROLE", step.role)
+		result := stub.MockInvoke(uuid.New().String(), args)
+		assert.EqualValues(shim.OK, result.GetStatus(), "Saving test data "+step.args[0]+" to state failed - "+result.GetMessage())
+	}
+
+	// Executing tests
+	for _, test := range linkedTable {
+		args := make([][]byte, len(test.args))
+		for i, arg := range test.args {
+			args[i] = []byte(arg)
+		}
+		os.Setenv("ROLE", test.role)
+		result := stub.MockInvoke(uuid.New().String(), args)
+		assert.EqualValues(shim.OK, result.GetStatus(), test.testNarrative+" failed - "+result.GetMessage())
+
+		result = stub.MockInvoke(uuid.New().String(), [][]byte{[]byte(GetDonation), []byte(test.queryTxnID)})
+		assert.Equal(test.expectedFound, result.GetStatus() == shim.OK, test.testNarrative+" failed - linked donation mismatch")
+		if result.GetStatus() != shim.OK {
+			continue
+		}
+		d := &dResp{}
+		err := json.Unmarshal(result.GetPayload(), d)
+		if err != nil {
+			panic(err)
+		}
+		assert.Equal(test.expectedStatus, d.Payload.Status, test.testNarrative+" failed - donation status mismatch")
+	}
+
+	// Verify rejected match is returned to the campaign cap & parked match never reached project funds
+	os.Unsetenv("ROLE")
+	result := stub.MockInvoke(uuid.New().String(), [][]byte{[]byte(GetMatchingCampaign), []byte("C101")})
+	assert.EqualValues(shim.OK, result.GetStatus(), GetMatchingCampaign+" failed to read the campaign")
+	m := &mResp{}
+	err := json.Unmarshal(result.GetPayload(), m)
+	if err != nil {
+		panic(err)
+	}
+	assert.Equal("1400", m.Payload.Data.MatchedAmt.String(), "Matched amount mismatch")
+	assert.Equal("3600", m.Payload.Data.RemainingCap.String(), "Remaining cap mismatch")
+
+	result = stub.MockInvoke(uuid.New().String(), [][]byte{[]byte(GetProject), []byte("P102")})
+	assert.EqualValues(shim.OK, result.GetStatus(), GetProject+" failed to read the project data")
+	p := &pResp{}
+	err = json.Unmarshal(result.GetPayload(), p)
+	if err != nil {
+		panic(err)
+	}
+	assert.Equal("2500", p.Payload.Data.AvlFund.String(), "Available fund mismatch")
+}
+
+// Verifies donations pending review & spends of a project are listed apart
+func TestAMLSpends(t *testing.T) {
+	fmt.Println("Executing Test - AMLSpends")
+	defer os.Unsetenv("ROLE")
+
+	// struct for parsing the shim APIs response
+	type qResp struct {
+		Payload []donation `json:"payload"`
+	}
+	type sResp struct {
+		Payload []spend `json:"payload"`
+	}
+	assert := assert.New(t)
+
+	// Instantiate mockStub using AidChaincode as the target chaincode to unit test
+	stub := shim.NewMockStub("TestStub", new(AidChaincode))
+	assert.NotNil(stub, "Stub is nil, Test stub creation failed")
+
+	// Add test project, item & AML limits to state, then a spend & a donation parked for review
+	setup := []struct {
+		args []string
+		role string
+	}{
+		{[]string{AddProject, "P101", "Prj101"}, ""},
+		{[]string{AddItem, "Itm001", "Item001", "Medicine"}, ""},
+		{[]string{SetAMLLimits, "1000", "1500", "200"}, ROLECMPL},
+		{[]string{AddDonation, "D101", "vishal", "P101", "Itm001", "500"}, ""},
+		{[]string{GetProject, "P101"}, ""},
+		{[]string{AddSpend, "S101", "beneficiary", "P101", "Itm001", "100"}, ""},
+		{[]string{AddDonation, "D102", "vishal", "P101", "Itm001", "1200"}, ""},
+	}
+	for _, step := range setup {
+		args := make([][]byte, len(step.args))
+		for i, arg := range step.args {
+			args[i] = []byte(arg)
+		}
+		os.Setenv("ROLE", step.role)
+		result := stub.MockInvoke(uuid.New().String(), args)
+		assert.EqualValues(shim.OK, result.GetStatus(), "Saving test data "+step.args[0]+" to state failed - "+result.GetMessage())
+	}
+	os.Unsetenv("ROLE")
+
+	// Review queue lists the parked donation only
+	result := stub.MockInvoke(uuid.New().String(), [][]byte{[]byte(GetReviewQueue), []byte("P101")})
+	assert.EqualValues(shim.OK, result.GetStatus(), GetReviewQueue+" failed to read the review queue - "+result.GetMessage())
+	q := &qResp{}
+	err := json.Unmarshal(result.GetPayload(), q)
+	if err != nil {
+		panic(err)
+	}
+	if assert.Len(q.Payload, 1, "Donations pending review mismatch") {
+		assert.Equal("D102", q.Payload[0].TxnID, "Donation pending review mismatch")
+	}
+
+	// Spends lacking evidence list the spend only
+	result = stub.MockInvoke(uuid.New().String(), [][]byte{[]byte(GetUnevidencedSpends), []byte("P101")})
+	assert.EqualValues(shim.OK, result.GetStatus(), GetUnevidencedSpends+" failed to read the project spends - "+result.GetMessage())
+	s := &sResp{}
+	err = json.Unmarshal(result.GetPayload(), s)
+	if err != nil {
+		panic(err)
+	}
+	if assert.Len(s.Payload, 1, "Spends lacking evidence mismatch") {
+		assert.Equal("S101", s.Payload[0].TxnID, "Spend lacking evidence mismatch")
+	}
+}
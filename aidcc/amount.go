@@ -0,0 +1,73 @@
+package main
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/shopspring/decimal"
+)
+
+// Amounts, rates & quantities are passed as plain decimal strings e.g. "120.50", with at most FIXEDPT
+// decimals. Exponents, NaN, infinities, signs other than minus, grouping & blanks are rejected rather than
+// coerced, so that a malformed input never becomes zero or gets rounded without notice.
+var (
+	amountPattern   = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+	exponentPattern = regexp.MustCompile(`^[-+]?[0-9.]+[eE][-+]?[0-9]+$`)
+)
+
+// parseAmount - parse a decimal input, rejecting malformed, over-precise & over-limit values with a code
+// specific to the reason
+func parseAmount(s string, max decimal.Decimal) (decimal.Decimal, *chainError) {
+
+	zeroDecimal, _ := decimal.NewFromString("0")
+	if len(s) == 0 {
+		return zeroDecimal, &chainError{"parseAmount", s, CODEAMTFORMAT, errors.New("Amount can not be empty")}
+	}
+	if !amountPattern.MatchString(s) {
+		reason := "Amount must be a decimal number e.g. 120.50"
+		lower := strings.ToLower(s)
+		if exponentPattern.MatchString(s) {
+			reason = "Amount can not be in exponent notation"
+		} else if strings.Contains(lower, "nan") || strings.Contains(lower, "inf") {
+			reason = "Amount must be a finite number"
+		}
+		return zeroDecimal, &chainError{"parseAmount", s, CODEAMTFORMAT, errors.New(reason)}
+	}
+	if i := strings.IndexByte(s, '.'); i >= 0 && len(s)-i-1 > FIXEDPT {
+		return zeroDecimal, &chainError{"parseAmount", s, CODEAMTPRECISION, errors.New("Amount can not have more than " + strconv.Itoa(FIXEDPT) + " decimals")}
+	}
+	amount, err := decimal.NewFromString(s)
+	if err != nil {
+		return zeroDecimal, &chainError{"parseAmount", s, CODEAMTFORMAT, errors.New("Amount must be a decimal number e.g. 120.50")}
+	}
+	if amount.Abs().GreaterThan(max) {
+		return zeroDecimal, &chainError{"parseAmount", s, CODEAMTRANGE, errors.New("Amount can not be more than " + max.String())}
+	}
+	return amount, nil
+}
+
+// amountLimit - max amount accepted by invokes, configured at instantiation. Defaults to MAXAMT
+func amountLimit(stub shim.ChaincodeStubInterface) decimal.Decimal {
+	max, _ := decimal.NewFromString(MAXAMT)
+	b, err := stub.GetState(AMTMAXKEY)
+	if err != nil || len(b) == 0 {
+		return max
+	}
+	configured, err := decimal.NewFromString(string(b))
+	if err != nil {
+		return max
+	}
+	return configured
+}
+
+// amount - parse the amount of a field, recording the error of the field if it is rejected
+func (fe *fieldErrors) amount(field string, s string, max decimal.Decimal) decimal.Decimal {
+	amount, cErr := parseAmount(s, max)
+	if cErr != nil {
+		fe.addCode(field, cErr.code, cErr.err.Error())
+	}
+	return amount
+}
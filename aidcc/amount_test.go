@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// Verifies scenarios related to the shared amount parser
+func TestParseAmount(t *testing.T) {
+	fmt.Println("Executing Test - ParseAmount")
+
+	// Test data - Refer to test narratives for test description
+	var amountTable = []struct {
+		input          string
+		expectedAmount string
+		expectedCode   string
+		testNarrative  string
+	}{
+		{"120.50", "120.5", "", "Happy scenario - decimal amount"},
+		{"500", "500", "", "Happy scenario - whole amount"},
+		{"0.00000001", "0.00000001", "", "Happy scenario - FIXEDPT decimals"},
+		{"-10", "-10", "", "Happy scenario - negative amount left to validations"},
+		{"1000", "1000", "", "Happy scenario - max amount"},
+		{"", "0", CODEAMTFORMAT, "Check for empty amount"},
+		{"abc", "0", CODEAMTFORMAT, "Check for non-numeric amount"},
+		{"1e3", "0", CODEAMTFORMAT, "Check for exponent notation"},
+		{"NaN", "0", CODEAMTFORMAT, "Check for NaN"},
+		{"Inf", "0", CODEAMTFORMAT, "Check for infinity"},
+		{"+10", "0", CODEAMTFORMAT, "Check for plus sign"},
+		{" 10", "0", CODEAMTFORMAT, "Check for blanks"},
+		{"1,000", "0", CODEAMTFORMAT, "Check for grouping"},
+		{".5", "0", CODEAMTFORMAT, "Check for missing whole part"},
+		{"0.000000001", "0", CODEAMTPRECISION, "Check for more than FIXEDPT decimals"},
+		{"1000.01", "0", CODEAMTRANGE, "Check for amount over max amount"},
+	}
+
+	assert := assert.New(t)
+	max, _ := decimal.NewFromString("1000")
+
+	for _, test := range amountTable {
+		amount, cErr := parseAmount(test.input, max)
+		if len(test.expectedCode) == 0 {
+			assert.Nil(cErr, test.testNarrative+" failed")
+		} else if assert.NotNil(cErr, test.testNarrative+" failed - error expected") {
+			assert.Equal(test.expectedCode, cErr.code, test.testNarrative+" failed - error code mismatch")
+		}
+		expectedAmount, _ := decimal.NewFromString(test.expectedAmount)
+		assert.True(expectedAmount.Equal(amount), test.testNarrative+" failed - amount mismatch")
+	}
+}
+
+// Verifies scenarios related to amount validations of invokes and the configured max amount
+func TestAmountLimit(t *testing.T) {
+	fmt.Println("Executing Test - AmountLimit")
+
+	// Test data - Refer to test narratives for test description. Invokes are executed in order
+	var invokeTable = []struct {
+		args          []string
+		expectedCode  string
+		testNarrative string
+	}{
+		{[]string{AddProject, "P101", "Prj101"}, CODEALLAOK, "Happy scenario - project"},
+		{[]string{AddItem, "Itm001", "Item001", "Medicine"}, CODEALLAOK, "Happy scenario - item"},
+		{[]string{AddDonation, "D101", "vishal", "P101", "Itm001", "5000"}, CODEALLAOK, "Happy scenario - donation at max amount"},
+		{[]string{AddDonation, "D102", "vishal", "P101", "Itm001", "5000.01"}, CODEAMTRANGE, "Check for donation over max amount"},
+		{[]string{AddDonation, "D102", "vishal", "P101", "Itm001", "abc"}, CODEAMTFORMAT, "Check for non-numeric donation"},
+		{[]string{AddDonation, "D102", "vishal", "P101", "Itm001", "1.123456789"}, CODEAMTPRECISION, "Check for over-precise donation"},
+		{[]string{AddDonation, `{"txnID":"D102","donor":"vishal","projectID":"P101","itemID":"Itm001","amount":1e3}`}, CODEAMTFORMAT, "Check for exponent in document amount"},
+		{[]string{AddSpend, "S101", "beneficiary", "P101", "Itm001", "NaN"}, CODEAMTFORMAT, "Check for NaN spend"},
+		{[]string{SetFXRate, "EUR/USD", "1.1e0", "2019-03-01"}, CODEAMTFORMAT, "Check for exponent in FX rate"},
+	}
+
+	// struct for parsing the failed response envelope
+	type eResp struct {
+		Code   string        `json:"code"`
+		Errors []errorDetail `json:"errors"`
+	}
+	assert := assert.New(t)
+
+	// Instantiate mockStub using AidChaincode as the target chaincode to unit test
+	stub := shim.NewMockStub("TestStub", new(AidChaincode))
+	assert.NotNil(stub, "Stub is nil, Test stub creation failed")
+
+	result := stub.MockInit(uuid.New().String(), [][]byte{[]byte(Init), []byte("1e4")})
+	assert.EqualValues(shim.ERROR, result.GetStatus(), "Check for malformed max amount failed - error expected")
+	result = stub.MockInit(uuid.New().String(), [][]byte{[]byte(Init), []byte("5000")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Init failed to configure max amount - "+result.GetMessage())
+
+	// Executing amount validation tests
+	for _, test := range invokeTable {
+		args := make([][]byte, len(test.args))
+		for i, arg := range test.args {
+			args[i] = []byte(arg)
+		}
+		result := stub.MockInvoke(uuid.New().String(), args)
+		if test.expectedCode == CODEALLAOK {
+			assert.EqualValues(shim.OK, result.GetStatus(), test.testNarrative+" failed - "+result.GetMessage())
+			continue
+		}
+		e := &eResp{}
+		err := json.Unmarshal(result.GetPayload(), e)
+		if err != nil {
+			panic(err)
+		}
+		assert.Equal(CODEUNPROCESSABLEENTITY, e.Code, test.testNarrative+" failed - error code mismatch")
+		if assert.Len(e.Errors, 1, test.testNarrative+" failed - one field error expected") {
+			assert.Equal(test.expectedCode, e.Errors[0].Code, test.testNarrative+" failed - field error code mismatch")
+		}
+	}
+}
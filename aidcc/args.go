@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// Write invokes accept either positional string arguments or a single JSON document argument with named
+// fields e.g. {"txnID":"D101","donor":"vishal","projectID":"P101","itemID":"Itm001","amount":"500"}.
+// A document is validated against the schema registered for the function and converted to positional
+// arguments, hence both forms go through the same validations. New fields are added to the end of a schema.
+
+// Kinds of document fields
+const (
+	ARGSTR string = "string" // JSON string
+	ARGAMT string = "amount" // JSON string or number, decimal
+	ARGDT  string = "date"   // JSON string in DATEFMT
+)
+
+// argField - named field of a document argument
+type argField struct {
+	Name     string `json:"name"`
+	Kind     string `json:"kind"`
+	Required bool   `json:"required"`
+}
+
+// isDocument - check if arguments are a single JSON document
+func isDocument(args []string) bool {
+	return len(args) == 1 && strings.HasPrefix(strings.TrimSpace(args[0]), "{")
+}
+
+// documentArgs - convert a JSON document argument of a write invoke to positional arguments. Positional
+// arguments are returned as is. Absent optional fields are passed empty, trailing ones are dropped.
+// Errors of all fields are reported together.
+func documentArgs(h *handler, args []string) ([]string, *fieldErrors) {
+
+	schema := h.Args
+	if !h.Write || !isDocument(args) {
+		return args, nil
+	}
+
+	fe := newFieldErrors("documentArgs")
+	doc := make(map[string]interface{})
+	dec := json.NewDecoder(bytes.NewReader([]byte(args[0])))
+	dec.UseNumber()
+	err := dec.Decode(&doc)
+	if err != nil {
+		fe.add("", "Malformed JSON document: "+err.Error())
+		return nil, fe
+	}
+
+	// reject fields not in the schema, a misspelt optional field would otherwise be silently ignored
+	known := make(map[string]bool, len(schema))
+	for _, f := range schema {
+		known[f.Name] = true
+	}
+	unknown := []string{}
+	for name := range doc {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	for _, name := range unknown {
+		fe.add(name, "Unknown field "+name)
+	}
+
+	positional := make([]string, len(schema))
+	n := 0
+	for i, f := range schema {
+		v, present := doc[f.Name]
+		if !present || v == nil {
+			if f.Required {
+				fe.add(f.Name, "Field "+f.Name+" is required")
+			}
+			continue
+		}
+		switch val := v.(type) {
+		case string:
+			positional[i] = val
+		case json.Number:
+			if f.Kind != ARGAMT {
+				fe.add(f.Name, "Field "+f.Name+" must be a string")
+			}
+			positional[i] = val.String()
+		default:
+			if f.Kind == ARGAMT {
+				fe.add(f.Name, "Field "+f.Name+" must be a number or a string")
+			} else {
+				fe.add(f.Name, "Field "+f.Name+" must be a string")
+			}
+		}
+		n = i + 1
+	}
+	if fe.failed() {
+		return nil, fe
+	}
+	return positional[:n], nil
+}
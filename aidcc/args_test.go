@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// Verifies scenarios related to JSON document arguments of write invokes
+func TestDocumentArgs(t *testing.T) {
+	fmt.Println("Executing Test - DocumentArgs")
+
+	// Test data - Refer to test narratives for test description. Invokes are executed in order
+	var argsTable = []struct {
+		args           []string
+		expectedStatus int32
+		testNarrative  string
+	}{
+		{[]string{AddProject, `{"projectID":"P101","projectName":"Prj101"}`}, 200, "Happy scenario - project document"},
+		{[]string{AddProject, "P102", "Prj102"}, 200, "Legacy positional arguments"},
+		{[]string{AddItem, `{"itemID":"Itm001","itemType":"Item001","narrative":"Medicine"}`}, 200, "Happy scenario - item document"},
+		{[]string{AddDonation, `{"txnID":"D101","donor":"vishal","projectID":"P101","itemID":"Itm001","amount":"500"}`}, 200, "Happy scenario - amount as string"},
+		{[]string{AddDonation, `{"txnID":"D102","donor":"vishal","projectID":"P101","itemID":"Itm001","amount":250.75}`}, 200, "Happy scenario - amount as number"},
+		{[]string{AddDonation, `{"txnID":"D103","donor":"vishal","projectID":"P101","itemID":"Itm001","amount":"40","currency":"EUR"}`}, 200, "Happy scenario - optional field after an absent one"},
+		{[]string{AddDonation, "D104", "vishal", "P101", "Itm001", "10"}, 200, "Legacy positional arguments"},
+		{[]string{AddDonation, `{"txnID":"D105","donor":"vishal","projectID":"P101","itemID":"Itm001"}`}, 500, "Check for missing required field"},
+		{[]string{AddDonation, `{"txnID":"D105","donor":"vishal","projectID":"P101","itemID":"Itm001","amount":"10","memo":"x"}`}, 500, "Check for unknown field"},
+		{[]string{AddDonation, `{"txnID":105,"donor":"vishal","projectID":"P101","itemID":"Itm001","amount":"10"}`}, 500, "Check for field type"},
+		{[]string{AddDonation, `{"txnID":"D105","donor":"vishal","projectID":"P101","itemID":"Itm001","amount":true}`}, 500, "Check for amount type"},
+		{[]string{AddDonation, `{"txnID":"D105","donor":"vishal",`}, 500, "Check for malformed document"},
+		{[]string{AddDonation, `{"txnID":"D105","donor":"","projectID":"P101","itemID":"Itm001","amount":"10"}`}, 500, "Check for positional validations on documents"},
+		{[]string{RejectDonation, `{"txnID":"D101"}`}, 500, "Check for required field of a short document"},
+	}
+
+	// struct for parsing the shim APIs response
+	type dResp struct {
+		Code    string   `json:"code"`
+		Message string   `json:"message"`
+		Payload donation `json:"payload"`
+	}
+	assert := assert.New(t)
+
+	// Instantiate mockStub using AidChaincode as the target chaincode to unit test
+	stub := shim.NewMockStub("TestStub", new(AidChaincode))
+	assert.NotNil(stub, "Stub is nil, Test stub creation failed")
+
+	// Executing document argument tests
+	for _, test := range argsTable {
+		args := make([][]byte, len(test.args))
+		for i, arg := range test.args {
+			args[i] = []byte(arg)
+		}
+		result := stub.MockInvoke(uuid.New().String(), args)
+		assert.Equal(test.expectedStatus, result.GetStatus(), test.testNarrative+" failed - "+(result.GetMessage()))
+	}
+
+	// Verify donation recorded from a document with optional fields
+	result := stub.MockInvoke(uuid.New().String(),
+		[][]byte{[]byte(GetDonation),
+			[]byte("D103")})
+	assert.EqualValues(shim.OK, result.GetStatus(), GetDonation+" failed to read the donation")
+
+	d := &dResp{}
+	err := json.Unmarshal(result.GetPayload(), d)
+	if err != nil {
+		panic(err)
+	}
+	expectedAmount, _ := decimal.NewFromString("40")
+	assert.True(expectedAmount.Equal(d.Payload.Data.Amount), "Donation amount mismatch")
+	assert.Equal("EUR", d.Payload.Data.Currency, "Donation currency mismatch")
+	assert.Empty(d.Payload.PledgeID, "Absent optional field not left empty")
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/shopspring/decimal"
+	"github.com/vishal3152/AIDChaincode/aidcc/events"
+)
+
+// Asset model for project budget line. All asset models are kept in
+// private scope i.e. they are not exported and ramin invisible to other packages
+// Budget lines are kept under the project. Once a project defines a budget, each spend must be
+// tagged with one of its categories and cumulative category spends are capped at the allocated amount.
+// Vouchers issued under a category hold their amount against it until they are redeemed or expire.
+type budgetLine struct {
+	Category  string          `json:"category"`  // e.g. logistics, staff, materials
+	Allocated decimal.Decimal `json:"allocated"` // in project's base currency
+}
+
+type projectBudgetLine struct {
+	ProjectID string     `json:"projectID"` // affiliated project, category is unique within the project
+	Data      budgetLine `json:"data"`      // composition
+}
+
+// budgetUtilisation - allocated vs spent amount of a budget line
+type budgetUtilisation struct {
+	Category  string          `json:"category"`
+	Allocated decimal.Decimal `json:"allocated"`
+	Spent     decimal.Decimal `json:"spent"` // including amounts held by outstanding vouchers
+	Remaining decimal.Decimal `json:"remaining"`
+}
+
+// Write budget line under the project
+func (bl *projectBudgetLine) putState(stub shim.ChaincodeStubInterface) pb.Response {
+
+	p := &project{ProjectID: bl.ProjectID}
+	cErr := p.load(stub, "putBudgetLine")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	// budget lines cap all spends of the project, only the project owner may add them
+	cErr = checkProjectOwner(stub, "putBudgetLine", p)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	// check if category is unique within the project
+	for _, l := range p.Data.Budget {
+		if l.Category == bl.Data.Category {
+			cErr = &chainError{"putBudgetLine", bl.Data.Category, CODEAlRDEXIST, errors.New("Budget line already exists under the project")}
+			return errorResponse(stub, cErr)
+		}
+	}
+
+	// allocation is in project's base currency
+	cErr = checkPrecision("putBudgetLine", bl.Data.Category, p.baseCurrency(), bl.Data.Allocated)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	p.Data.Budget = append(p.Data.Budget, bl.Data)
+	cErr = p.store(stub, "putBudgetLine")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	// Emit transaction event for listeners
+	cErr = emitEvent(stub, "putBudgetLine", bl.ProjectID, events.BudgetLineAdded, &events.BudgetLine{ProjectID: bl.ProjectID, Category: bl.Data.Category, Allocated: bl.Data.Allocated, Currency: p.baseCurrency()})
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	r := response{CODEALLAOK, bl.Data.Category, nil}
+	return shim.Success((r.formatResponse(stub)))
+}
+
+// Read allocated vs spent amount of project budget lines, all lines unless a category is requested
+func (bl *projectBudgetLine) getState(stub shim.ChaincodeStubInterface) pb.Response {
+
+	p := &project{ProjectID: bl.ProjectID}
+	cErr := p.load(stub, "readBudget")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	lines := []budgetUtilisation{}
+	for _, l := range p.Data.Budget {
+		if len(bl.Data.Category) != 0 && l.Category != bl.Data.Category {
+			continue
+		}
+		spent, cErr := categorySpent(stub, bl.ProjectID, l.Category)
+		if cErr != nil {
+			return errorResponse(stub, cErr)
+		}
+		lines = append(lines, budgetUtilisation{l.Category, l.Allocated, spent, l.Allocated.Sub(spent)})
+	}
+	if len(bl.Data.Category) != 0 && len(lines) == 0 {
+		cErr = &chainError{"readBudget", bl.Data.Category, CODENOTFOUND, errors.New("Budget line not found under the project")}
+		return errorResponse(stub, cErr)
+	}
+
+	b, err := json.Marshal(lines)
+	if err != nil {
+		cErr = &chainError{"readBudget", bl.ProjectID, CODEGENEXCEPTION, err}
+		return errorResponse(stub, cErr)
+	}
+	r := response{CODEALLAOK, p.baseCurrency(), b}
+	return shim.Success((r.formatResponse(stub)))
+}
+
+// checkBudget - spend of a project with a budget must be tagged with one of its categories and be in
+// base currency. Cumulative category spends can not exceed the allocated amount, held is the part of the
+// amount already held against the category by a voucher.
+func checkBudget(stub shim.ChaincodeStubInterface, fcn string, p *project, s *spend, held decimal.Decimal) *chainError {
+
+	if len(p.Data.Budget) == 0 {
+		if len(s.Category) != 0 {
+			return &chainError{fcn, s.TxnID, CODENOTALLWD, errors.New("Project defines no budget line")}
+		}
+		return nil
+	}
+	if len(s.Category) == 0 {
+		return &chainError{fcn, s.TxnID, CODEUNPROCESSABLEENTITY, errors.New("Budget category can not be empty")}
+	}
+	for _, l := range p.Data.Budget {
+		if l.Category != s.Category {
+			continue
+		}
+		if s.Data.Currency != p.baseCurrency() {
+			return &chainError{fcn, s.TxnID, CODENOTALLWD, errors.New("Spend under budget lines must be in base currency " + p.baseCurrency())}
+		}
+		spent, cErr := categorySpent(stub, p.ProjectID, l.Category)
+		if cErr != nil {
+			return cErr
+		}
+		if spent.Add(s.Data.Amount).Sub(held).GreaterThan(l.Allocated) {
+			return &chainError{fcn, s.TxnID, CODENOTALLWD, errors.New("Spend exceeds " + l.Category + " allocation of " + l.Allocated.String())}
+		}
+		return nil
+	}
+	return &chainError{fcn, s.TxnID, CODENOTFOUND, errors.New("Budget line " + s.Category + " not found under the project")}
+}
+
+// putCategorySpend - add indexkey for range query :- each spend, reversal & outstanding voucher is kept
+// under its budget line, reversals with a negative amount
+func putCategorySpend(stub shim.ChaincodeStubInterface, fcn string, projectID string, category string, txnID string, amount decimal.Decimal) *chainError {
+
+	indexKey, err := stub.CreateCompositeKey(CATIDX, []string{projectID, category, txnID, amount.String()})
+	if err != nil {
+		return &chainError{fcn, txnID, CODEGENEXCEPTION, err}
+	}
+	err = stub.PutState(indexKey, []byte{0x00})
+	if err != nil {
+		return &chainError{fcn, txnID, CODEGENEXCEPTION, err}
+	}
+	return nil
+}
+
+// delCategorySpend - remove the indexkey of a voucher held under its budget line, once the voucher is
+// redeemed or expires
+func delCategorySpend(stub shim.ChaincodeStubInterface, fcn string, projectID string, category string, txnID string, amount decimal.Decimal) *chainError {
+
+	indexKey, err := stub.CreateCompositeKey(CATIDX, []string{projectID, category, txnID, amount.String()})
+	if err != nil {
+		return &chainError{fcn, txnID, CODEGENEXCEPTION, err}
+	}
+	err = stub.DelState(indexKey)
+	if err != nil {
+		return &chainError{fcn, txnID, CODEGENEXCEPTION, err}
+	}
+	return nil
+}
+
+// categorySpent - sum of spends less reversals & outstanding vouchers under a budget line
+func categorySpent(stub shim.ChaincodeStubInterface, projectID string, category string) (decimal.Decimal, *chainError) {
+
+	spent, _ := decimal.NewFromString("0")
+	catItr, err := stub.GetStateByPartialCompositeKey(CATIDX, []string{projectID, category})
+	if err != nil {
+		return spent, &chainError{"categorySpent", projectID, CODEGENEXCEPTION, err}
+	}
+	//Close itrerator when done reading
+	defer catItr.Close()
+	for catItr.HasNext() {
+		rangeItem, err := catItr.Next()
+		if err != nil {
+			return spent, &chainError{"categorySpent", projectID, CODEGENEXCEPTION, err}
+		}
+		_, compositeKeyParts, err := stub.SplitCompositeKey(rangeItem.Key)
+		if err != nil {
+			return spent, &chainError{"categorySpent", projectID, CODEGENEXCEPTION, err}
+		}
+		// compositeKeyParts[3] represents transaction amount
+		txAmount, _ := decimal.NewFromString(compositeKeyParts[3])
+		spent = spent.Add(txAmount)
+	}
+	return spent, nil
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// Verifies scenarios related to project budget lines and category level spending
+func TestBudgetRW(t *testing.T) {
+	fmt.Println("Executing Test - BudgetRW")
+	defer os.Unsetenv("ROLE")
+	defer os.Unsetenv("CALLER")
+
+	// Test data - Refer to test narratives for test description
+	var budgetTable = []struct {
+		projectID      string
+		category       string
+		allocated      string
+		caller         string // project owner if empty
+		role           string
+		expectedStatus int32
+		testNarrative  string
+	}{
+		{"P101", "logistics", "300", "", "", 200, "Happy scenario"},
+		{"P101", "materials", "100", "intruder", "donor", 500, "Check for caller not running the project"},
+		{"P101", "staff", "200", "intruder", ROLEADMIN, 200, "Happy scenario - admin adds budget line of any project"},
+		{"", "materials", "100", "", "", 500, "Check for missing project ID"},
+		{"P101", "", "100", "", "", 500, "Check for missing category"},
+		{"P101", "materials", "0", "", "", 500, "Check for positive allocation"},
+		{"P101", "materials", "100.001", "", "", 500, "Check for allocation beyond base currency precision"},
+		{"P102", "materials", "100", "", "", 500, "Check for project ID existence"},
+		{"P101", "staff", "100", "", "", 500, "Check for duplicate category"},
+	}
+	// Spends & reversals are executed in order
+	var spendTable = []struct {
+		txnID          string
+		amount         string
+		category       string
+		reverse        string
+		expectedStatus int32
+		testNarrative  string
+	}{
+		{"S101", "100", "logistics", "", 200, "Happy scenario"},
+		{"S102", "50", "", "", 500, "Check for spend without category"},
+		{"S103", "50", "travel", "", 500, "Check for category existence"},
+		{"S104", "250", "logistics", "", 500, "Check for spend over category allocation"},
+		{"S105", "200", "staff", "", 200, "Spend up to category allocation"},
+		{"S106", "250", "logistics", "S101", 200, "Reversed spend is released to its category"},
+	}
+	// Expected utilisation per category
+	var utilisationTable = []struct {
+		category          string
+		expectedSpent     string
+		expectedRemaining string
+	}{
+		{"logistics", "250", "50"},
+		{"staff", "200", "0"},
+	}
+
+	// struct for parsing the shim APIs response
+	type bResp struct {
+		Code    string              `json:"code"`
+		Message string              `json:"message"`
+		Payload []budgetUtilisation `json:"payload"`
+	}
+	assert := assert.New(t)
+
+	// Instantiate mockStub using AidChaincode as the target chaincode to unit test
+	stub := shim.NewMockStub("TestStub", new(AidChaincode))
+	assert.NotNil(stub, "Stub is nil, Test stub creation failed")
+
+	uid := uuid.New().String()
+
+	// Add test project, item & donation to state.
+	result := stub.MockInvoke(uid,
+		[][]byte{[]byte(AddProject),
+			[]byte("P101"),
+			[]byte("Prj101")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Saving test project to state failed.")
+
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(AddItem),
+			[]byte("Itm001"),
+			[]byte("Item001"),
+			[]byte("Medicine")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Saving test item to state failed.")
+
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(AddDonation),
+			[]byte("D101"),
+			[]byte("vishal"),
+			[]byte("P101"),
+			[]byte("Itm001"),
+			[]byte("1000")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Saving test donation entry to state failed.")
+
+	// Executing budget line tests
+	for _, test := range budgetTable {
+		os.Setenv("CALLER", test.caller)
+		os.Setenv("ROLE", test.role)
+		result := stub.MockInvoke(uid,
+			[][]byte{[]byte(AddBudgetLine),
+				[]byte(test.projectID),
+				[]byte(test.category),
+				[]byte(test.allocated)})
+
+		assert.Equal(test.expectedStatus, result.GetStatus(), test.testNarrative+" failed - "+(result.GetMessage()))
+	}
+	os.Unsetenv("CALLER")
+	os.Unsetenv("ROLE")
+
+	// Refresh Available funds under Project
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(GetProject),
+			[]byte("P101")})
+	assert.EqualValues(shim.OK, result.GetStatus(), GetProject+" failed to read the project data")
+
+	// Executing spend tests
+	for _, test := range spendTable {
+		if len(test.reverse) != 0 {
+			result := stub.MockInvoke(uid,
+				[][]byte{[]byte(ReverseSpend),
+					[]byte(test.reverse),
+					[]byte("Recorded in error")})
+			assert.EqualValues(shim.OK, result.GetStatus(), test.testNarrative+" failed - "+(result.GetMessage()))
+		}
+
+		result := stub.MockInvoke(uid,
+			[][]byte{[]byte(AddSpend),
+				[]byte(test.txnID),
+				[]byte("vishal"),
+				[]byte("P101"),
+				[]byte("Itm001"),
+				[]byte(test.amount),
+				[]byte(""),
+				[]byte(test.category)})
+
+		assert.Equal(test.expectedStatus, result.GetStatus(), test.testNarrative+" failed - "+(result.GetMessage()))
+	}
+
+	// Verify allocated vs spent amount per category
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(GetBudgetUtilisation),
+			[]byte("P101")})
+	assert.EqualValues(shim.OK, result.GetStatus(), GetBudgetUtilisation+" failed to read the budget utilisation")
+
+	b := &bResp{}
+	err := json.Unmarshal(result.GetPayload(), b)
+	if err != nil {
+		panic(err)
+	}
+	assert.Len(b.Payload, len(utilisationTable), "Budget lines mismatch")
+	for i, test := range utilisationTable {
+		expectedSpent, _ := decimal.NewFromString(test.expectedSpent)
+		expectedRemaining, _ := decimal.NewFromString(test.expectedRemaining)
+		assert.Equal(test.category, b.Payload[i].Category, "Budget category mismatch")
+		assert.True(expectedSpent.Equal(b.Payload[i].Spent), test.category+" spent amount mismatch")
+		assert.True(expectedRemaining.Equal(b.Payload[i].Remaining), test.category+" remaining amount mismatch")
+	}
+
+	// Verify utilisation of a single category
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(GetBudgetUtilisation),
+			[]byte("P101"),
+			[]byte("travel")})
+	assert.EqualValues(shim.ERROR, result.GetStatus(), "Check for category existence failed")
+}
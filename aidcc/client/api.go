@@ -0,0 +1,479 @@
+package client
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Write requests. Optional fields are left empty to apply chaincode defaults e.g. amounts without
+// currency are in the project's base currency.
+
+// ProjectRequest adds a project
+type ProjectRequest struct {
+	ProjectID    string
+	ProjectName  string
+	BaseCurrency string          // optional
+	OverheadRate decimal.Decimal // percentage of each donation taken for overhead, requires OverheadPrj
+	OverheadPrj  string          // optional, project receiving the overhead
+}
+
+// ItemRequest adds an item
+type ItemRequest struct {
+	ItemID    string
+	ItemType  string
+	Narrative string
+}
+
+// DonationRequest adds a donation
+type DonationRequest struct {
+	TxnID     string
+	Donor     string
+	ProjectID string
+	ItemID    string
+	Amount    decimal.Decimal
+	PledgeID  string // optional, pledge fulfilled by the donation
+	Currency  string // optional
+}
+
+// SpendRequest adds a spend
+type SpendRequest struct {
+	TxnID       string
+	Beneficiary string
+	ProjectID   string
+	ItemID      string
+	Amount      decimal.Decimal
+	Currency    string // optional
+	Category    string // optional, budget line of the spend
+	VendorID    string // optional, vendor paid by the spend
+}
+
+// PledgeRequest adds a pledge
+type PledgeRequest struct {
+	PledgeID  string
+	Donor     string
+	ProjectID string
+	ItemID    string
+	Amount    decimal.Decimal
+	Currency  string // optional
+}
+
+// RecurringDonationRequest schedules a recurring donation
+type RecurringDonationRequest struct {
+	ScheduleID string
+	Donor      string
+	ProjectID  string
+	ItemID     string
+	Amount     decimal.Decimal // instalment amount
+	Interval   string          // WEEKLY, MONTHLY, QUARTERLY or YEARLY
+	StartDt    time.Time
+	EndDt      time.Time
+	Currency   string // optional
+}
+
+// InstalmentRequest records an instalment of a recurring donation
+type InstalmentRequest struct {
+	TxnID      string
+	ScheduleID string
+	Amount     decimal.Decimal
+	Period     int // optional, period of an overdue instalment. Defaults to the current period
+}
+
+// MatchingCampaignRequest adds a matching campaign
+type MatchingCampaignRequest struct {
+	CampaignID string
+	Sponsor    string
+	ProjectID  string
+	Ratio      decimal.Decimal
+	Cap        decimal.Decimal
+	StartDt    time.Time
+	EndDt      time.Time
+	Currency   string // optional
+}
+
+// MilestoneRequest adds a milestone to a project
+type MilestoneRequest struct {
+	ProjectID   string
+	MilestoneID string
+	Narrative   string
+	Budget      decimal.Decimal
+}
+
+// BudgetLineRequest adds a budget line to a project
+type BudgetLineRequest struct {
+	ProjectID string
+	Category  string
+	Allocated decimal.Decimal
+}
+
+// FXRateRequest publishes an FX rate
+type FXRateRequest struct {
+	Pair string // base/quote currency e.g. EUR/USD
+	Rate decimal.Decimal
+	AsOf time.Time
+}
+
+// InKindRequest adds an in-kind donation
+type InKindRequest struct {
+	TxnID     string
+	Donor     string
+	ProjectID string
+	ItemID    string
+	Quantity  decimal.Decimal
+	Unit      string
+	EstValue  decimal.Decimal
+	Currency  string // optional, currency of estimated value
+}
+
+// DistributionRequest distributes in-kind stock to a beneficiary
+type DistributionRequest struct {
+	TxnID       string
+	Beneficiary string
+	ProjectID   string
+	ItemID      string
+	Quantity    decimal.Decimal
+	Unit        string
+}
+
+// EvidenceRequest attaches evidence to a spend
+type EvidenceRequest struct {
+	TxnID        string
+	DocumentHash string // hex encoded SHA-256 of the document
+	URI          string
+	MediaType    string
+}
+
+// VoucherRequest issues a voucher
+type VoucherRequest struct {
+	VoucherID   string
+	Beneficiary string
+	ProjectID   string
+	ItemID      string
+	Amount      decimal.Decimal
+	ExpiryDt    time.Time
+	Currency    string // optional
+	Category    string // optional, budget line the voucher is spent under
+}
+
+// VendorRequest registers a vendor
+type VendorRequest struct {
+	VendorID       string
+	LegalName      string
+	RegistrationID string
+	BankRefHash    string // hex encoded SHA-256 of the bank reference
+}
+
+// AMLLimitsRequest sets AML donation limits, zero disables a limit
+type AMLLimitsRequest struct {
+	SingleLimit  decimal.Decimal
+	RollingLimit decimal.Decimal
+	AnonymousCap decimal.Decimal
+	Currency     string // optional
+}
+
+// AddProject - add a project, returns project ID
+func (c *Client) AddProject(r ProjectRequest) (string, error) {
+	overheadRate := ""
+	if len(r.OverheadPrj) != 0 {
+		overheadRate = r.OverheadRate.String()
+	}
+	return c.submit("AddProject", optional([]string{r.ProjectID, r.ProjectName}, r.BaseCurrency, overheadRate, r.OverheadPrj))
+}
+
+// AddItem - add an item, returns item ID
+func (c *Client) AddItem(r ItemRequest) (string, error) {
+	return c.submit("AddItem", []string{r.ItemID, r.ItemType, r.Narrative})
+}
+
+// AddDonation - add a donation, returns txn ID
+func (c *Client) AddDonation(r DonationRequest) (string, error) {
+	return c.submit("AddDonation", optional([]string{r.TxnID, r.Donor, r.ProjectID, r.ItemID, r.Amount.String()}, r.PledgeID, r.Currency))
+}
+
+// AddSpend - add a spend, returns txn ID
+func (c *Client) AddSpend(r SpendRequest) (string, error) {
+	return c.submit("AddSpend", optional([]string{r.TxnID, r.Beneficiary, r.ProjectID, r.ItemID, r.Amount.String()}, r.Currency, r.Category, r.VendorID))
+}
+
+// ReverseSpend - reverse a spend, returns txn ID of the reversal
+func (c *Client) ReverseSpend(txnID string, reason string) (string, error) {
+	return c.submit("ReverseSpend", []string{txnID, reason})
+}
+
+// AddPledge - add a pledge, returns pledge ID
+func (c *Client) AddPledge(r PledgeRequest) (string, error) {
+	return c.submit("AddPledge", optional([]string{r.PledgeID, r.Donor, r.ProjectID, r.ItemID, r.Amount.String()}, r.Currency))
+}
+
+// CancelPledge - cancel an open pledge, returns pledge ID
+func (c *Client) CancelPledge(pledgeID string) (string, error) {
+	return c.submit("CancelPledge", []string{pledgeID})
+}
+
+// AddRecurringDonation - schedule a recurring donation, returns schedule ID
+func (c *Client) AddRecurringDonation(r RecurringDonationRequest) (string, error) {
+	return c.submit("AddRecurringDonation", optional([]string{r.ScheduleID, r.Donor, r.ProjectID, r.ItemID, r.Amount.String(), r.Interval, date(r.StartDt), date(r.EndDt)}, r.Currency))
+}
+
+// RecordInstalment - record an instalment of a recurring donation, returns txn ID
+func (c *Client) RecordInstalment(r InstalmentRequest) (string, error) {
+	args := []string{r.TxnID, r.ScheduleID, r.Amount.String()}
+	if r.Period > 0 {
+		args = append(args, strconv.Itoa(r.Period))
+	}
+	return c.submit("RecordInstalment", args)
+}
+
+// AddMatchingCampaign - add a matching campaign, returns campaign ID
+func (c *Client) AddMatchingCampaign(r MatchingCampaignRequest) (string, error) {
+	return c.submit("AddMatchingCampaign", optional([]string{r.CampaignID, r.Sponsor, r.ProjectID, r.Ratio.String(), r.Cap.String(), date(r.StartDt), date(r.EndDt)}, r.Currency))
+}
+
+// AddMilestone - add a milestone to a project, returns milestone ID
+func (c *Client) AddMilestone(r MilestoneRequest) (string, error) {
+	return c.submit("AddMilestone", []string{r.ProjectID, r.MilestoneID, r.Narrative, r.Budget.String()})
+}
+
+// ApproveMilestone - approve a milestone of a project, returns milestone ID
+func (c *Client) ApproveMilestone(projectID string, milestoneID string) (string, error) {
+	return c.submit("ApproveMilestone", []string{projectID, milestoneID})
+}
+
+// SetFXRate - publish an FX rate, returns the rate key
+func (c *Client) SetFXRate(r FXRateRequest) (string, error) {
+	return c.submit("SetFXRate", []string{r.Pair, r.Rate.String(), date(r.AsOf)})
+}
+
+// AddBudgetLine - add a budget line to a project, returns the category
+func (c *Client) AddBudgetLine(r BudgetLineRequest) (string, error) {
+	return c.submit("AddBudgetLine", []string{r.ProjectID, r.Category, r.Allocated.String()})
+}
+
+// AddInKindDonation - add an in-kind donation, returns txn ID
+func (c *Client) AddInKindDonation(r InKindRequest) (string, error) {
+	return c.submit("AddInKindDonation", optional([]string{r.TxnID, r.Donor, r.ProjectID, r.ItemID, r.Quantity.String(), r.Unit, r.EstValue.String()}, r.Currency))
+}
+
+// DistributeInKind - distribute in-kind stock, returns txn ID
+func (c *Client) DistributeInKind(r DistributionRequest) (string, error) {
+	return c.submit("DistributeInKind", []string{r.TxnID, r.Beneficiary, r.ProjectID, r.ItemID, r.Quantity.String(), r.Unit})
+}
+
+// AddSpendEvidence - attach evidence to a spend, returns txn ID
+func (c *Client) AddSpendEvidence(r EvidenceRequest) (string, error) {
+	return c.submit("AddSpendEvidence", []string{r.TxnID, r.DocumentHash, r.URI, r.MediaType})
+}
+
+// IssueVoucher - issue a voucher, returns voucher ID
+func (c *Client) IssueVoucher(r VoucherRequest) (string, error) {
+	return c.submit("IssueVoucher", optional([]string{r.VoucherID, r.Beneficiary, r.ProjectID, r.ItemID, r.Amount.String(), date(r.ExpiryDt)}, r.Currency, r.Category))
+}
+
+// RedeemVoucher - redeem a voucher as the calling vendor, returns txn ID of the spend
+func (c *Client) RedeemVoucher(voucherID string) (string, error) {
+	return c.submit("RedeemVoucher", []string{voucherID})
+}
+
+// ExpireVoucher - expire a voucher past its expiry date, returns voucher ID
+func (c *Client) ExpireVoucher(voucherID string) (string, error) {
+	return c.submit("ExpireVoucher", []string{voucherID})
+}
+
+// AddVendor - register a vendor, returns vendor ID
+func (c *Client) AddVendor(r VendorRequest) (string, error) {
+	return c.submit("AddVendor", []string{r.VendorID, r.LegalName, r.RegistrationID, r.BankRefHash})
+}
+
+// SetVendorStatus - activate or suspend a vendor, returns vendor ID
+func (c *Client) SetVendorStatus(vendorID string, status string) (string, error) {
+	return c.submit("SetVendorStatus", []string{vendorID, status})
+}
+
+// AddBlockedParty - block a party by its hash, returns the party hash
+func (c *Client) AddBlockedParty(partyHash string, reason string) (string, error) {
+	return c.submit("AddBlockedParty", []string{partyHash, reason})
+}
+
+// RemoveBlockedParty - delist a blocked party, returns the party hash
+func (c *Client) RemoveBlockedParty(partyHash string) (string, error) {
+	return c.submit("RemoveBlockedParty", []string{partyHash})
+}
+
+// RecordScreening - record the screening of a party for a transaction rejected by sanctions screening,
+// returns the txn ID
+func (c *Client) RecordScreening(txnID string, party string, partyID string) (string, error) {
+	return c.submit("RecordScreening", []string{txnID, party, partyID})
+}
+
+// SetAMLLimits - set AML donation limits
+func (c *Client) SetAMLLimits(r AMLLimitsRequest) (string, error) {
+	return c.submit("SetAMLLimits", optional([]string{r.SingleLimit.String(), r.RollingLimit.String(), r.AnonymousCap.String()}, r.Currency))
+}
+
+// ApproveDonation - approve a donation held for AML review, returns txn ID
+func (c *Client) ApproveDonation(txnID string, remarks string) (string, error) {
+	return c.submit("ApproveDonation", optional([]string{txnID}, remarks))
+}
+
+// RejectDonation - reject a donation held for AML review, remarks are required. Returns txn ID
+func (c *Client) RejectDonation(txnID string, remarks string) (string, error) {
+	return c.submit("RejectDonation", []string{txnID, remarks})
+}
+
+// MigrateState - rewrite a batch of documents of the asset type to the latest schema version, starting
+// at the bookmark of the previous batch
+func (c *Client) MigrateState(assetType string, batchSize int, bookmark string) (*MigrationBatch, error) {
+	b := &MigrationBatch{}
+	return b, c.submitDecode("MigrateState", optional([]string{assetType, strconv.Itoa(batchSize)}, bookmark), b)
+}
+
+// GetProject - read a project, funds are converted to currency when provided
+func (c *Client) GetProject(projectID string, currency string) (*Project, error) {
+	p := &Project{}
+	return p, c.evaluate("GetProject", optional([]string{projectID}, currency), p)
+}
+
+// GetItem - read an item
+func (c *Client) GetItem(itemID string) (*Item, error) {
+	it := &Item{}
+	return it, c.evaluate("GetItem", []string{itemID}, it)
+}
+
+// GetDonation - read a donation
+func (c *Client) GetDonation(txnID string) (*Donation, error) {
+	d := &Donation{}
+	return d, c.evaluate("GetDonation", []string{txnID}, d)
+}
+
+// GetSpend - read a spend
+func (c *Client) GetSpend(txnID string) (*Spend, error) {
+	s := &Spend{}
+	return s, c.evaluate("GetSpend", []string{txnID}, s)
+}
+
+// GetReversal - read a reversal by its txn ID
+func (c *Client) GetReversal(txnID string) (*Reversal, error) {
+	r := &Reversal{}
+	return r, c.evaluate("GetReversal", []string{txnID}, r)
+}
+
+// GetPledge - read a pledge
+func (c *Client) GetPledge(pledgeID string) (*Pledge, error) {
+	pl := &Pledge{}
+	return pl, c.evaluate("GetPledge", []string{pledgeID}, pl)
+}
+
+// GetRecurringDonation - read a recurring donation schedule
+func (c *Client) GetRecurringDonation(scheduleID string) (*RecurringDonation, error) {
+	rd := &RecurringDonation{}
+	return rd, c.evaluate("GetRecurringDonation", []string{scheduleID}, rd)
+}
+
+// GetMissedInstalments - read missed instalments of a recurring donation schedule
+func (c *Client) GetMissedInstalments(scheduleID string) ([]Instalment, error) {
+	missed := []Instalment{}
+	return missed, c.evaluate("GetMissedInstalments", []string{scheduleID}, &missed)
+}
+
+// GetMatchingCampaign - read a matching campaign
+func (c *Client) GetMatchingCampaign(campaignID string) (*MatchingCampaign, error) {
+	m := &MatchingCampaign{}
+	return m, c.evaluate("GetMatchingCampaign", []string{campaignID}, m)
+}
+
+// GetMilestone - read a milestone of a project
+func (c *Client) GetMilestone(projectID string, milestoneID string) (*ProjectMilestone, error) {
+	pm := &ProjectMilestone{}
+	return pm, c.evaluate("GetMilestone", []string{projectID, milestoneID}, pm)
+}
+
+// GetFXRate - read the FX rate of a pair effective on the date
+func (c *Client) GetFXRate(pair string, asOf time.Time) (*FXRate, error) {
+	fx := &FXRate{}
+	return fx, c.evaluate("GetFXRate", []string{pair, date(asOf)}, fx)
+}
+
+// GetBudgetUtilisation - read utilisation of project budget lines, of the category when provided
+func (c *Client) GetBudgetUtilisation(projectID string, category string) ([]BudgetUtilisation, error) {
+	lines := []BudgetUtilisation{}
+	return lines, c.evaluate("GetBudgetUtilisation", optional([]string{projectID}, category), &lines)
+}
+
+// GetInKind - read an in-kind donation or distribution
+func (c *Client) GetInKind(txnID string) (*InKind, error) {
+	k := &InKind{}
+	return k, c.evaluate("GetInKind", []string{txnID}, k)
+}
+
+// GetInventory - read stock on hand of a project, of the item when provided
+func (c *Client) GetInventory(projectID string, itemID string) ([]StockLine, error) {
+	stock := []StockLine{}
+	return stock, c.evaluate("GetInventory", optional([]string{projectID}, itemID), &stock)
+}
+
+// VerifySpendEvidence - verify a document hash against the evidence of a spend
+func (c *Client) VerifySpendEvidence(txnID string, documentHash string) (*EvidenceCheck, error) {
+	check := &EvidenceCheck{}
+	return check, c.evaluate("VerifySpendEvidence", []string{txnID, documentHash}, check)
+}
+
+// GetUnevidencedSpends - read spends of a project lacking evidence
+func (c *Client) GetUnevidencedSpends(projectID string) ([]Spend, error) {
+	spends := []Spend{}
+	return spends, c.evaluate("GetUnevidencedSpends", []string{projectID}, &spends)
+}
+
+// GetVoucher - read a voucher
+func (c *Client) GetVoucher(voucherID string) (*Voucher, error) {
+	v := &Voucher{}
+	return v, c.evaluate("GetVoucher", []string{voucherID}, v)
+}
+
+// GetVendor - read a vendor
+func (c *Client) GetVendor(vendorID string) (*Vendor, error) {
+	vd := &Vendor{}
+	return vd, c.evaluate("GetVendor", []string{vendorID}, vd)
+}
+
+// GetVendorSpending - read amounts paid to a vendor per project & currency, of the project when provided
+func (c *Client) GetVendorSpending(vendorID string, projectID string) ([]VendorSpend, error) {
+	spending := []VendorSpend{}
+	return spending, c.evaluate("GetVendorSpending", optional([]string{vendorID}, projectID), &spending)
+}
+
+// GetBlockedParty - read a blocked party by its hash
+func (c *Client) GetBlockedParty(partyHash string) (*BlockedParty, error) {
+	bp := &BlockedParty{}
+	return bp, c.evaluate("GetBlockedParty", []string{partyHash}, bp)
+}
+
+// GetScreenings - read sanctions screenings of a transaction
+func (c *Client) GetScreenings(txnID string) ([]Screening, error) {
+	screenings := []Screening{}
+	return screenings, c.evaluate("GetScreenings", []string{txnID}, &screenings)
+}
+
+// GetAMLLimits - read AML donation limits
+func (c *Client) GetAMLLimits() (*AMLLimits, error) {
+	aml := &AMLLimits{}
+	return aml, c.evaluate("GetAMLLimits", []string{}, aml)
+}
+
+// GetReviewQueue - read donations held for AML review, of the project when provided
+func (c *Client) GetReviewQueue(projectID string) ([]Donation, error) {
+	queue := []Donation{}
+	return queue, c.evaluate("GetReviewQueue", optional([]string{}, projectID), &queue)
+}
+
+// GetErrorCatalogue - read every response code of the chaincode with its meaning
+func (c *Client) GetErrorCatalogue() ([]ErrorCode, error) {
+	codes := []ErrorCode{}
+	return codes, c.evaluate("GetErrorCatalogue", []string{}, &codes)
+}
+
+// ListFunctions - read every function of the chaincode with its arguments, role & read/write nature
+func (c *Client) ListFunctions() ([]Function, error) {
+	functions := []Function{}
+	return functions, c.evaluate("ListFunctions", []string{}, &functions)
+}
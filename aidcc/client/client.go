@@ -0,0 +1,146 @@
+// Package client is a Go client of AIDcc. It exposes typed requests & responses for every chaincode
+// function, decoupled from any Fabric SDK through the Transport interface. Services plug in a transport
+// over their SDK of choice, tests use MockTransport over shim.MockStub.
+package client
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// DATEFMT is the date format of dates passed to the chaincode
+const DATEFMT = "2006-01-02"
+
+// Transport submits a function with its arguments to the chaincode and returns the response payload.
+// Errors returned by the chaincode are returned as error with the chaincode's message, which carries
+// the response envelope of the failed invoke.
+type Transport interface {
+	Submit(function string, args []string) ([]byte, error)   // endorse and commit a write invoke
+	Evaluate(function string, args []string) ([]byte, error) // query, nothing is committed
+}
+
+// Client of the chaincode API
+type Client struct {
+	transport Transport
+}
+
+// New - client over the transport
+func New(t Transport) *Client {
+	return &Client{transport: t}
+}
+
+// Response is the envelope of every chaincode response, successful or not
+type Response struct {
+	Code      string          `json:"code"`
+	Message   string          `json:"message"` // asset key for writes, "OK" or asset key for reads
+	Payload   json.RawMessage `json:"payload"` // asset state for reads, null if none
+	TxID      string          `json:"txID"`
+	TimeStamp time.Time       `json:"timeStamp"`
+	Errors    []ErrorDetail   `json:"errors"` // empty on success
+}
+
+// ErrorDetail is a structured detail of a chaincode error
+type ErrorDetail struct {
+	Code     string `json:"code"`
+	Function string `json:"function"`
+	Key      string `json:"key,omitempty"`
+	Field    string `json:"field,omitempty"` // input field in error, for validation errors
+	Message  string `json:"message"`
+}
+
+// Error returned by the chaincode
+type Error struct {
+	Function string // chaincode function invoked
+	Code     string // chaincode error code e.g. P4001, empty if not reported
+	Message  string
+	TxID     string        // failed transaction, if reported
+	Details  []ErrorDetail // structured details, if reported
+}
+
+func (e *Error) Error() string {
+	if len(e.Code) == 0 {
+		return e.Function + ": " + e.Message
+	}
+	return e.Function + " " + e.Code + ": " + e.Message
+}
+
+// chainErr - wrap transport error as chaincode Error, decoding the envelope of the failed invoke if any
+func chainErr(function string, err error) *Error {
+	e := &Error{Function: function, Message: err.Error()}
+	r := &Response{}
+	if json.Unmarshal([]byte(e.Message), r) == nil && len(r.Code) != 0 {
+		e.Code, e.Message, e.TxID, e.Details = r.Code, r.Message, r.TxID, r.Errors
+	}
+	return e
+}
+
+// submit - submit a write invoke, returning the key reported by the chaincode
+func (c *Client) submit(function string, args []string) (string, error) {
+	r, err := c.call(c.transport.Submit, function, args)
+	if err != nil {
+		return "", err
+	}
+	return r.Message, nil
+}
+
+// evaluate - evaluate a query, decoding response payload into v
+func (c *Client) evaluate(function string, args []string, v interface{}) error {
+	r, err := c.call(c.transport.Evaluate, function, args)
+	if err != nil {
+		return err
+	}
+	return decode(function, r, v)
+}
+
+// submitDecode - submit a transaction, decoding response payload into v
+func (c *Client) submitDecode(function string, args []string, v interface{}) error {
+	r, err := c.call(c.transport.Submit, function, args)
+	if err != nil {
+		return err
+	}
+	return decode(function, r, v)
+}
+
+// decode - decode response payload into v
+func decode(function string, r *Response, v interface{}) error {
+	if len(r.Payload) == 0 || string(r.Payload) == "null" {
+		return &Error{Function: function, Message: "Response payload is empty"}
+	}
+	err := json.Unmarshal(r.Payload, v)
+	if err != nil {
+		return &Error{Function: function, Message: err.Error()}
+	}
+	return nil
+}
+
+// call - send the request over the transport and decode the response envelope
+func (c *Client) call(send func(string, []string) ([]byte, error), function string, args []string) (*Response, error) {
+	b, err := send(function, args)
+	if err != nil {
+		return nil, chainErr(function, err)
+	}
+	r := &Response{}
+	err = json.Unmarshal(b, r)
+	if err != nil {
+		return nil, &Error{Function: function, Message: err.Error()}
+	}
+	return r, nil
+}
+
+// optional - positional arguments with trailing empty optional arguments dropped
+func optional(args []string, opts ...string) []string {
+	n := len(opts)
+	for n > 0 && len(strings.TrimSpace(opts[n-1])) == 0 {
+		n--
+	}
+	return append(args, opts[:n]...)
+}
+
+// date - date argument, empty for zero time
+func date(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(DATEFMT)
+}
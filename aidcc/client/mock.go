@@ -0,0 +1,42 @@
+package client
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+)
+
+// MockTransport is a transport over shim.MockStub for unit tests. Each request is invoked as a new
+// transaction, queries are invoked as well since MockStub does not distinguish them.
+type MockTransport struct {
+	Stub *shim.MockStub
+}
+
+// NewMockTransport - transport invoking the chaincode of the stub
+func NewMockTransport(stub *shim.MockStub) *MockTransport {
+	return &MockTransport{Stub: stub}
+}
+
+// Submit - invoke the function as a new transaction
+func (m *MockTransport) Submit(function string, args []string) ([]byte, error) {
+	return m.invoke(function, args)
+}
+
+// Evaluate - invoke the function as a new transaction
+func (m *MockTransport) Evaluate(function string, args []string) ([]byte, error) {
+	return m.invoke(function, args)
+}
+
+func (m *MockTransport) invoke(function string, args []string) ([]byte, error) {
+	b := make([][]byte, 0, len(args)+1)
+	b = append(b, []byte(function))
+	for _, arg := range args {
+		b = append(b, []byte(arg))
+	}
+	result := m.Stub.MockInvoke(uuid.New().String(), b)
+	if result.GetStatus() != shim.OK {
+		return nil, errors.New(result.GetMessage())
+	}
+	return result.GetPayload(), nil
+}
@@ -0,0 +1,403 @@
+package client
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Asset models as returned by the chaincode read functions. Refer to the chaincode asset models for
+// the meaning of each field.
+
+// Project read by GetProject
+type Project struct {
+	DocType       string          `json:"docType"`
+	SchemaVersion int             `json:"schemaVersion"`
+	ProjectID     string          `json:"projectID"`
+	Data          ProjectBase     `json:"data"`
+	Converted     *ConvertedFunds `json:"converted,omitempty"` // funds converted to the requested currency, if any
+}
+
+// ProjectBase is the state of a project
+type ProjectBase struct {
+	ProjectName  string                 `json:"projectName"`
+	RunBy        string                 `json:"runBy"`
+	StartDt      time.Time              `json:"startDt"`
+	BaseCurrency string                 `json:"baseCurrency"`
+	OverheadRate decimal.Decimal        `json:"overheadRate"`
+	OverheadPrj  string                 `json:"overheadPrj,omitempty"`
+	AvlFund      decimal.Decimal        `json:"avlFund"`
+	SpentFund    decimal.Decimal        `json:"spentFund"`
+	PledgedFund  decimal.Decimal        `json:"pledgedFund"`
+	ReservedFund decimal.Decimal        `json:"reservedFund"`
+	Balances     map[string]FundBalance `json:"balances"`
+	Milestones   []Milestone            `json:"milestones,omitempty"`
+	Budget       []BudgetLine           `json:"budget,omitempty"`
+	Reports      map[string]FundReport  `json:"reports,omitempty"`
+}
+
+// FundBalance of a project in a currency
+type FundBalance struct {
+	AvlFund      decimal.Decimal `json:"avlFund"`
+	SpentFund    decimal.Decimal `json:"spentFund"`
+	PledgedFund  decimal.Decimal `json:"pledgedFund"`
+	ReservedFund decimal.Decimal `json:"reservedFund"`
+}
+
+// FundReport is the part of project funds in a reporting currency folded from settled months of history
+type FundReport struct {
+	Checkpoint string       `json:"checkpoint"`
+	Funds      FundBalance  `json:"funds"`
+	RatesUsed  []FXRateUsed `json:"ratesUsed"`
+}
+
+// ConvertedFunds are project funds converted to a reporting currency
+type ConvertedFunds struct {
+	Currency     string          `json:"currency"`
+	AvlFund      decimal.Decimal `json:"avlFund"`
+	SpentFund    decimal.Decimal `json:"spentFund"`
+	PledgedFund  decimal.Decimal `json:"pledgedFund"`
+	ReservedFund decimal.Decimal `json:"reservedFund"`
+	RatesUsed    []FXRateUsed    `json:"ratesUsed"`
+}
+
+// FXRateUsed while converting funds
+type FXRateUsed struct {
+	Pair     string          `json:"pair"`
+	AsOf     string          `json:"asOf"`
+	Rate     decimal.Decimal `json:"rate"`
+	Inverted bool            `json:"inverted,omitempty"`
+}
+
+// Item read by GetItem
+type Item struct {
+	DocType       string `json:"docType"`
+	SchemaVersion int    `json:"schemaVersion"`
+	ItemID        string `json:"itemID"`
+	Data          struct {
+		ItemType  string `json:"itemType"`
+		Narrative string `json:"narrative"`
+	} `json:"data"`
+}
+
+// TxnBase is the state shared by donations, spends and reversals
+type TxnBase struct {
+	ProjectID string          `json:"projectID"`
+	ItemID    string          `json:"itemID"`
+	Amount    decimal.Decimal `json:"amount"`
+	Currency  string          `json:"currency"`
+	TimeStamp time.Time       `json:"timeStamp"`
+}
+
+// Donation read by GetDonation
+type Donation struct {
+	DocType       string     `json:"docType"`
+	SchemaVersion int        `json:"schemaVersion"`
+	TxnID         string     `json:"txnID"`
+	Donor         string     `json:"donor"`
+	PledgeID      string     `json:"pledgeID,omitempty"`
+	ScheduleID    string     `json:"scheduleID,omitempty"`
+	Period        int        `json:"period,omitempty"`
+	CampaignID    string     `json:"campaignID,omitempty"`
+	MatchOf       string     `json:"matchOf,omitempty"`
+	MatchedBy     []string   `json:"matchedBy,omitempty"`
+	Overhead      *Overhead  `json:"overhead,omitempty"`
+	OverheadOf    string     `json:"overheadOf,omitempty"`
+	Status        string     `json:"status,omitempty"`
+	Review        *AMLReview `json:"review,omitempty"`
+	Data          TxnBase    `json:"data"`
+}
+
+// Overhead split of a donation
+type Overhead struct {
+	Rate        decimal.Decimal `json:"rate"`
+	ProgramAmt  decimal.Decimal `json:"programAmt"`
+	OverheadAmt decimal.Decimal `json:"overheadAmt"`
+	ProjectID   string          `json:"projectID"`
+	TxnID       string          `json:"txnID,omitempty"`
+}
+
+// AMLReview of a donation held over AML limits
+type AMLReview struct {
+	Reasons    []string  `json:"reasons"`
+	ReviewedBy string    `json:"reviewedBy,omitempty"`
+	ReviewedDt time.Time `json:"reviewedDt"`
+	Remarks    string    `json:"remarks,omitempty"`
+}
+
+// Spend read by GetSpend
+type Spend struct {
+	DocType       string     `json:"docType"`
+	SchemaVersion int        `json:"schemaVersion"`
+	TxnID         string     `json:"txnID"`
+	Beneficiary   string     `json:"donor"`
+	ReversedBy    string     `json:"reversedBy,omitempty"`
+	Category      string     `json:"category,omitempty"`
+	Evidence      []Evidence `json:"evidence,omitempty"`
+	VoucherID     string     `json:"voucherID,omitempty"`
+	VendorID      string     `json:"vendorID,omitempty"`
+	Data          TxnBase    `json:"data"`
+}
+
+// Reversal read by GetReversal
+type Reversal struct {
+	DocType       string  `json:"docType"`
+	SchemaVersion int     `json:"schemaVersion"`
+	TxnID         string  `json:"txnID"`
+	ReversalOf    string  `json:"reversalOf"`
+	Reason        string  `json:"reason"`
+	Data          TxnBase `json:"data"`
+}
+
+// Pledge read by GetPledge
+type Pledge struct {
+	DocType       string `json:"docType"`
+	SchemaVersion int    `json:"schemaVersion"`
+	PledgeID      string `json:"pledgeID"`
+	Donor         string `json:"donor"`
+	Data          struct {
+		ProjectID    string          `json:"projectID"`
+		ItemID       string          `json:"itemID"`
+		Amount       decimal.Decimal `json:"amount"`
+		Currency     string          `json:"currency"`
+		FulfilledAmt decimal.Decimal `json:"fulfilledAmt"`
+		Fulfilments  []string        `json:"fulfilments"`
+		Status       string          `json:"status"`
+		TimeStamp    time.Time       `json:"timeStamp"`
+	} `json:"data"`
+}
+
+// RecurringDonation read by GetRecurringDonation
+type RecurringDonation struct {
+	DocType       string `json:"docType"`
+	SchemaVersion int    `json:"schemaVersion"`
+	ScheduleID    string `json:"scheduleID"`
+	Donor         string `json:"donor"`
+	Data          struct {
+		ProjectID   string          `json:"projectID"`
+		ItemID      string          `json:"itemID"`
+		Amount      decimal.Decimal `json:"amount"`
+		Currency    string          `json:"currency"`
+		Interval    string          `json:"interval"`
+		StartDt     time.Time       `json:"startDt"`
+		EndDt       time.Time       `json:"endDt"`
+		Instalments []Instalment    `json:"instalments"`
+	} `json:"data"`
+}
+
+// Instalment of a recurring donation
+type Instalment struct {
+	Period int       `json:"period"`
+	DueDt  time.Time `json:"dueDt"`
+	TxnID  string    `json:"txnID,omitempty"`
+}
+
+// MatchingCampaign read by GetMatchingCampaign
+type MatchingCampaign struct {
+	DocType       string `json:"docType"`
+	SchemaVersion int    `json:"schemaVersion"`
+	CampaignID    string `json:"campaignID"`
+	Sponsor       string `json:"sponsor"`
+	Data          struct {
+		ProjectID    string          `json:"projectID"`
+		Ratio        decimal.Decimal `json:"ratio"`
+		Cap          decimal.Decimal `json:"cap"`
+		Currency     string          `json:"currency"`
+		MatchedAmt   decimal.Decimal `json:"matchedAmt"`
+		RemainingCap decimal.Decimal `json:"remainingCap"`
+		StartDt      time.Time       `json:"startDt"`
+		EndDt        time.Time       `json:"endDt"`
+	} `json:"data"`
+}
+
+// Milestone of a project
+type Milestone struct {
+	MilestoneID string          `json:"milestoneID"`
+	Narrative   string          `json:"narrative"`
+	Budget      decimal.Decimal `json:"budget"`
+	Approved    bool            `json:"approved"`
+	ApprovedBy  string          `json:"approvedBy,omitempty"`
+	ApprovedDt  time.Time       `json:"approvedDt"`
+}
+
+// ProjectMilestone read by GetMilestone
+type ProjectMilestone struct {
+	ProjectID string    `json:"projectID"`
+	Data      Milestone `json:"data"`
+}
+
+// BudgetLine of a project
+type BudgetLine struct {
+	Category  string          `json:"category"`
+	Allocated decimal.Decimal `json:"allocated"`
+}
+
+// BudgetUtilisation read by GetBudgetUtilisation
+type BudgetUtilisation struct {
+	Category  string          `json:"category"`
+	Allocated decimal.Decimal `json:"allocated"`
+	Spent     decimal.Decimal `json:"spent"`
+	Remaining decimal.Decimal `json:"remaining"`
+}
+
+// FXRate read by GetFXRate
+type FXRate struct {
+	DocType       string `json:"docType"`
+	SchemaVersion int    `json:"schemaVersion"`
+	Pair          string `json:"pair"`
+	Data          struct {
+		Rate  decimal.Decimal `json:"rate"`
+		AsOf  time.Time       `json:"asOf"`
+		SetBy string          `json:"setBy"`
+	} `json:"data"`
+}
+
+// InKindBase is the state shared by in-kind donations and distributions
+type InKindBase struct {
+	ProjectID string          `json:"projectID"`
+	ItemID    string          `json:"itemID"`
+	Quantity  decimal.Decimal `json:"quantity"`
+	Unit      string          `json:"unit"`
+	TimeStamp time.Time       `json:"timeStamp"`
+}
+
+// InKind read by GetInKind, either an in-kind donation or a distribution
+type InKind struct {
+	DocType       string          `json:"docType"`
+	SchemaVersion int             `json:"schemaVersion"`
+	TxnID         string          `json:"txnID"`
+	Donor         string          `json:"donor,omitempty"`
+	Beneficiary   string          `json:"beneficiary,omitempty"`
+	EstValue      decimal.Decimal `json:"estValue"`
+	Currency      string          `json:"currency,omitempty"`
+	Data          InKindBase      `json:"data"`
+}
+
+// StockLine read by GetInventory
+type StockLine struct {
+	ItemID   string          `json:"itemID"`
+	Unit     string          `json:"unit"`
+	Quantity decimal.Decimal `json:"quantity"`
+}
+
+// Evidence attached to a spend
+type Evidence struct {
+	DocumentHash string    `json:"documentHash"`
+	URI          string    `json:"uri"`
+	MediaType    string    `json:"mediaType"`
+	AddedBy      string    `json:"addedBy"`
+	TimeStamp    time.Time `json:"timeStamp"`
+}
+
+// EvidenceCheck read by VerifySpendEvidence
+type EvidenceCheck struct {
+	TxnID        string    `json:"txnID"`
+	DocumentHash string    `json:"documentHash"`
+	Verified     bool      `json:"verified"`
+	Evidence     *Evidence `json:"evidence,omitempty"`
+}
+
+// Voucher read by GetVoucher
+type Voucher struct {
+	DocType       string `json:"docType"`
+	SchemaVersion int    `json:"schemaVersion"`
+	VoucherID     string `json:"voucherID"`
+	Beneficiary   string `json:"beneficiary"`
+	Data          struct {
+		ProjectID  string          `json:"projectID"`
+		ItemID     string          `json:"itemID"`
+		Amount     decimal.Decimal `json:"amount"`
+		Currency   string          `json:"currency"`
+		Status     string          `json:"status"`
+		IssuedDt   time.Time       `json:"issuedDt"`
+		ExpiryDt   time.Time       `json:"expiryDt"`
+		RedeemedBy string          `json:"redeemedBy,omitempty"`
+		RedeemedDt time.Time       `json:"redeemedDt"`
+		SpendTxnID string          `json:"spendTxnID,omitempty"`
+	} `json:"data"`
+}
+
+// Vendor read by GetVendor
+type Vendor struct {
+	DocType       string `json:"docType"`
+	SchemaVersion int    `json:"schemaVersion"`
+	VendorID      string `json:"vendorID"`
+	Data          struct {
+		LegalName      string    `json:"legalName"`
+		RegistrationID string    `json:"registrationID"`
+		BankRefHash    string    `json:"bankRefHash"`
+		Status         string    `json:"status"`
+		UpdatedBy      string    `json:"updatedBy"`
+		UpdatedDt      time.Time `json:"updatedDt"`
+	} `json:"data"`
+}
+
+// VendorSpend read by GetVendorSpending
+type VendorSpend struct {
+	ProjectID string          `json:"projectID"`
+	Currency  string          `json:"currency"`
+	Amount    decimal.Decimal `json:"amount"`
+}
+
+// BlockedParty read by GetBlockedParty
+type BlockedParty struct {
+	DocType       string `json:"docType"`
+	SchemaVersion int    `json:"schemaVersion"`
+	PartyHash     string `json:"partyHash"`
+	Data          struct {
+		Reason    string    `json:"reason"`
+		Active    bool      `json:"active"`
+		UpdatedBy string    `json:"updatedBy"`
+		UpdatedDt time.Time `json:"updatedDt"`
+	} `json:"data"`
+}
+
+// Screening read by GetScreenings
+type Screening struct {
+	TxnID     string    `json:"txnID"`
+	Party     string    `json:"party"`
+	PartyHash string    `json:"partyHash"`
+	Matched   bool      `json:"matched"`
+	TimeStamp time.Time `json:"timeStamp"`
+}
+
+// AMLLimits read by GetAMLLimits
+type AMLLimits struct {
+	DocType       string `json:"docType"`
+	SchemaVersion int    `json:"schemaVersion"`
+	Data          struct {
+		SingleLimit  decimal.Decimal `json:"singleLimit"`
+		RollingLimit decimal.Decimal `json:"rollingLimit"`
+		AnonymousCap decimal.Decimal `json:"anonymousCap"`
+		Currency     string          `json:"currency"`
+		UpdatedBy    string          `json:"updatedBy"`
+		UpdatedDt    time.Time       `json:"updatedDt"`
+	} `json:"data"`
+}
+
+// ErrorCode read by GetErrorCatalogue
+type ErrorCode struct {
+	Code    string `json:"code"`
+	Name    string `json:"name"`
+	Meaning string `json:"meaning"`
+}
+
+// Function read by ListFunctions
+type Function struct {
+	Name  string `json:"name"`
+	Write bool   `json:"write"`          // false for queries, to be evaluated
+	Role  string `json:"role,omitempty"` // caller role required, if any
+	Args  []struct {
+		Name     string `json:"name"`
+		Kind     string `json:"kind"` // string, amount or date
+		Required bool   `json:"required"`
+	} `json:"args"`
+}
+
+// MigrationBatch returned by MigrateState
+type MigrationBatch struct {
+	AssetType string `json:"assetType"`
+	Scanned   int    `json:"scanned"`  // documents of the asset type in the batch
+	Migrated  int    `json:"migrated"` // documents rewritten to the latest schema version
+	Bookmark  string `json:"bookmark"` // pass to the next batch, empty once migration is complete
+}
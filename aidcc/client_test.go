@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/vishal3152/AIDChaincode/aidcc/client"
+)
+
+// Verifies scenarios related to the Go client over the mock transport
+func TestClientRW(t *testing.T) {
+	fmt.Println("Executing Test - ClientRW")
+
+	amount := func(s string) decimal.Decimal {
+		d, _ := decimal.NewFromString(s)
+		return d
+	}
+
+	// Test data - Refer to test narratives for test description. Requests are executed in order
+	var writeTable = []struct {
+		submit        func(c *client.Client) (string, error)
+		expectedKey   string
+		expectedCode  string
+		testNarrative string
+	}{
+		{func(c *client.Client) (string, error) {
+			return c.AddProject(client.ProjectRequest{ProjectID: "P101", ProjectName: "Prj101"})
+		}, "P101", "", "Happy scenario - project"},
+		{func(c *client.Client) (string, error) {
+			return c.AddItem(client.ItemRequest{ItemID: "Itm001", ItemType: "Item001", Narrative: "Medicine"})
+		}, "Itm001", "", "Happy scenario - item"},
+		{func(c *client.Client) (string, error) {
+			return c.AddDonation(client.DonationRequest{TxnID: "D101", Donor: "vishal", ProjectID: "P101", ItemID: "Itm001", Amount: amount("500")})
+		}, "D101", "", "Happy scenario - donation"},
+		{func(c *client.Client) (string, error) {
+			return c.AddDonation(client.DonationRequest{TxnID: "D101", Donor: "vishal", ProjectID: "P101", ItemID: "Itm001", Amount: amount("500")})
+		}, "", CODEAlRDEXIST, "Check for duplicate donation"},
+		{func(c *client.Client) (string, error) {
+			return c.AddDonation(client.DonationRequest{TxnID: "D102", Donor: "vishal", ProjectID: "P109", ItemID: "Itm001", Amount: amount("500")})
+		}, "", CODENOTFOUND, "Check for project existence"},
+		{func(c *client.Client) (string, error) {
+			_, err := c.GetProject("P101", "")
+			return "P101", err
+		}, "P101", "", "Refresh available funds"},
+		{func(c *client.Client) (string, error) {
+			return c.AddSpend(client.SpendRequest{TxnID: "S101", Beneficiary: "beneficiary", ProjectID: "P101", ItemID: "Itm001", Amount: amount("120.50")})
+		}, "S101", "", "Happy scenario - spend"},
+		{func(c *client.Client) (string, error) {
+			return c.ReverseSpend("S101", "")
+		}, "", CODEUNPROCESSABLEENTITY, "Check for missing reversal reason"},
+	}
+
+	assert := assert.New(t)
+
+	// Instantiate mockStub using AidChaincode as the target chaincode to unit test
+	stub := shim.NewMockStub("TestStub", new(AidChaincode))
+	assert.NotNil(stub, "Stub is nil, Test stub creation failed")
+	c := client.New(client.NewMockTransport(stub))
+
+	// Executing write tests
+	for _, test := range writeTable {
+		key, err := test.submit(c)
+		if len(test.expectedCode) == 0 {
+			assert.NoError(err, test.testNarrative+" failed")
+			assert.Equal(test.expectedKey, key, test.testNarrative+" failed - key mismatch")
+			continue
+		}
+		if assert.IsType(&client.Error{}, err, test.testNarrative+" failed - error expected") {
+			assert.Equal(test.expectedCode, err.(*client.Error).Code, test.testNarrative+" failed - error code mismatch")
+		}
+	}
+
+	// Verify typed reads
+	s, err := c.GetSpend("S101")
+	assert.NoError(err, GetSpend+" failed to read the spend")
+	assert.Equal("beneficiary", s.Beneficiary, "Spend beneficiary mismatch")
+	assert.True(amount("120.50").Equal(s.Data.Amount), "Spend amount mismatch")
+	assert.Equal(DEFCCY, s.Data.Currency, "Spend currency mismatch")
+
+	p, err := c.GetProject("P101", "")
+	assert.NoError(err, GetProject+" failed to read the project")
+	assert.Equal("Prj101", p.Data.ProjectName, "Project name mismatch")
+	assert.True(amount("379.50").Equal(p.Data.AvlFund), "Available fund mismatch")
+	assert.True(amount("120.50").Equal(p.Data.SpentFund), "Spent fund mismatch")
+
+	_, err = c.GetDonation("D109")
+	if assert.IsType(&client.Error{}, err, "Check for donation existence failed - error expected") {
+		assert.Equal(CODENOTFOUND, err.(*client.Error).Code, "Check for donation existence failed - error code mismatch")
+	}
+}
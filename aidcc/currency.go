@@ -0,0 +1,58 @@
+package main
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/shopspring/decimal"
+)
+
+// ISO 4217 currency codes accepted by AidChaincode along with their minor unit i.e. no of decimals
+// an amount in the currency can carry
+var currencyPrecision = map[string]int32{
+	"AED": 2, "AUD": 2, "BDT": 2, "BHD": 3, "BRL": 2, "CAD": 2, "CHF": 2, "CNY": 2,
+	"DKK": 2, "EGP": 2, "ETB": 2, "EUR": 2, "GBP": 2, "GHS": 2, "HKD": 2, "IDR": 2,
+	"ILS": 2, "INR": 2, "JOD": 3, "JPY": 0, "KES": 2, "KRW": 0, "KWD": 3, "LKR": 2,
+	"MXN": 2, "MYR": 2, "NGN": 2, "NOK": 2, "NPR": 2, "NZD": 2, "OMR": 3, "PHP": 2,
+	"PKR": 2, "QAR": 2, "SAR": 2, "SEK": 2, "SGD": 2, "THB": 2, "TND": 3, "TRY": 2,
+	"TZS": 2, "UGX": 0, "USD": 2, "VND": 0, "XAF": 0, "XOF": 0, "ZAR": 2,
+}
+
+// precisionOf - no of decimals for a currency, false if currency code is not supported
+func precisionOf(currency string) (int32, bool) {
+	precision, ok := currencyPrecision[currency]
+	return precision, ok
+}
+
+// resolveCurrency - currency of an amount defaults to the base currency of the affiliated project.
+// Amount can not have more decimals than the resolved currency allows.
+func resolveCurrency(stub shim.ChaincodeStubInterface, fcn string, key string, projectID string, currency string, amount decimal.Decimal) (string, decimal.Decimal, *chainError) {
+
+	if len(currency) == 0 {
+		p := &project{ProjectID: projectID}
+		cErr := p.load(stub, fcn)
+		if cErr != nil {
+			return "", amount, cErr
+		}
+		currency = p.baseCurrency()
+	}
+	if _, ok := precisionOf(currency); !ok {
+		return "", amount, &chainError{fcn, key, CODEUNPROCESSABLEENTITY, errors.New("Unsupported currency " + currency)}
+	}
+	cErr := checkPrecision(fcn, key, currency, amount)
+	if cErr != nil {
+		return "", amount, cErr
+	}
+	return currency, amount, nil
+}
+
+// checkPrecision - amount can not have more decimals than the minor unit of its currency
+func checkPrecision(fcn string, key string, currency string, amount decimal.Decimal) *chainError {
+
+	precision, _ := precisionOf(currency)
+	if !amount.Equal(amount.Truncate(precision)) {
+		return &chainError{fcn, key, CODEAMTPRECISION, errors.New("Amount can not have more than " + strconv.Itoa(int(precision)) + " decimals in " + currency)}
+	}
+	return nil
+}
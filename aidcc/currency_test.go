@@ -0,0 +1,172 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// Verifies scenarios related to multi-currency donations & spends
+func TestCurrencyRW(t *testing.T) {
+	fmt.Println("Executing Test - CurrencyRW")
+
+	// Test data - Refer to test narratives for test description
+	var donationTable = []struct {
+		txnID          string
+		amount         string
+		currency       string
+		expectedAmount string
+		expectedStatus int32
+		expectedCode   string
+		testNarrative  string
+	}{
+		{"D101", "100", "", "100", 200, CODEALLAOK, "Happy scenario - defaults to project base currency"},
+		{"D102", "50.005", "USD", "", 500, CODEAMTPRECISION, "Check for more than 2 decimals in USD"},
+		{"D102", "50.00", "USD", "50", 200, CODEALLAOK, "Happy scenario - trailing zeros within 2 decimals"},
+		{"D103", "1000.4", "JPY", "", 500, CODEAMTPRECISION, "Check for decimals in JPY"},
+		{"D103", "1000", "JPY", "1000", 200, CODEALLAOK, "Happy scenario - whole amount in JPY"},
+		{"D104", "10", "ABC", "", 500, CODEUNPROCESSABLEENTITY, "Check for unsupported currency"},
+	}
+	// Spends are executed in order
+	var spendTable = []struct {
+		txnID          string
+		amount         string
+		currency       string
+		expectedStatus int32
+		testNarrative  string
+	}{
+		{"S101", "20", "USD", 200, "Happy scenario - spend in non base currency"},
+		{"S102", "10", "GBP", 500, "Check for mixed currency spend"},
+		{"S103", "60", "USD", 500, "Check for overwithdrawal in spend currency"},
+		{"S104", "30", "", 200, "Happy scenario - spend in base currency"},
+	}
+
+	// struct for parsing the shim APIs response
+	type pResp struct {
+		Code    string  `json:"code"`
+		Message string  `json:"message"`
+		Payload project `json:"payload"`
+	}
+	type eResp struct {
+		Code string `json:"code"`
+	}
+	type dResp struct {
+		Code    string   `json:"code"`
+		Message string   `json:"message"`
+		Payload donation `json:"payload"`
+	}
+	assert := assert.New(t)
+
+	// Instantiate mockStub using AidChaincode as the target chaincode to unit test
+	stub := shim.NewMockStub("TestStub", new(AidChaincode))
+	assert.NotNil(stub, "Stub is nil, Test stub creation failed")
+
+	uid := uuid.New().String()
+
+	// Add test project with EUR as base currency and item ID i.e. Itm001 to state.
+	result := stub.MockInvoke(uid,
+		[][]byte{[]byte(AddProject),
+			[]byte("P101"),
+			[]byte("Prj101"),
+			[]byte("EUR")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Saving test project to state failed.")
+
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(AddProject),
+			[]byte("P102"),
+			[]byte("Prj102"),
+			[]byte("XYZ")})
+	assert.EqualValues(shim.ERROR, result.GetStatus(), "Check for unsupported base currency failed.")
+
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(AddItem),
+			[]byte("Itm001"),
+			[]byte("Item001"),
+			[]byte("Medicine")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Saving test item to state failed.")
+
+	// Executing donation tests
+	for _, test := range donationTable {
+		result := stub.MockInvoke(uid,
+			[][]byte{[]byte(AddDonation),
+				[]byte(test.txnID),
+				[]byte("vishal"),
+				[]byte("P101"),
+				[]byte("Itm001"),
+				[]byte(test.amount),
+				[]byte(""),
+				[]byte(test.currency)})
+
+		assert.Equal(test.expectedStatus, result.GetStatus(), test.testNarrative+" failed - "+(result.GetMessage()))
+		e := &eResp{}
+		err := json.Unmarshal(result.GetPayload(), e)
+		if err != nil {
+			panic(err)
+		}
+		assert.Equal(test.expectedCode, e.Code, test.testNarrative+" failed - error code mismatch")
+
+		if result.GetStatus() == shim.OK {
+			// verify donation is recorded in resolved currency & precision
+			result = stub.MockInvoke(uid,
+				[][]byte{[]byte(GetDonation),
+					[]byte(test.txnID)})
+			assert.EqualValues(shim.OK, result.GetStatus(), GetDonation+" failed to read the donation data")
+
+			d := &dResp{}
+			err := json.Unmarshal(result.GetPayload(), d)
+			if err != nil {
+				panic(err)
+			}
+			expectedAmount, _ := decimal.NewFromString(test.expectedAmount)
+			assert.True(expectedAmount.Equal(d.Payload.Data.Amount), test.testNarrative+" failed - amount mismatch")
+			assert.NotEmpty(d.Payload.Data.Currency, test.testNarrative+" failed - currency not resolved")
+		}
+	}
+
+	// Refresh Available funds under Project
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(GetProject),
+			[]byte("P101")})
+	assert.EqualValues(shim.OK, result.GetStatus(), GetProject+" failed to read the project data")
+
+	// Executing spend tests
+	for _, test := range spendTable {
+		result := stub.MockInvoke(uid,
+			[][]byte{[]byte(AddSpend),
+				[]byte(test.txnID),
+				[]byte("vishal"),
+				[]byte("P101"),
+				[]byte("Itm001"),
+				[]byte(test.amount),
+				[]byte(test.currency)})
+
+		assert.Equal(test.expectedStatus, result.GetStatus(), test.testNarrative+" failed - "+(result.GetMessage()))
+	}
+
+	// Verify funds are held per currency, base currency balance is reported at top level
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(GetProject),
+			[]byte("P101")})
+	assert.EqualValues(shim.OK, result.GetStatus(), GetProject+" failed to read the project data")
+
+	p := &pResp{}
+	err := json.Unmarshal(result.GetPayload(), p)
+	if err != nil {
+		panic(err)
+	}
+	var expectedBalances = map[string][2]string{"EUR": {"70", "30"}, "USD": {"30", "20"}, "JPY": {"1000", "0"}}
+	assert.Len(p.Payload.Data.Balances, len(expectedBalances), "Project balance currencies mismatch")
+	for currency, expected := range expectedBalances {
+		avlFund, _ := decimal.NewFromString(expected[0])
+		spentFund, _ := decimal.NewFromString(expected[1])
+		assert.True(avlFund.Equal(p.Payload.Data.Balances[currency].AvlFund), currency+" available fund mismatch")
+		assert.True(spentFund.Equal(p.Payload.Data.Balances[currency].SpentFund), currency+" spent fund mismatch")
+	}
+	assert.Equal("EUR", p.Payload.Data.BaseCurrency, "Project base currency mismatch")
+	assert.True(p.Payload.Data.Balances["EUR"].AvlFund.Equal(p.Payload.Data.AvlFund), "Base currency fund not reported at top level")
+}
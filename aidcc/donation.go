@@ -1,13 +1,13 @@
 package main
 
 import (
-	"encoding/json"
 	"errors"
 	"time"
 
 	"github.com/hyperledger/fabric/core/chaincode/shim"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/shopspring/decimal"
+	"github.com/vishal3152/AIDChaincode/aidcc/events"
 )
 
 // Asset model for donation. All asset models are kept in
@@ -16,14 +16,64 @@ type donationBase struct {
 	ProjectID string          `json:"projectID"`
 	ItemID    string          `json:"itemID"` //ItemID
 	Amount    decimal.Decimal `json:"amount"`
+	Currency  string          `json:"currency"` // ISO 4217 code
 	TimeStamp time.Time       `json:"timeStamp"`
 }
 
 type donation struct {
 	ObjectType string       `json:"docType"` // donation Type 'DONIN'
-	TxnID      string       `json:"txnID"`   // asset unique key
+	versioned               // schema version of the document
+	TxnID      string       `json:"txnID"` // asset unique key
 	Donor      string       `json:"donor"`
-	Data       donationBase `json:"data"` // composition
+	PledgeID   string       `json:"pledgeID,omitempty"`   // pledge fulfilled by the donation, if any
+	ScheduleID string       `json:"scheduleID,omitempty"` // recurring donation schedule of the instalment, if any
+	Period     int          `json:"period,omitempty"`     // schedule period of the instalment
+	CampaignID string       `json:"campaignID,omitempty"` // matching campaign of a matched donation
+	MatchOf    string       `json:"matchOf,omitempty"`    // txnID of the donation matched by a sponsor
+	MatchedBy  []string     `json:"matchedBy,omitempty"`  // txnIDs of sponsor donations matching this donation
+	Overhead   *overhead    `json:"overhead,omitempty"`   // split of the donation into program funds & overhead
+	OverheadOf string       `json:"overheadOf,omitempty"` // txnID of the donation the overhead is taken from
+	Status     string       `json:"status,omitempty"`     // REVIEW, APPROVED or REJECTED when over AML limits
+	Review     *amlReview   `json:"review,omitempty"`     // AML review of the donation, if any
+	Data       donationBase `json:"data"`                 // composition
+}
+
+// key - ledger key of the donation
+func (d *donation) key() string {
+	return d.TxnID
+}
+
+// docType - doc type of donation assets
+func (d *donation) docType() string {
+	return DONIN
+}
+
+// validate - check donation invariants before it is written
+func (d *donation) validate() *chainError {
+
+	cErr := checkIdentity(d, d.ObjectType)
+	if cErr != nil {
+		return cErr
+	}
+	if d.Data.Amount.Sign() <= 0 {
+		return &chainError{"validate", d.key(), CODEUNPROCESSABLEENTITY, errors.New("Amount must be positive")}
+	}
+	return nil
+}
+
+// overhead - donation amount taken for overhead at the project's overhead rate. Remaining
+// program amount is available to the project
+type overhead struct {
+	Rate        decimal.Decimal `json:"rate"`
+	ProgramAmt  decimal.Decimal `json:"programAmt"`
+	OverheadAmt decimal.Decimal `json:"overheadAmt"`
+	ProjectID   string          `json:"projectID"`       // overhead project
+	TxnID       string          `json:"txnID,omitempty"` // donation recorded under overhead project
+}
+
+// overheadKey - derive overhead donation key from the original donation txnID
+func overheadKey(txnID string) string {
+	return txnID + "_OVH"
 }
 
 // Write donation to ledger
@@ -32,67 +82,75 @@ func (d *donation) putState(stub shim.ChaincodeStubInterface) pb.Response {
 	// check if affiliated project exists
 	c, cErr := checkAsset(stub, d.Data.ProjectID)
 	if cErr != nil {
-		return shim.Error(cErr.Error())
+		return errorResponse(stub, cErr)
 	}
 	if !c {
 		e := &chainError{"putDonation", d.TxnID, CODENOTFOUND, errors.New("Affiliated project not found")}
-		return shim.Error(e.Error())
+		return errorResponse(stub, e)
+	}
+
+	// resolve donation currency, defaults to project's base currency
+	d.Data.Currency, d.Data.Amount, cErr = resolveCurrency(stub, "putDonation", d.TxnID, d.Data.ProjectID, d.Data.Currency, d.Data.Amount)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
 	}
 
 	// check if affiliated item exists
 	c, cErr = checkAsset(stub, d.Data.ItemID)
 	if cErr != nil {
-		return shim.Error(cErr.Error())
+		return errorResponse(stub, cErr)
 	} else if !c {
 		e := &chainError{"putDonation", d.TxnID, CODENOTFOUND, errors.New("Affiliated item not found")}
-		return shim.Error(e.Error())
+		return errorResponse(stub, e)
 	}
 
 	// check if txnID is unique
 	c, cErr = checkAsset(stub, d.TxnID)
 	if cErr != nil {
-		return shim.Error(cErr.Error())
+		return errorResponse(stub, cErr)
 	} else if c {
 		e := &chainError{"putDonation", d.TxnID, CODEAlRDEXIST, errors.New("Asset with key already exists")}
-		return shim.Error(e.Error())
+		return errorResponse(stub, e)
 	}
 
-	// Marshal the donation struct to []byte
-	b, err := json.Marshal(d)
-	if err != nil {
-		cErr = &chainError{"putDonation", d.TxnID, CODEGENEXCEPTION, err}
-		return shim.Error(cErr.Error())
+	// check if donor is not a sanctioned party
+	cErr = screenParty(stub, "putDonation", d.TxnID, "donor", d.Donor)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
 	}
 
-	// Write key value to ledger
-	err = stub.PutState(d.TxnID, b)
-
-	if err != nil {
-		cErr = &chainError{"putDonation", d.TxnID, CODEGENEXCEPTION, err}
-		return shim.Error(cErr.Error())
+	// check if donation is within AML limits, donation over any limit is parked for review
+	reasons, cErr := d.admit(stub, "putDonation")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
 	}
-
-	// Add indexkey for range query :- each donation  is stored in form of a delta and aggregated whenever
-	// project state is read
-	indexName := INDXNM
-	indexKey, err := stub.CreateCompositeKey(indexName, []string{"1", d.TxnID, d.Data.Amount.StringFixedBank(int32(FIXEDPT))})
-	if err != nil {
-		cErr = &chainError{"putDonation", d.TxnID, CODEGENEXCEPTION, err}
-		return shim.Error(cErr.Error())
+	if len(reasons) != 0 {
+		cErr = d.park(stub, reasons)
+		if cErr != nil {
+			return errorResponse(stub, cErr)
+		}
+		// Emit transaction event for listeners
+		cErr = emitEvent(stub, "putDonation", d.TxnID, events.DonationHeldForReview, d.event())
+		if cErr != nil {
+			return errorResponse(stub, cErr)
+		}
+		r := response{CODEALLAOK, d.TxnID, nil}
+		return shim.Success((r.formatResponse(stub)))
 	}
-	value := []byte{0x00}
 
-	err = stub.PutState(indexKey, value)
-	if err != nil {
-		cErr = &chainError{"putDonation", d.TxnID, CODEGENEXCEPTION, err}
-		return shim.Error(cErr.Error())
+	cErr = d.record(stub, "putDonation")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
 	}
 
 	// Emit transaction event for listeners
-	stub.SetEvent((d.TxnID + "_AID_DON_" + d.Data.Amount.StringFixed(int32(FIXEDPT))), nil)
+	cErr = emitEvent(stub, "putDonation", d.TxnID, events.DonationRecorded, d.event())
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
 
 	r := response{CODEALLAOK, d.TxnID, nil}
-	return shim.Success((r.formatResponse()))
+	return shim.Success((r.formatResponse(stub)))
 }
 
 // Read donation state from the ledger
@@ -100,8 +158,119 @@ func (d *donation) getState(stub shim.ChaincodeStubInterface) pb.Response {
 
 	donation, cErr := queryAsset(stub, d.TxnID)
 	if cErr != nil {
-		return shim.Error(cErr.Error())
+		return errorResponse(stub, cErr)
 	}
 	r := response{CODEALLAOK, "OK", donation}
-	return shim.Success((r.formatResponse()))
+	return shim.Success((r.formatResponse(stub)))
+}
+
+// record - record donation under project funds, fulfilling its pledge and applying overhead & matching
+func (d *donation) record(stub shim.ChaincodeStubInterface, fcn string) *chainError {
+
+	// Track fulfilment if donation is made against a pledge
+	if len(d.PledgeID) != 0 {
+		pl := &pledge{PledgeID: d.PledgeID}
+		cErr := pl.fulfil(stub, d)
+		if cErr != nil {
+			return cErr
+		}
+	}
+
+	// Overhead is taken from the donation when project defines an overhead rate
+	cErr := d.splitOverhead(stub)
+	if cErr != nil {
+		return cErr
+	}
+
+	// Sponsors matching donations to the project write a linked donation in the same transaction
+	cErr = applyMatching(stub, d)
+	if cErr != nil {
+		return cErr
+	}
+
+	return d.write(stub, fcn)
+}
+
+// write - write donation to ledger along with its delta under project funds
+func (d *donation) write(stub shim.ChaincodeStubInterface, fcn string) *chainError {
+
+	cErr := d.store(stub, fcn)
+	if cErr != nil {
+		return cErr
+	}
+
+	// Add indexkey for range query :- each donation  is stored in form of a delta and aggregated whenever
+	// project state is read
+	return putDelta(stub, fcn, d.Data.ProjectID, "1", d.Data.Currency, d.TxnID, d.programAmt())
+}
+
+// store - write donation state to the ledger
+func (d *donation) store(stub shim.ChaincodeStubInterface, fcn string) *chainError {
+	return newRepository(stub, fcn).save(d)
+}
+
+// event - event payload of the donation
+func (d *donation) event() *events.Donation {
+	e := &events.Donation{TxnID: d.TxnID, ProjectID: d.Data.ProjectID, ItemID: d.Data.ItemID, Donor: d.Donor, Amount: d.Data.Amount,
+		Currency: d.Data.Currency, ProgramAmt: d.programAmt(), PledgeID: d.PledgeID, ScheduleID: d.ScheduleID, CampaignID: d.CampaignID,
+		MatchOf: d.MatchOf, OverheadOf: d.OverheadOf, MatchedBy: d.MatchedBy}
+	if d.Overhead != nil {
+		e.OverheadAmt = d.Overhead.OverheadAmt
+	}
+	if d.Review != nil {
+		e.Reasons = d.Review.Reasons
+	}
+	return e
+}
+
+// programAmt - donation amount available to the project i.e. net of overhead
+func (d *donation) programAmt() decimal.Decimal {
+	if d.Overhead == nil {
+		return d.Data.Amount
+	}
+	return d.Overhead.ProgramAmt
+}
+
+// splitOverhead - split the donation into program funds & overhead at the project's overhead rate. Overhead
+// is recorded as a linked donation under the designated overhead project
+func (d *donation) splitOverhead(stub shim.ChaincodeStubInterface) *chainError {
+
+	p := &project{ProjectID: d.Data.ProjectID}
+	cErr := p.load(stub, "putDonation")
+	if cErr != nil {
+		return cErr
+	}
+	if !p.Data.OverheadRate.IsPositive() {
+		return nil
+	}
+
+	hundred, _ := decimal.NewFromString("100")
+	precision, _ := precisionOf(d.Data.Currency)
+	amount := d.Data.Amount.Mul(p.Data.OverheadRate).Div(hundred).RoundBank(precision)
+	d.Overhead = &overhead{Rate: p.Data.OverheadRate, ProgramAmt: d.Data.Amount.Sub(amount), OverheadAmt: amount, ProjectID: p.Data.OverheadPrj}
+	if !amount.IsPositive() {
+		return nil
+	}
+
+	// Overhead is part of the donation, which is evaluated against AML limits & added to its donor's history
+	// in full before it is split. Overhead donation is written only once the donation is recorded, hence it
+	// is not evaluated or counted again
+
+	// check if overhead donation txnID is unique
+	ovhTxnID := overheadKey(d.TxnID)
+	c, cErr := checkAsset(stub, ovhTxnID)
+	if cErr != nil {
+		return cErr
+	} else if c {
+		return &chainError{"putDonation", ovhTxnID, CODEAlRDEXIST, errors.New("Asset with key already exists")}
+	}
+
+	d.Overhead.TxnID = ovhTxnID
+	ovhBase := donationBase{ProjectID: p.Data.OverheadPrj, ItemID: d.Data.ItemID, Amount: amount, Currency: d.Data.Currency, TimeStamp: d.Data.TimeStamp}
+	od := &donation{ObjectType: DONIN, TxnID: ovhTxnID, Donor: d.Donor, OverheadOf: d.TxnID, Data: ovhBase}
+	cErr = od.write(stub, "putDonation")
+	if cErr != nil {
+		return cErr
+	}
+	return emitEvent(stub, "putDonation", ovhTxnID, events.DonationRecorded, od.event())
 }
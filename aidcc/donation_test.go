@@ -122,3 +122,150 @@ func TestDonationRW(t *testing.T) {
 		}
 	}
 }
+
+// Verifies scenarios related to overhead taken from donations
+func TestDonationOverhead(t *testing.T) {
+	fmt.Println("Executing Test - DonationOverhead")
+
+	// Test data - Refer to test narratives for test description
+	var projectTable = []struct {
+		projectID      string
+		rate           string
+		overheadPrj    string
+		expectedStatus int32
+		testNarrative  string
+	}{
+		{"P101", "10", "P100", 200, "Happy scenario"},
+		{"P102", "0", "P100", 500, "Check for positive overhead rate"},
+		{"P102", "100", "P100", 500, "Check for overhead rate below 100 percent"},
+		{"P102", "10", "", 500, "Check for missing overhead project"},
+		{"P102", "10", "P102", 500, "Check for overhead project being the project itself"},
+		{"P102", "10", "P103", 500, "Check for overhead project existence"},
+	}
+	// Donations are executed in order against project P101
+	var donationTable = []struct {
+		txnID            string
+		amount           string
+		expectedProgram  string
+		expectedOverhead string
+		testNarrative    string
+	}{
+		{"D101", "100", "90", "10", "Overhead taken at project rate"},
+		{"D102", "0.05", "0.05", "0", "Overhead rounded off to zero"},
+	}
+
+	// struct for parsing the shim APIs response
+	type dResp struct {
+		Code    string   `json:"code"`
+		Message string   `json:"message"`
+		Payload donation `json:"payload"`
+	}
+	type pResp struct {
+		Code    string  `json:"code"`
+		Message string  `json:"message"`
+		Payload project `json:"payload"`
+	}
+	assert := assert.New(t)
+
+	// Instantiate mockStub using AidChaincode as the target chaincode to unit test
+	stub := shim.NewMockStub("TestStub", new(AidChaincode))
+	assert.NotNil(stub, "Stub is nil, Test stub creation failed")
+
+	uid := uuid.New().String()
+
+	// Add overhead project i.e. P100 and item ID i.e. Itm001 to state.
+	result := stub.MockInvoke(uid,
+		[][]byte{[]byte(AddProject),
+			[]byte("P100"),
+			[]byte("Overhead")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Saving overhead project to state failed.")
+
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(AddItem),
+			[]byte("Itm001"),
+			[]byte("Item001"),
+			[]byte("Medicine")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Saving test item to state failed.")
+
+	// Executing project tests
+	for _, test := range projectTable {
+		result := stub.MockInvoke(uid,
+			[][]byte{[]byte(AddProject),
+				[]byte(test.projectID),
+				[]byte("Prj"),
+				[]byte(""),
+				[]byte(test.rate),
+				[]byte(test.overheadPrj)})
+
+		assert.Equal(test.expectedStatus, result.GetStatus(), test.testNarrative+" failed - "+(result.GetMessage()))
+	}
+
+	// Executing donation tests
+	for _, test := range donationTable {
+		result := stub.MockInvoke(uid,
+			[][]byte{[]byte(AddDonation),
+				[]byte(test.txnID),
+				[]byte("vishal"),
+				[]byte("P101"),
+				[]byte("Itm001"),
+				[]byte(test.amount)})
+		assert.EqualValues(shim.OK, result.GetStatus(), test.testNarrative+" failed - "+(result.GetMessage()))
+
+		// Verify split is recorded on the donation
+		result = stub.MockInvoke(uid,
+			[][]byte{[]byte(GetDonation),
+				[]byte(test.txnID)})
+		assert.EqualValues(shim.OK, result.GetStatus(), GetDonation+" failed to read the donation data")
+
+		d := &dResp{}
+		err := json.Unmarshal(result.GetPayload(), d)
+		if err != nil {
+			panic(err)
+		}
+		expectedProgram, _ := decimal.NewFromString(test.expectedProgram)
+		expectedOverhead, _ := decimal.NewFromString(test.expectedOverhead)
+		assert.NotNil(d.Payload.Overhead, test.testNarrative+" failed - overhead split missing")
+		assert.True(expectedProgram.Equal(d.Payload.Overhead.ProgramAmt), test.testNarrative+" failed - program amount mismatch")
+		assert.True(expectedOverhead.Equal(d.Payload.Overhead.OverheadAmt), test.testNarrative+" failed - overhead amount mismatch")
+
+		// Verify overhead is recorded under overhead project, linked to the original donation
+		result = stub.MockInvoke(uid,
+			[][]byte{[]byte(GetDonation),
+				[]byte(overheadKey(test.txnID))})
+		if expectedOverhead.IsZero() {
+			assert.EqualValues(shim.ERROR, result.GetStatus(), test.testNarrative+" failed - unexpected overhead donation")
+			continue
+		}
+		assert.EqualValues(shim.OK, result.GetStatus(), test.testNarrative+" failed - overhead donation not found")
+		od := &dResp{}
+		err = json.Unmarshal(result.GetPayload(), od)
+		if err != nil {
+			panic(err)
+		}
+		assert.Equal(test.txnID, od.Payload.OverheadOf, test.testNarrative+" failed - overhead donation not linked")
+		assert.Equal("P100", od.Payload.Data.ProjectID, test.testNarrative+" failed - overhead donation not under overhead project")
+	}
+
+	// Verify program & overhead funds under their projects
+	var balanceTable = []struct {
+		projectID   string
+		expectedAvl string
+	}{
+		{"P101", "90.05"},
+		{"P100", "10"},
+	}
+	for _, test := range balanceTable {
+		result = stub.MockInvoke(uid,
+			[][]byte{[]byte(GetProject),
+				[]byte(test.projectID)})
+		assert.EqualValues(shim.OK, result.GetStatus(), GetProject+" failed to read the project data")
+
+		p := &pResp{}
+		err := json.Unmarshal(result.GetPayload(), p)
+		if err != nil {
+			panic(err)
+		}
+		expectedAvl, _ := decimal.NewFromString(test.expectedAvl)
+		assert.True(expectedAvl.Equal(p.Payload.Data.AvlFund), test.projectID+" available fund mismatch")
+	}
+}
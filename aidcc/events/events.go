@@ -0,0 +1,311 @@
+// Package events defines chaincode events emitted by AIDcc. Fabric keeps a single event per transaction,
+// hence each transaction emits one Transaction event whose payload is a Composite listing the domain
+// events of the transaction in order. Domain event name is one of the constants below and its payload is
+// the JSON encoding of the matching struct. Listeners decode each entry into the struct named after it.
+package events
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Version of the event payloads. It is raised whenever a payload changes in a way older listeners can
+// not read, fields added to a payload do not raise the version
+const Version = 1
+
+// Transaction is the name of the composite event emitted once per transaction
+const Transaction = "AidTransaction"
+
+// Domain event names
+const (
+	ProjectCreated             = "ProjectCreated"
+	ItemCreated                = "ItemCreated"
+	DonationRecorded           = "DonationRecorded"
+	DonationHeldForReview      = "DonationHeldForReview"
+	DonationReviewed           = "DonationReviewed"
+	SpendRecorded              = "SpendRecorded"
+	SpendReversed              = "SpendReversed"
+	PledgeCreated              = "PledgeCreated"
+	PledgeCancelled            = "PledgeCancelled"
+	RecurringDonationScheduled = "RecurringDonationScheduled"
+	MatchingCampaignCreated    = "MatchingCampaignCreated"
+	MilestoneAdded             = "MilestoneAdded"
+	MilestoneApproved          = "MilestoneApproved"
+	BudgetLineAdded            = "BudgetLineAdded"
+	FXRatePublished            = "FXRatePublished"
+	InKindReceived             = "InKindReceived"
+	InKindDistributed          = "InKindDistributed"
+	SpendEvidenceAdded         = "SpendEvidenceAdded"
+	VoucherIssued              = "VoucherIssued"
+	VoucherRedeemed            = "VoucherRedeemed"
+	VoucherExpired             = "VoucherExpired"
+	VendorRegistered           = "VendorRegistered"
+	VendorStatusChanged        = "VendorStatusChanged"
+	PartyBlocked               = "PartyBlocked"
+	PartyDelisted              = "PartyDelisted"
+	ScreeningRecorded          = "ScreeningRecorded"
+	AMLLimitsSet               = "AMLLimitsSet"
+	StateMigrated              = "StateMigrated"
+)
+
+// Event is implemented by all event payloads
+type Event interface {
+	Base() *Header
+}
+
+// Header is common to all event payloads
+type Header struct {
+	Name      string    `json:"name"`
+	Version   int       `json:"version"`
+	TxID      string    `json:"txID"`
+	TimeStamp time.Time `json:"timeStamp"` // transaction timestamp, UTC
+}
+
+// Base returns the header of the payload
+func (h *Header) Base() *Header {
+	return h
+}
+
+// Composite is the payload of the Transaction event
+type Composite struct {
+	Header
+	Events []Entry `json:"events"` // in the order emitted by the transaction
+}
+
+// Entry is a domain event of the transaction
+type Entry struct {
+	Name    string          `json:"name"`
+	Payload json.RawMessage `json:"payload"` // JSON encoding of the struct named after the event
+}
+
+// Project emitted as ProjectCreated
+type Project struct {
+	Header
+	ProjectID    string          `json:"projectID"`
+	ProjectName  string          `json:"projectName"`
+	RunBy        string          `json:"runBy"`
+	BaseCurrency string          `json:"baseCurrency"`
+	OverheadRate decimal.Decimal `json:"overheadRate"`
+	OverheadPrj  string          `json:"overheadPrj,omitempty"`
+}
+
+// Item emitted as ItemCreated
+type Item struct {
+	Header
+	ItemID    string `json:"itemID"`
+	ItemType  string `json:"itemType"`
+	Narrative string `json:"narrative"`
+}
+
+// Donation emitted as DonationRecorded & DonationHeldForReview. Reasons are set when the donation is
+// held for review, program & overhead amounts when it is recorded under project funds
+type Donation struct {
+	Header
+	TxnID       string          `json:"txnID"`
+	ProjectID   string          `json:"projectID"`
+	ItemID      string          `json:"itemID"`
+	Donor       string          `json:"donor"`
+	Amount      decimal.Decimal `json:"amount"`
+	Currency    string          `json:"currency"`
+	ProgramAmt  decimal.Decimal `json:"programAmt"`
+	OverheadAmt decimal.Decimal `json:"overheadAmt"`
+	PledgeID    string          `json:"pledgeID,omitempty"`
+	ScheduleID  string          `json:"scheduleID,omitempty"`
+	CampaignID  string          `json:"campaignID,omitempty"`
+	MatchOf     string          `json:"matchOf,omitempty"`
+	OverheadOf  string          `json:"overheadOf,omitempty"`
+	MatchedBy   []string        `json:"matchedBy,omitempty"`
+	Reasons     []string        `json:"reasons,omitempty"`
+}
+
+// DonationReview emitted as DonationReviewed
+type DonationReview struct {
+	Header
+	TxnID      string `json:"txnID"`
+	ProjectID  string `json:"projectID"`
+	Status     string `json:"status"` // APPROVED or REJECTED
+	ReviewedBy string `json:"reviewedBy"`
+	Remarks    string `json:"remarks,omitempty"`
+}
+
+// Spend emitted as SpendRecorded
+type Spend struct {
+	Header
+	TxnID       string          `json:"txnID"`
+	ProjectID   string          `json:"projectID"`
+	ItemID      string          `json:"itemID"`
+	Beneficiary string          `json:"beneficiary"`
+	Amount      decimal.Decimal `json:"amount"`
+	Currency    string          `json:"currency"`
+	Category    string          `json:"category,omitempty"`
+	VendorID    string          `json:"vendorID,omitempty"`
+	VoucherID   string          `json:"voucherID,omitempty"`
+}
+
+// Reversal emitted as SpendReversed
+type Reversal struct {
+	Header
+	TxnID      string          `json:"txnID"`
+	ReversalOf string          `json:"reversalOf"`
+	ProjectID  string          `json:"projectID"`
+	ItemID     string          `json:"itemID"`
+	Amount     decimal.Decimal `json:"amount"`
+	Currency   string          `json:"currency"`
+	Reason     string          `json:"reason"`
+}
+
+// Pledge emitted as PledgeCreated & PledgeCancelled. Amount is the pledged amount on creation and the
+// released outstanding amount on cancellation
+type Pledge struct {
+	Header
+	PledgeID  string          `json:"pledgeID"`
+	ProjectID string          `json:"projectID"`
+	ItemID    string          `json:"itemID"`
+	Donor     string          `json:"donor"`
+	Amount    decimal.Decimal `json:"amount"`
+	Currency  string          `json:"currency"`
+}
+
+// RecurringDonation emitted as RecurringDonationScheduled
+type RecurringDonation struct {
+	Header
+	ScheduleID  string          `json:"scheduleID"`
+	ProjectID   string          `json:"projectID"`
+	ItemID      string          `json:"itemID"`
+	Donor       string          `json:"donor"`
+	Amount      decimal.Decimal `json:"amount"`
+	Currency    string          `json:"currency"`
+	Interval    string          `json:"interval"`
+	StartDt     time.Time       `json:"startDt"`
+	EndDt       time.Time       `json:"endDt"`
+	Instalments int             `json:"instalments"`
+}
+
+// MatchingCampaign emitted as MatchingCampaignCreated
+type MatchingCampaign struct {
+	Header
+	CampaignID string          `json:"campaignID"`
+	ProjectID  string          `json:"projectID"`
+	Sponsor    string          `json:"sponsor"`
+	Ratio      decimal.Decimal `json:"ratio"`
+	Cap        decimal.Decimal `json:"cap"`
+	Currency   string          `json:"currency"`
+	StartDt    time.Time       `json:"startDt"`
+	EndDt      time.Time       `json:"endDt"`
+}
+
+// Milestone emitted as MilestoneAdded & MilestoneApproved
+type Milestone struct {
+	Header
+	ProjectID   string          `json:"projectID"`
+	MilestoneID string          `json:"milestoneID"`
+	Narrative   string          `json:"narrative"`
+	Budget      decimal.Decimal `json:"budget"`
+	ApprovedBy  string          `json:"approvedBy,omitempty"`
+}
+
+// BudgetLine emitted as BudgetLineAdded
+type BudgetLine struct {
+	Header
+	ProjectID string          `json:"projectID"`
+	Category  string          `json:"category"`
+	Allocated decimal.Decimal `json:"allocated"`
+	Currency  string          `json:"currency"`
+}
+
+// FXRate emitted as FXRatePublished
+type FXRate struct {
+	Header
+	Pair  string          `json:"pair"`
+	Rate  decimal.Decimal `json:"rate"`
+	AsOf  string          `json:"asOf"` // YYYY-MM-DD
+	SetBy string          `json:"setBy"`
+}
+
+// InKind emitted as InKindReceived & InKindDistributed. Donor & estimated value are set on receipt,
+// beneficiary on distribution
+type InKind struct {
+	Header
+	TxnID       string          `json:"txnID"`
+	ProjectID   string          `json:"projectID"`
+	ItemID      string          `json:"itemID"`
+	Quantity    decimal.Decimal `json:"quantity"`
+	Unit        string          `json:"unit"`
+	Donor       string          `json:"donor,omitempty"`
+	EstValue    decimal.Decimal `json:"estValue"`
+	Currency    string          `json:"currency,omitempty"`
+	Beneficiary string          `json:"beneficiary,omitempty"`
+}
+
+// Evidence emitted as SpendEvidenceAdded
+type Evidence struct {
+	Header
+	TxnID        string `json:"txnID"`
+	DocumentHash string `json:"documentHash"`
+	URI          string `json:"uri"`
+	MediaType    string `json:"mediaType"`
+	AddedBy      string `json:"addedBy"`
+}
+
+// Voucher emitted as VoucherIssued, VoucherRedeemed & VoucherExpired
+type Voucher struct {
+	Header
+	VoucherID   string          `json:"voucherID"`
+	ProjectID   string          `json:"projectID"`
+	ItemID      string          `json:"itemID"`
+	Beneficiary string          `json:"beneficiary"`
+	Amount      decimal.Decimal `json:"amount"`
+	Currency    string          `json:"currency"`
+	Status      string          `json:"status"`
+	ExpiryDt    string          `json:"expiryDt"` // YYYY-MM-DD
+	RedeemedBy  string          `json:"redeemedBy,omitempty"`
+	SpendTxnID  string          `json:"spendTxnID,omitempty"`
+}
+
+// Vendor emitted as VendorRegistered & VendorStatusChanged
+type Vendor struct {
+	Header
+	VendorID       string `json:"vendorID"`
+	LegalName      string `json:"legalName"`
+	RegistrationID string `json:"registrationID"`
+	Status         string `json:"status"`
+	UpdatedBy      string `json:"updatedBy"`
+}
+
+// BlockedParty emitted as PartyBlocked & PartyDelisted
+type BlockedParty struct {
+	Header
+	PartyHash string `json:"partyHash"`
+	Reason    string `json:"reason"`
+	Active    bool   `json:"active"`
+	UpdatedBy string `json:"updatedBy"`
+}
+
+// Screening emitted as ScreeningRecorded
+type Screening struct {
+	Header
+	TxnID     string `json:"txnID"`
+	Party     string `json:"party"`
+	PartyHash string `json:"partyHash"`
+	Matched   bool   `json:"matched"`
+}
+
+// AMLLimits emitted as AMLLimitsSet
+type AMLLimits struct {
+	Header
+	SingleLimit  decimal.Decimal `json:"singleLimit"`
+	RollingLimit decimal.Decimal `json:"rollingLimit"`
+	AnonymousCap decimal.Decimal `json:"anonymousCap"`
+	Currency     string          `json:"currency"`
+	UpdatedBy    string          `json:"updatedBy"`
+}
+
+// Migration emitted as StateMigrated
+type Migration struct {
+	Header
+	AssetType string `json:"assetType"`
+	Migrated  int    `json:"migrated"` // documents rewritten to the latest schema version
+	Bookmark  string `json:"bookmark"` // key the migration resumes from, empty once complete
+}
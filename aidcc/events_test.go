@@ -0,0 +1,201 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/vishal3152/AIDChaincode/aidcc/events"
+)
+
+// lastEvent - drain events emitted on the stub, returning the last one if any
+func lastEvent(stub *shim.MockStub) *pb.ChaincodeEvent {
+	var last *pb.ChaincodeEvent
+	for {
+		select {
+		case e := <-stub.ChaincodeEventsChannel:
+			last = e
+		default:
+			return last
+		}
+	}
+}
+
+// Verifies event emitted by each invoke, invokes are executed in order
+func TestEventsRW(t *testing.T) {
+	fmt.Println("Executing Test - EventsRW")
+
+	today := time.Now().UTC()
+	startDt := today.Format(DATEFMT)
+	endDt := today.AddDate(0, 3, 0).Format(DATEFMT)
+	docHash := partyHash("receipt")
+
+	// Test data - Refer to test narratives for test description. Expected field is checked in the
+	// event payload, no event is expected when event name is empty
+	var eventTable = []struct {
+		args          []string
+		expectedName  string
+		expectedField string
+		expectedValue string
+		testNarrative string
+	}{
+		{[]string{AddProject, "P101", "Prj101"}, events.ProjectCreated, "projectID", "P101", "Project creation"},
+		{[]string{AddProject, "P102", "Prj102"}, events.ProjectCreated, "projectName", "Prj102", "Project creation"},
+		{[]string{AddItem, "Itm001", "Item001", "Medicine"}, events.ItemCreated, "itemID", "Itm001", "Item creation"},
+		{[]string{AddDonation, "D101", "vishal", "P101", "Itm001", "1000"}, events.DonationRecorded, "donor", "vishal", "Donation"},
+		{[]string{GetProject, "P101"}, "", "", "", "Project read"},
+		{[]string{AddSpend, "S101", "beneficiary", "P101", "Itm001", "50"}, events.SpendRecorded, "beneficiary", "beneficiary", "Spend"},
+		{[]string{ReverseSpend, "S101", "Recorded in error"}, events.SpendReversed, "reversalOf", "S101", "Spend reversal"},
+		{[]string{AddPledge, "PL101", "vishal", "P101", "Itm001", "200"}, events.PledgeCreated, "pledgeID", "PL101", "Pledge"},
+		{[]string{CancelPledge, "PL101"}, events.PledgeCancelled, "amount", "200", "Pledge cancellation"},
+		{[]string{AddRecurringDonation, "RD101", "vishal", "P101", "Itm001", "25", MONTHLY, startDt, endDt}, events.RecurringDonationScheduled, "interval", MONTHLY, "Recurring donation schedule"},
+		{[]string{RecordInstalment, "D102", "RD101", "25"}, events.DonationRecorded, "scheduleID", "RD101", "Recurring donation instalment"},
+		{[]string{AddMatchingCampaign, "MC101", "sponsor", "P101", "1", "100", startDt, endDt}, events.MatchingCampaignCreated, "sponsor", "sponsor", "Matching campaign"},
+		{[]string{AddMilestone, "P102", "M1", "Phase 1", "100"}, events.MilestoneAdded, "milestoneID", "M1", "Milestone"},
+		{[]string{ApproveMilestone, "P102", "M1"}, events.MilestoneApproved, "approvedBy", "Test Caller", "Milestone approval"},
+		{[]string{AddBudgetLine, "P102", "logistics", "100"}, events.BudgetLineAdded, "category", "logistics", "Budget line"},
+		{[]string{SetFXRate, "EUR/USD", "1.1", "2010-01-01"}, events.FXRatePublished, "pair", "EUR/USD", "FX rate"},
+		{[]string{AddInKindDonation, "K101", "vishal", "P101", "Itm001", "10", "box", "50"}, events.InKindReceived, "unit", "box", "In-kind donation"},
+		{[]string{DistributeInKind, "O101", "beneficiary", "P101", "Itm001", "5", "box"}, events.InKindDistributed, "beneficiary", "beneficiary", "In-kind distribution"},
+		{[]string{AddSpend, "S102", "beneficiary", "P101", "Itm001", "50"}, events.SpendRecorded, "txnID", "S102", "Spend"},
+		{[]string{AddSpendEvidence, "S102", docHash, "https://docs.example.org/r1.pdf", "application/pdf"}, events.SpendEvidenceAdded, "documentHash", docHash, "Spend evidence"},
+		{[]string{IssueVoucher, "V101", "beneficiary", "P101", "Itm001", "100", endDt}, events.VoucherIssued, "status", VCHISSD, "Voucher issue"},
+		{[]string{RedeemVoucher, "V101"}, events.VoucherRedeemed, "spendTxnID", redemptionKey("V101"), "Voucher redemption"},
+		{[]string{AddVendor, "VN101", "Acme Supplies Ltd", "REG-001", docHash}, events.VendorRegistered, "status", VNDACTV, "Vendor registration"},
+		{[]string{SetVendorStatus, "VN101", VNDSUSP}, events.VendorStatusChanged, "status", VNDSUSP, "Vendor suspension"},
+		{[]string{AddBlockedParty, partyHash("blocked"), "Sanctions list"}, events.PartyBlocked, "active", "true", "Party blocked"},
+		{[]string{RemoveBlockedParty, partyHash("blocked")}, events.PartyDelisted, "active", "false", "Party delisted"},
+		{[]string{SetAMLLimits, "1000", "0", "0"}, events.AMLLimitsSet, "currency", DEFCCY, "AML limits"},
+		{[]string{AddDonation, "D103", "vishal", "P101", "Itm001", "5000"}, events.DonationHeldForReview, "txnID", "D103", "Donation over AML limits"},
+		{[]string{ApproveDonation, "D103"}, events.DonationReviewed, "status", DONAPRV, "Donation review"},
+	}
+
+	assert := assert.New(t)
+
+	// Instantiate mockStub using AidChaincode as the target chaincode to unit test
+	stub := shim.NewMockStub("TestStub", new(AidChaincode))
+	assert.NotNil(stub, "Stub is nil, Test stub creation failed")
+
+	// Executing event tests
+	for _, test := range eventTable {
+		uid := uuid.New().String()
+		args := make([][]byte, len(test.args))
+		for i, arg := range test.args {
+			args[i] = []byte(arg)
+		}
+		result := stub.MockInvoke(uid, args)
+		assert.EqualValues(shim.OK, result.GetStatus(), test.testNarrative+" failed - "+(result.GetMessage()))
+
+		e := lastEvent(stub)
+		if len(test.expectedName) == 0 {
+			assert.Nil(e, test.testNarrative+" failed - unexpected event")
+			continue
+		}
+		if !assert.NotNil(e, test.testNarrative+" failed - no event emitted") {
+			continue
+		}
+		assert.Equal(events.Transaction, e.GetEventName(), test.testNarrative+" failed - event name mismatch")
+
+		c := &events.Composite{}
+		err := json.Unmarshal(e.GetPayload(), c)
+		if err != nil {
+			panic(err)
+		}
+		assert.EqualValues(events.Version, c.Version, test.testNarrative+" failed - composite version mismatch")
+		assert.Equal(uid, c.TxID, test.testNarrative+" failed - composite txID mismatch")
+		if !assert.NotEmpty(c.Events, test.testNarrative+" failed - no domain event collected") {
+			continue
+		}
+
+		// Expected event is the last domain event of the transaction
+		last := c.Events[len(c.Events)-1]
+		assert.Equal(test.expectedName, last.Name, test.testNarrative+" failed - event name mismatch")
+		payload := make(map[string]interface{})
+		err = json.Unmarshal(last.Payload, &payload)
+		if err != nil {
+			panic(err)
+		}
+		assert.Equal(test.expectedName, payload["name"], test.testNarrative+" failed - payload name mismatch")
+		assert.EqualValues(events.Version, payload["version"], test.testNarrative+" failed - payload version mismatch")
+		assert.Equal(uid, payload["txID"], test.testNarrative+" failed - payload txID mismatch")
+		assert.Equal(test.expectedValue, fmt.Sprint(payload[test.expectedField]), test.testNarrative+" failed - payload "+test.expectedField+" mismatch")
+	}
+}
+
+// Verifies domain events collected by a transaction are flushed in order as one composite event
+func TestEventCollector(t *testing.T) {
+	fmt.Println("Executing Test - EventCollector")
+
+	today := time.Now().UTC()
+	startDt := today.Format(DATEFMT)
+	endDt := today.AddDate(0, 3, 0).Format(DATEFMT)
+
+	// Test data - Refer to test narratives for test description. No event is expected for a failed invoke
+	var collectorTable = []struct {
+		args           []string
+		expectedStatus int32
+		expectedNames  []string
+		expectedTxnIDs []string
+		testNarrative  string
+	}{
+		{[]string{AddProject, "P101", "Prj101"}, 200, []string{events.ProjectCreated}, []string{""}, "Single domain event"},
+		{[]string{AddItem, "Itm001", "Item001", "Medicine"}, 200, []string{events.ItemCreated}, []string{""}, "Single domain event"},
+		{[]string{AddMatchingCampaign, "MC101", "sponsor", "P101", "1", "100", startDt, endDt}, 200, []string{events.MatchingCampaignCreated}, []string{""}, "Single domain event"},
+		{[]string{AddDonation, "D101", "vishal", "P101", "Itm001", "50"}, 200, []string{events.DonationRecorded, events.DonationRecorded}, []string{matchKey("D101", "MC101"), "D101"}, "Donation matched by a campaign"},
+		{[]string{GetProject, "P101"}, 200, nil, nil, "Read emits no event"},
+		{[]string{IssueVoucher, "V101", "beneficiary", "P101", "Itm001", "20", endDt}, 200, []string{events.VoucherIssued}, []string{""}, "Single domain event"},
+		{[]string{RedeemVoucher, "V101"}, 200, []string{events.SpendRecorded, events.VoucherRedeemed}, []string{redemptionKey("V101"), ""}, "Voucher redemption"},
+		{[]string{AddDonation, "D101", "vishal", "P101", "Itm001", "50"}, 500, nil, nil, "Failed invoke emits no event"},
+	}
+
+	assert := assert.New(t)
+
+	// Instantiate mockStub using AidChaincode as the target chaincode to unit test
+	stub := shim.NewMockStub("TestStub", new(AidChaincode))
+	assert.NotNil(stub, "Stub is nil, Test stub creation failed")
+
+	// Executing collector tests
+	for _, test := range collectorTable {
+		uid := uuid.New().String()
+		args := make([][]byte, len(test.args))
+		for i, arg := range test.args {
+			args[i] = []byte(arg)
+		}
+		result := stub.MockInvoke(uid, args)
+		assert.Equal(test.expectedStatus, result.GetStatus(), test.testNarrative+" failed - "+(result.GetMessage()))
+
+		e := lastEvent(stub)
+		if len(test.expectedNames) == 0 {
+			assert.Nil(e, test.testNarrative+" failed - unexpected event")
+			continue
+		}
+		if !assert.NotNil(e, test.testNarrative+" failed - no event emitted") {
+			continue
+		}
+		c := &events.Composite{}
+		err := json.Unmarshal(e.GetPayload(), c)
+		if err != nil {
+			panic(err)
+		}
+		if !assert.Len(c.Events, len(test.expectedNames), test.testNarrative+" failed - domain events mismatch") {
+			continue
+		}
+		for i, entry := range c.Events {
+			assert.Equal(test.expectedNames[i], entry.Name, test.testNarrative+" failed - domain event order mismatch")
+			if len(test.expectedTxnIDs[i]) == 0 {
+				continue
+			}
+			payload := make(map[string]interface{})
+			err = json.Unmarshal(entry.Payload, &payload)
+			if err != nil {
+				panic(err)
+			}
+			assert.Equal(test.expectedTxnIDs[i], payload["txnID"], test.testNarrative+" failed - domain event txnID mismatch")
+		}
+	}
+}
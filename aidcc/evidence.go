@@ -0,0 +1,142 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/vishal3152/AIDChaincode/aidcc/events"
+)
+
+// Asset model for spend evidence. All asset models are kept in
+// private scope i.e. they are not exported and ramin invisible to other packages
+// Evidence is kept under the spend. Receipts, photos or delivery notes stay off-chain, only their
+// content hash and location are recorded so that a copy can be verified against the ledger.
+type evidence struct {
+	DocumentHash string    `json:"documentHash"` // hex encoded SHA-256 of the document
+	URI          string    `json:"uri"`          // off-chain location of the document
+	MediaType    string    `json:"mediaType"`    // e.g. image/jpeg, application/pdf
+	AddedBy      string    `json:"addedBy"`
+	TimeStamp    time.Time `json:"timeStamp"`
+}
+
+type spendEvidence struct {
+	TxnID string   `json:"txnID"` // affiliated spend
+	Data  evidence `json:"data"`  // composition
+}
+
+// evidenceCheck - result of verifying a document hash against a spend's evidence
+type evidenceCheck struct {
+	TxnID        string    `json:"txnID"`
+	DocumentHash string    `json:"documentHash"`
+	Verified     bool      `json:"verified"`
+	Evidence     *evidence `json:"evidence,omitempty"` // matching evidence, if verified
+}
+
+// Write evidence under the spend
+func (se *spendEvidence) putState(stub shim.ChaincodeStubInterface) pb.Response {
+
+	s := &spend{TxnID: se.TxnID}
+	cErr := s.load(stub, "putEvidence")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	// check if document is already attached to the spend
+	for _, e := range s.Evidence {
+		if e.DocumentHash == se.Data.DocumentHash {
+			cErr = &chainError{"putEvidence", se.TxnID, CODEAlRDEXIST, errors.New("Document already attached to the spend")}
+			return errorResponse(stub, cErr)
+		}
+	}
+
+	s.Evidence = append(s.Evidence, se.Data)
+	cErr = s.store(stub, "putEvidence")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	// Emit transaction event for listeners
+	cErr = emitEvent(stub, "putEvidence", se.TxnID, events.SpendEvidenceAdded, &events.Evidence{TxnID: se.TxnID, DocumentHash: se.Data.DocumentHash, URI: se.Data.URI, MediaType: se.Data.MediaType, AddedBy: se.Data.AddedBy})
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	r := response{CODEALLAOK, se.TxnID, nil}
+	return shim.Success((r.formatResponse(stub)))
+}
+
+// Verify a document hash against the evidence attached to the spend
+func (se *spendEvidence) getState(stub shim.ChaincodeStubInterface) pb.Response {
+
+	s := &spend{TxnID: se.TxnID}
+	cErr := s.load(stub, "verifyEvidence")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	check := evidenceCheck{TxnID: se.TxnID, DocumentHash: se.Data.DocumentHash}
+	for i := range s.Evidence {
+		if s.Evidence[i].DocumentHash == se.Data.DocumentHash {
+			check.Verified = true
+			check.Evidence = &s.Evidence[i]
+			break
+		}
+	}
+	b, err := json.Marshal(check)
+	if err != nil {
+		cErr = &chainError{"verifyEvidence", se.TxnID, CODEGENEXCEPTION, err}
+		return errorResponse(stub, cErr)
+	}
+	r := response{CODEALLAOK, "OK", b}
+	return shim.Success((r.formatResponse(stub)))
+}
+
+// unevidencedState - read spends of the project lacking evidence. Reversed spends are excluded
+func (p *project) unevidencedState(stub shim.ChaincodeStubInterface) pb.Response {
+
+	cErr := p.load(stub, "readUnevidenced")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	// Spends written before they were listed by project are listed once rewritten by MigrateState
+	spdItr, err := stub.GetStateByPartialCompositeKey(SPDIDX, []string{p.ProjectID})
+	if err != nil {
+		cErr = &chainError{"readUnevidenced", p.ProjectID, CODEGENEXCEPTION, err}
+		return errorResponse(stub, cErr)
+	}
+	//Close itrerator when done reading
+	defer spdItr.Close()
+	spends := []spend{}
+	for spdItr.HasNext() {
+		rangeItem, err := spdItr.Next()
+		if err != nil {
+			cErr = &chainError{"readUnevidenced", p.ProjectID, CODEGENEXCEPTION, err}
+			return errorResponse(stub, cErr)
+		}
+		_, compositeKeyParts, err := stub.SplitCompositeKey(rangeItem.Key)
+		if err != nil {
+			cErr = &chainError{"readUnevidenced", p.ProjectID, CODEGENEXCEPTION, err}
+			return errorResponse(stub, cErr)
+		}
+		// compositeKeyParts[1] represents txnID
+		s := &spend{TxnID: compositeKeyParts[1]}
+		cErr = s.load(stub, "readUnevidenced")
+		if cErr != nil {
+			return errorResponse(stub, cErr)
+		}
+		if len(s.Evidence) == 0 && len(s.ReversedBy) == 0 {
+			spends = append(spends, *s)
+		}
+	}
+
+	b, err := json.Marshal(spends)
+	if err != nil {
+		cErr = &chainError{"readUnevidenced", p.ProjectID, CODEGENEXCEPTION, err}
+		return errorResponse(stub, cErr)
+	}
+	r := response{CODEALLAOK, p.ProjectID, b}
+	return shim.Success((r.formatResponse(stub)))
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/stretchr/testify/assert"
+)
+
+// Verifies scenarios related to proof of delivery evidence for spends
+func TestEvidenceRW(t *testing.T) {
+	fmt.Println("Executing Test - EvidenceRW")
+	defer os.Unsetenv("ROLE")
+
+	receipt := sha256.Sum256([]byte("receipt"))
+	photo := sha256.Sum256([]byte("photo"))
+	receiptHash := hex.EncodeToString(receipt[:])
+	photoHash := hex.EncodeToString(photo[:])
+
+	// Test data - Refer to test narratives for test description
+	var evidenceTable = []struct {
+		txnID          string
+		documentHash   string
+		uri            string
+		mediaType      string
+		role           string
+		expectedStatus int32
+		testNarrative  string
+	}{
+		{"S101", receiptHash, "https://docs.example.org/r1.pdf", "application/pdf", "", 200, "Happy scenario"},
+		{"S101", strings.ToUpper(receiptHash), "https://docs.example.org/r1.pdf", "application/pdf", "", 500, "Check for duplicate document"},
+		{"S101", "abc123", "https://docs.example.org/p1.jpg", "image/jpeg", "", 500, "Check for SHA-256 document hash"},
+		{"S101", photoHash, "", "image/jpeg", "", 500, "Check for missing document URI"},
+		{"S101", photoHash, "https://docs.example.org/p1.jpg", "jpeg", "", 500, "Check for media type format"},
+		{"S109", photoHash, "https://docs.example.org/p1.jpg", "image/jpeg", "", 500, "Check for spend existence"},
+		{"D101", photoHash, "https://docs.example.org/p1.jpg", "image/jpeg", "", 500, "Check for evidence on a donation"},
+		{"S102", photoHash, "https://docs.example.org/p1.jpg", "image/jpeg", "donor", 500, "Check for caller role"},
+	}
+	// Verification of document hashes against spend evidence
+	var verifyTable = []struct {
+		txnID            string
+		documentHash     string
+		expectedVerified bool
+		testNarrative    string
+	}{
+		{"S101", receiptHash, true, "Happy scenario"},
+		{"S101", strings.ToUpper(receiptHash), true, "Hash verified regardless of case"},
+		{"S101", photoHash, false, "Check for document not attached"},
+		{"S102", receiptHash, false, "Check for document attached to another spend"},
+	}
+
+	// struct for parsing the shim APIs response
+	type vResp struct {
+		Code    string        `json:"code"`
+		Message string        `json:"message"`
+		Payload evidenceCheck `json:"payload"`
+	}
+	type sResp struct {
+		Code    string  `json:"code"`
+		Message string  `json:"message"`
+		Payload []spend `json:"payload"`
+	}
+	assert := assert.New(t)
+
+	// Instantiate mockStub using AidChaincode as the target chaincode to unit test
+	stub := shim.NewMockStub("TestStub", new(AidChaincode))
+	assert.NotNil(stub, "Stub is nil, Test stub creation failed")
+
+	uid := uuid.New().String()
+
+	// Add test project, item & donation to state.
+	result := stub.MockInvoke(uid,
+		[][]byte{[]byte(AddProject),
+			[]byte("P101"),
+			[]byte("Prj101")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Saving test project to state failed.")
+
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(AddItem),
+			[]byte("Itm001"),
+			[]byte("Item001"),
+			[]byte("Medicine")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Saving test item to state failed.")
+
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(AddDonation),
+			[]byte("D101"),
+			[]byte("vishal"),
+			[]byte("P101"),
+			[]byte("Itm001"),
+			[]byte("500")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Saving test donation entry to state failed.")
+
+	// Refresh Available funds under Project
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(GetProject),
+			[]byte("P101")})
+	assert.EqualValues(shim.OK, result.GetStatus(), GetProject+" failed to read the project data")
+
+	// Add test spends, S103 is reversed
+	for _, txnID := range []string{"S101", "S102", "S103"} {
+		result = stub.MockInvoke(uid,
+			[][]byte{[]byte(AddSpend),
+				[]byte(txnID),
+				[]byte("vishal"),
+				[]byte("P101"),
+				[]byte("Itm001"),
+				[]byte("50")})
+		assert.EqualValues(shim.OK, result.GetStatus(), "Saving test spend entry to state failed.")
+	}
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(ReverseSpend),
+			[]byte("S103"),
+			[]byte("Recorded in error")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Reversing test spend entry failed.")
+
+	// Executing evidence tests
+	for _, test := range evidenceTable {
+		os.Setenv("ROLE", test.role)
+		result := stub.MockInvoke(uid,
+			[][]byte{[]byte(AddSpendEvidence),
+				[]byte(test.txnID),
+				[]byte(test.documentHash),
+				[]byte(test.uri),
+				[]byte(test.mediaType)})
+
+		assert.Equal(test.expectedStatus, result.GetStatus(), test.testNarrative+" failed - "+(result.GetMessage()))
+	}
+	os.Unsetenv("ROLE")
+
+	// Executing verification tests
+	for _, test := range verifyTable {
+		result := stub.MockInvoke(uid,
+			[][]byte{[]byte(VerifySpendEvidence),
+				[]byte(test.txnID),
+				[]byte(test.documentHash)})
+		assert.EqualValues(shim.OK, result.GetStatus(), test.testNarrative+" failed - "+(result.GetMessage()))
+
+		v := &vResp{}
+		err := json.Unmarshal(result.GetPayload(), v)
+		if err != nil {
+			panic(err)
+		}
+		assert.Equal(test.expectedVerified, v.Payload.Verified, test.testNarrative+" failed - verification mismatch")
+		if test.expectedVerified {
+			assert.Equal("application/pdf", v.Payload.Evidence.MediaType, test.testNarrative+" failed - matching evidence not returned")
+		}
+	}
+
+	// Verify spends lacking evidence, reversed spend is excluded
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(GetUnevidencedSpends),
+			[]byte("P101")})
+	assert.EqualValues(shim.OK, result.GetStatus(), GetUnevidencedSpends+" failed to read the project spends")
+
+	s := &sResp{}
+	err := json.Unmarshal(result.GetPayload(), s)
+	if err != nil {
+		panic(err)
+	}
+	if assert.Len(s.Payload, 1, "Spends lacking evidence mismatch") {
+		assert.Equal("S102", s.Payload[0].TxnID, "Spend lacking evidence mismatch")
+	}
+
+	// Spend written before schema versioning, hence not listed by project until migrated
+	stub.MockTransactionStart(uuid.New().String())
+	legacyDoc := `{"docType":"` + DONOUT + `","txnID":"S104","donor":"vishal","data":{"projectID":"P101","itemID":"Itm001","amount":"10","currency":"USD"}}`
+	err = stub.PutState("S104", []byte(legacyDoc))
+	if err != nil {
+		panic(err)
+	}
+	stub.MockTransactionEnd("")
+	var legacyTable = []struct {
+		args           []string
+		expectedSpends int
+		testNarrative  string
+	}{
+		{[]string{GetUnevidencedSpends, "P101"}, 1, "Check for legacy spend not listed before migration"},
+		{[]string{MigrateState, DONOUT, "10"}, 0, "Happy scenario - migrate spends"},
+		{[]string{GetUnevidencedSpends, "P101"}, 2, "Happy scenario - legacy spend listed once migrated"},
+	}
+	for _, test := range legacyTable {
+		args := make([][]byte, len(test.args))
+		for i, arg := range test.args {
+			args[i] = []byte(arg)
+		}
+		result = stub.MockInvoke(uid, args)
+		assert.EqualValues(shim.OK, result.GetStatus(), test.testNarrative+" failed - "+result.GetMessage())
+		if test.args[0] != GetUnevidencedSpends {
+			continue
+		}
+		s := &sResp{}
+		err := json.Unmarshal(result.GetPayload(), s)
+		if err != nil {
+			panic(err)
+		}
+		assert.Len(s.Payload, test.expectedSpends, test.testNarrative+" failed - spends lacking evidence mismatch")
+	}
+}
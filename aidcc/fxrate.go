@@ -0,0 +1,429 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/shopspring/decimal"
+	"github.com/vishal3152/AIDChaincode/aidcc/events"
+)
+
+// Asset model for FX rate. All asset models are kept in
+// private scope i.e. they are not exported and ramin invisible to other packages
+// A rate is published by an authorized oracle for a currency pair and an effective date. Published
+// rates are never overwritten, a rate stays effective until the next effective date of the pair.
+type fxRateBase struct {
+	Rate  decimal.Decimal `json:"rate"`  // units of quote currency per unit of base currency
+	AsOf  time.Time       `json:"asOf"`  // effective date
+	SetBy string          `json:"setBy"` // oracle identity
+}
+
+type fxRate struct {
+	ObjectType string     `json:"docType"` // FX rate Type 'FXRATE'
+	versioned             // schema version of the document
+	Pair       string     `json:"pair"` // base/quote currency e.g. EUR/USD
+	Data       fxRateBase `json:"data"` // composition
+}
+
+// key - ledger key of the FX rate
+func (fx *fxRate) key() string {
+	return fxRateKey(fx.Pair, fx.Data.AsOf.Format(DATEFMT))
+}
+
+// docType - doc type of FX rate assets
+func (fx *fxRate) docType() string {
+	return FXRATE
+}
+
+// validate - check FX rate invariants before it is written
+func (fx *fxRate) validate() *chainError {
+
+	cErr := checkIdentity(fx, fx.ObjectType)
+	if cErr != nil {
+		return cErr
+	}
+	if fx.Data.Rate.Sign() <= 0 {
+		return &chainError{"validate", fx.key(), CODEUNPROCESSABLEENTITY, errors.New("FX rate must be positive")}
+	}
+	return nil
+}
+
+// fxRateUsed - rate applied while converting funds to a reporting currency
+type fxRateUsed struct {
+	Pair     string          `json:"pair"`
+	AsOf     string          `json:"asOf"`
+	Rate     decimal.Decimal `json:"rate"`
+	Inverted bool            `json:"inverted,omitempty"` // published rate of the reverse pair was applied
+}
+
+// convertedFunds - project funds converted to a reporting currency
+type convertedFunds struct {
+	Currency     string          `json:"currency"`
+	AvlFund      decimal.Decimal `json:"avlFund"`
+	SpentFund    decimal.Decimal `json:"spentFund"`
+	PledgedFund  decimal.Decimal `json:"pledgedFund"`
+	ReservedFund decimal.Decimal `json:"reservedFund"`
+	RatesUsed    []fxRateUsed    `json:"ratesUsed"`
+}
+
+// fxRateKey - derive FX rate key from the currency pair and effective date
+func fxRateKey(pair string, asOf string) string {
+	return pair + "_" + asOf
+}
+
+// fxPair - currency pair in base/quote form
+func fxPair(base string, quote string) string {
+	return base + "/" + quote
+}
+
+// Write FX rate to ledger
+func (fx *fxRate) putState(stub shim.ChaincodeStubInterface) pb.Response {
+
+	// check if rate is already published for the pair and date
+	asOf := fx.Data.AsOf.Format(DATEFMT)
+	c, cErr := checkAsset(stub, fxRateKey(fx.Pair, asOf))
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	} else if c {
+		e := &chainError{"putFXRate", fx.Pair, CODEAlRDEXIST, errors.New("FX rate already published for " + asOf)}
+		return errorResponse(stub, e)
+	}
+
+	cErr = newRepository(stub, "putFXRate").save(fx)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	// Add indexkey for range query :- rates are looked up by pair whenever funds are converted
+	indexKey, err := stub.CreateCompositeKey(FXIDX, []string{fx.Pair, asOf})
+	if err != nil {
+		cErr = &chainError{"putFXRate", fx.Pair, CODEGENEXCEPTION, err}
+		return errorResponse(stub, cErr)
+	}
+	err = stub.PutState(indexKey, []byte{0x00})
+	if err != nil {
+		cErr = &chainError{"putFXRate", fx.Pair, CODEGENEXCEPTION, err}
+		return errorResponse(stub, cErr)
+	}
+
+	// Emit transaction event for listeners
+	cErr = emitEvent(stub, "putFXRate", fx.Pair, events.FXRatePublished, &events.FXRate{Pair: fx.Pair, Rate: fx.Data.Rate, AsOf: asOf, SetBy: fx.Data.SetBy})
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	r := response{CODEALLAOK, fxRateKey(fx.Pair, asOf), nil}
+	return shim.Success((r.formatResponse(stub)))
+}
+
+// Read FX rate of the pair effective on the requested date
+func (fx *fxRate) getState(stub shim.ChaincodeStubInterface) pb.Response {
+
+	cErr := fx.effective(stub, "readFXRate")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	b, err := json.Marshal(fx)
+	if err != nil {
+		cErr = &chainError{"readFXRate", fx.Pair, CODEGENEXCEPTION, err}
+		return errorResponse(stub, cErr)
+	}
+	r := response{CODEALLAOK, "OK", b}
+	return shim.Success((r.formatResponse(stub)))
+}
+
+// effective - load the rate of the pair effective on the receiver's date i.e. the latest rate
+// published on or before the date
+func (fx *fxRate) effective(stub shim.ChaincodeStubInterface, fcn string) *chainError {
+
+	date := fx.Data.AsOf.Format(DATEFMT)
+	fxItr, err := stub.GetStateByPartialCompositeKey(FXIDX, []string{fx.Pair})
+	if err != nil {
+		return &chainError{fcn, fx.Pair, CODEGENEXCEPTION, err}
+	}
+	//Close itrerator when done reading
+	defer fxItr.Close()
+	latest := ""
+	for fxItr.HasNext() {
+		rangeItem, err := fxItr.Next()
+		if err != nil {
+			return &chainError{fcn, fx.Pair, CODEGENEXCEPTION, err}
+		}
+		_, compositeKeyParts, err := stub.SplitCompositeKey(rangeItem.Key)
+		if err != nil {
+			return &chainError{fcn, fx.Pair, CODEGENEXCEPTION, err}
+		}
+		// compositeKeyParts[1] represents effective date, dates in YYYY-MM-DD format sort as strings
+		if compositeKeyParts[1] <= date && compositeKeyParts[1] > latest {
+			latest = compositeKeyParts[1]
+		}
+	}
+	if len(latest) == 0 {
+		return &chainError{fcn, fx.Pair, CODENOTFOUND, errors.New("No FX rate effective on " + date)}
+	}
+
+	fxBytes, cErr := queryAsset(stub, fxRateKey(fx.Pair, latest))
+	if cErr != nil {
+		return cErr
+	}
+	err = json.Unmarshal(fxBytes, fx)
+	if err != nil {
+		return &chainError{fcn, fx.Pair, CODEGENEXCEPTION, err}
+	}
+	return nil
+}
+
+// rateAt - rate converting one currency into another, effective on the date. Published rate of the
+// reverse pair is inverted when the pair itself has no rate
+func rateAt(stub shim.ChaincodeStubInterface, from string, to string, date time.Time) (fxRateUsed, *chainError) {
+
+	fx := &fxRate{Pair: fxPair(from, to), Data: fxRateBase{AsOf: date}}
+	cErr := fx.effective(stub, "convertFunds")
+	if cErr == nil {
+		return fxRateUsed{fx.Pair, fx.Data.AsOf.Format(DATEFMT), fx.Data.Rate, false}, nil
+	} else if cErr.code != CODENOTFOUND {
+		return fxRateUsed{}, cErr
+	}
+
+	fx = &fxRate{Pair: fxPair(to, from), Data: fxRateBase{AsOf: date}}
+	cErr = fx.effective(stub, "convertFunds")
+	if cErr != nil {
+		if cErr.code == CODENOTFOUND {
+			cErr = &chainError{"convertFunds", fxPair(from, to), CODENOTFOUND, errors.New("No FX rate effective on " + date.Format(DATEFMT))}
+		}
+		return fxRateUsed{}, cErr
+	}
+	return fxRateUsed{fx.Pair, fx.Data.AsOf.Format(DATEFMT), fx.Data.Rate, true}, nil
+}
+
+// fundReport - project funds in a reporting currency, kept on the project so that only movements of months
+// not yet folded are converted on read. Funds held before project history was kept are folded in as an
+// opening balance, at the rate effective when the report is first built
+type fundReport struct {
+	Checkpoint string       `json:"checkpoint"` // movements of months before the checkpoint are folded
+	Funds      fundBalance  `json:"funds"`
+	RatesUsed  []fxRateUsed `json:"ratesUsed"`
+}
+
+// fundMovement - fund movement read from project history
+type fundMovement struct {
+	timeStamp time.Time
+	bitmask   string
+	currency  string
+	amount    decimal.Decimal
+}
+
+// fundConverter - converts fund movements to a reporting currency, rates are looked up once per
+// currency & date
+type fundConverter struct {
+	stub     shim.ChaincodeStubInterface
+	currency string
+	rates    map[string]fxRateUsed
+}
+
+// convert - convert an amount at the rate effective on the date, the rate applied is added to report
+func (fc *fundConverter) convert(currency string, amount decimal.Decimal, date time.Time, report *fundReport) (decimal.Decimal, *chainError) {
+
+	if currency == fc.currency || amount.IsZero() {
+		return amount, nil
+	}
+	lookup := currency + "_" + date.Format(DATEFMT)
+	rate, found := fc.rates[lookup]
+	if !found {
+		var cErr *chainError
+		rate, cErr = rateAt(fc.stub, currency, fc.currency, date)
+		if cErr != nil {
+			return amount, cErr
+		}
+		fc.rates[lookup] = rate
+	}
+	report.addRate(rate)
+	if rate.Inverted {
+		return amount.Div(rate.Rate), nil
+	}
+	return amount.Mul(rate.Rate), nil
+}
+
+// fold - convert fund movements & apply them to report funds
+func (fc *fundConverter) fold(moves []fundMovement, report *fundReport) *chainError {
+
+	for _, m := range moves {
+		amount, cErr := fc.convert(m.currency, m.amount, m.timeStamp, report)
+		if cErr != nil {
+			return cErr
+		}
+		report.Funds.apply(m.bitmask, amount)
+	}
+	return nil
+}
+
+// addRate - add rate to the rates used by the report, unless already there
+func (r *fundReport) addRate(rate fxRateUsed) {
+	for _, used := range r.RatesUsed {
+		if used.Pair == rate.Pair && used.AsOf == rate.AsOf {
+			return
+		}
+	}
+	r.RatesUsed = append(r.RatesUsed, rate)
+}
+
+// readHistory - fund movements of a month of project history, in ledger key order
+func readHistory(stub shim.ChaincodeStubInterface, projectID string, month string) ([]fundMovement, *chainError) {
+
+	moves := []fundMovement{}
+	hstItr, err := stub.GetStateByPartialCompositeKey(HSTIDX, []string{projectID, month})
+	if err != nil {
+		return moves, &chainError{"convertFunds", projectID, CODEGENEXCEPTION, err}
+	}
+	//Close itrerator when done reading
+	defer hstItr.Close()
+	for hstItr.HasNext() {
+		rangeItem, err := hstItr.Next()
+		if err != nil {
+			return moves, &chainError{"convertFunds", projectID, CODEGENEXCEPTION, err}
+		}
+		_, compositeKeyParts, err := stub.SplitCompositeKey(rangeItem.Key)
+		if err != nil {
+			return moves, &chainError{"convertFunds", projectID, CODEGENEXCEPTION, err}
+		}
+		// compositeKeyParts[2] represents timestamp, [3] bitmask, [4] currency & [6] transaction amount
+		timeStamp, err := time.Parse(time.RFC3339, compositeKeyParts[2])
+		if err != nil {
+			return moves, &chainError{"convertFunds", projectID, CODEGENEXCEPTION, err}
+		}
+		amount, _ := decimal.NewFromString(compositeKeyParts[6])
+		moves = append(moves, fundMovement{timeStamp, compositeKeyParts[3], compositeKeyParts[4], amount})
+	}
+	return moves, nil
+}
+
+// historyMonths - months of project history with fund movements, in chronological order
+func historyMonths(stub shim.ChaincodeStubInterface, projectID string) ([]string, *chainError) {
+
+	months := []string{}
+	mthItr, err := stub.GetStateByPartialCompositeKey(MTHIDX, []string{projectID})
+	if err != nil {
+		return months, &chainError{"convertFunds", projectID, CODEGENEXCEPTION, err}
+	}
+	//Close itrerator when done reading
+	defer mthItr.Close()
+	for mthItr.HasNext() {
+		rangeItem, err := mthItr.Next()
+		if err != nil {
+			return months, &chainError{"convertFunds", projectID, CODEGENEXCEPTION, err}
+		}
+		_, compositeKeyParts, err := stub.SplitCompositeKey(rangeItem.Key)
+		if err != nil {
+			return months, &chainError{"convertFunds", projectID, CODEGENEXCEPTION, err}
+		}
+		// compositeKeyParts[1] represents month, months in YYYY-MM format sort as strings
+		months = append(months, compositeKeyParts[1])
+	}
+	return months, nil
+}
+
+// convertFunds - convert project funds to the reporting currency. Each fund movement in project history
+// is converted at the rate effective on its timestamp. Settled months are folded into the report kept on
+// the project, only months after its checkpoint are read again. The report is built from stored balances
+// the first time, funds not explained by history are converted as an opening balance
+func convertFunds(stub shim.ChaincodeStubInterface, p *project, currency string) (*convertedFunds, *chainError) {
+
+	precision, ok := precisionOf(currency)
+	if !ok {
+		return nil, &chainError{"convertFunds", p.ProjectID, CODEUNPROCESSABLEENTITY, errors.New("Unsupported currency " + currency)}
+	}
+	epochTime, _ := stub.GetTxTimestamp()
+	txTime := time.Unix(epochTime.GetSeconds(), 0).UTC()
+	settled := txTime.Add(-time.Duration(HSTSETTLE) * time.Hour).Format(MONTHFMT)
+
+	fc := &fundConverter{stub, currency, make(map[string]fxRateUsed)}
+	report, found := p.Data.Reports[currency]
+	native := make(map[string]fundBalance) // history per currency, explains stored balances
+	if !found {
+		report = fundReport{RatesUsed: []fxRateUsed{}}
+	}
+
+	// movements of months not yet settled are converted on every read
+	recent := fundReport{RatesUsed: []fxRateUsed{}}
+	months, cErr := historyMonths(stub, p.ProjectID)
+	if cErr != nil {
+		return nil, cErr
+	}
+	for _, month := range months {
+		if found && month < report.Checkpoint {
+			continue
+		}
+		moves, cErr := readHistory(stub, p.ProjectID, month)
+		if cErr != nil {
+			return nil, cErr
+		}
+		addNative(native, moves)
+		target := &recent
+		if month < settled {
+			target = &report
+		}
+		cErr = fc.fold(moves, target)
+		if cErr != nil {
+			return nil, cErr
+		}
+	}
+
+	if !found {
+		currencies := make([]string, 0, len(p.Data.Balances))
+		for ccy := range p.Data.Balances {
+			currencies = append(currencies, ccy)
+		}
+		sort.Strings(currencies)
+		for _, ccy := range currencies {
+			bal, hst := p.Data.Balances[ccy], native[ccy]
+			opening := []decimal.Decimal{bal.AvlFund.Sub(hst.AvlFund), bal.SpentFund.Sub(hst.SpentFund), bal.PledgedFund.Sub(hst.PledgedFund), bal.ReservedFund.Sub(hst.ReservedFund)}
+			for i := range opening {
+				opening[i], cErr = fc.convert(ccy, opening[i], txTime, &report)
+				if cErr != nil {
+					return nil, cErr
+				}
+			}
+			report.Funds.AvlFund = report.Funds.AvlFund.Add(opening[0])
+			report.Funds.SpentFund = report.Funds.SpentFund.Add(opening[1])
+			report.Funds.PledgedFund = report.Funds.PledgedFund.Add(opening[2])
+			report.Funds.ReservedFund = report.Funds.ReservedFund.Add(opening[3])
+		}
+	}
+	if settled > report.Checkpoint {
+		report.Checkpoint = settled
+	}
+	report.Funds.AvlFund = report.Funds.AvlFund.Round(int32(FIXEDPT))
+	report.Funds.SpentFund = report.Funds.SpentFund.Round(int32(FIXEDPT))
+	report.Funds.PledgedFund = report.Funds.PledgedFund.Round(int32(FIXEDPT))
+	report.Funds.ReservedFund = report.Funds.ReservedFund.Round(int32(FIXEDPT))
+	if p.Data.Reports == nil {
+		p.Data.Reports = make(map[string]fundReport)
+	}
+	p.Data.Reports[currency] = report
+
+	// rates of folded & recent movements are reported once each
+	merged := &fundReport{RatesUsed: []fxRateUsed{}}
+	for _, rates := range [][]fxRateUsed{report.RatesUsed, recent.RatesUsed} {
+		for _, rate := range rates {
+			merged.addRate(rate)
+		}
+	}
+	cf := &convertedFunds{Currency: currency, RatesUsed: merged.RatesUsed}
+	cf.AvlFund = report.Funds.AvlFund.Add(recent.Funds.AvlFund).RoundBank(precision)
+	cf.SpentFund = report.Funds.SpentFund.Add(recent.Funds.SpentFund).RoundBank(precision)
+	cf.PledgedFund = report.Funds.PledgedFund.Add(recent.Funds.PledgedFund).RoundBank(precision)
+	cf.ReservedFund = report.Funds.ReservedFund.Add(recent.Funds.ReservedFund).RoundBank(precision)
+	return cf, nil
+}
+
+// addNative - add fund movements in their own currency to the balances per currency
+func addNative(native map[string]fundBalance, moves []fundMovement) {
+	for _, m := range moves {
+		bal := native[m.currency]
+		bal.apply(m.bitmask, m.amount)
+		native[m.currency] = bal
+	}
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// Verifies scenarios related to FX rates and project funds converted to a reporting currency
+func TestFXRateRW(t *testing.T) {
+	fmt.Println("Executing Test - FXRateRW")
+	defer os.Unsetenv("ROLE")
+
+	today := time.Now().UTC()
+	pastDt := today.AddDate(0, 0, -1).Format(DATEFMT)
+	futureDt := today.AddDate(0, 0, 1).Format(DATEFMT)
+
+	// Test data - Refer to test narratives for test description
+	var fxTable = []struct {
+		role           string
+		pair           string
+		rate           string
+		asOf           string
+		expectedStatus int32
+		testNarrative  string
+	}{
+		{ROLEORACLE, "EUR/USD", "1.1", "2010-01-01", 200, "Happy scenario"},
+		{ROLEORACLE, "EUR/USD", "1.2", pastDt, 200, "Happy scenario - rate effective since yesterday"},
+		{ROLEORACLE, "EUR/USD", "2", futureDt, 200, "Happy scenario - rate effective from tomorrow"},
+		{"donor", "EUR/USD", "1.3", "2011-01-01", 500, "Check for rate published by unauthorized role"},
+		{ROLEORACLE, "EURUSD", "1.3", "2011-01-01", 500, "Check for currency pair format"},
+		{ROLEORACLE, "EUR/EUR", "1", "2011-01-01", 500, "Check for pair of same currency"},
+		{ROLEORACLE, "EUR/ABC", "1.3", "2011-01-01", 500, "Check for unsupported currency"},
+		{ROLEORACLE, "EUR/USD", "0", "2011-01-01", 500, "Check for positive rate"},
+		{ROLEORACLE, "EUR/USD", "1.3", "01-01-2011", 500, "Check for effective date format"},
+		{ROLEORACLE, "EUR/USD", "1.3", "2010-01-01", 500, "Check for rate already published for the date"},
+	}
+	// Project funds are reported in each currency
+	var convertTable = []struct {
+		currency       string
+		expectedAvl    string
+		expectedSpent  string
+		expectedRates  int
+		expectedStatus int32
+		testNarrative  string
+	}{
+		{"USD", "146", "24", 1, 200, "Happy scenario - EUR converted at rate effective on transaction date"},
+		{"EUR", "121.67", "20", 1, 200, "Happy scenario - USD converted at inverted rate"},
+		{"GBP", "", "", 0, 500, "Check for missing FX rate"},
+		{"ABC", "", "", 0, 500, "Check for unsupported reporting currency"},
+	}
+
+	// struct for parsing the shim APIs response
+	type fResp struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+		Payload fxRate `json:"payload"`
+	}
+	type pResp struct {
+		Code    string  `json:"code"`
+		Message string  `json:"message"`
+		Payload project `json:"payload"`
+	}
+	assert := assert.New(t)
+
+	// Instantiate mockStub using AidChaincode as the target chaincode to unit test
+	stub := shim.NewMockStub("TestStub", new(AidChaincode))
+	assert.NotNil(stub, "Stub is nil, Test stub creation failed")
+
+	uid := uuid.New().String()
+
+	// Executing FX rate tests
+	for _, test := range fxTable {
+		os.Setenv("ROLE", test.role)
+		result := stub.MockInvoke(uid,
+			[][]byte{[]byte(SetFXRate),
+				[]byte(test.pair),
+				[]byte(test.rate),
+				[]byte(test.asOf)})
+
+		assert.Equal(test.expectedStatus, result.GetStatus(), test.testNarrative+" failed - "+(result.GetMessage()))
+	}
+
+	// Verify rate effective on a date is the latest rate published on or before the date
+	result := stub.MockInvoke(uid,
+		[][]byte{[]byte(GetFXRate),
+			[]byte("EUR/USD"),
+			[]byte("2015-06-30")})
+	assert.EqualValues(shim.OK, result.GetStatus(), GetFXRate+" failed to read the FX rate")
+
+	f := &fResp{}
+	err := json.Unmarshal(result.GetPayload(), f)
+	if err != nil {
+		panic(err)
+	}
+	expectedRate, _ := decimal.NewFromString("1.1")
+	assert.True(expectedRate.Equal(f.Payload.Data.Rate), "Effective FX rate mismatch")
+
+	// Add test project with EUR as base currency, item, donations & spend to state.
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(AddProject),
+			[]byte("P101"),
+			[]byte("Prj101"),
+			[]byte("EUR")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Saving test project to state failed.")
+
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(AddItem),
+			[]byte("Itm001"),
+			[]byte("Item001"),
+			[]byte("Medicine")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Saving test item to state failed.")
+
+	for _, don := range [][]string{{"D101", "100", "EUR"}, {"D102", "50", "USD"}} {
+		result = stub.MockInvoke(uid,
+			[][]byte{[]byte(AddDonation),
+				[]byte(don[0]),
+				[]byte("vishal"),
+				[]byte("P101"),
+				[]byte("Itm001"),
+				[]byte(don[1]),
+				[]byte(""),
+				[]byte(don[2])})
+		assert.EqualValues(shim.OK, result.GetStatus(), "Saving test donation entry to state failed.")
+	}
+
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(GetProject),
+			[]byte("P101")})
+	assert.EqualValues(shim.OK, result.GetStatus(), GetProject+" failed to read the project data")
+
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(AddSpend),
+			[]byte("S101"),
+			[]byte("vishal"),
+			[]byte("P101"),
+			[]byte("Itm001"),
+			[]byte("20")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Saving test spend entry to state failed.")
+
+	// Executing conversion tests
+	for _, test := range convertTable {
+		result := stub.MockInvoke(uid,
+			[][]byte{[]byte(GetProject),
+				[]byte("P101"),
+				[]byte(test.currency)})
+
+		assert.Equal(test.expectedStatus, result.GetStatus(), test.testNarrative+" failed - "+(result.GetMessage()))
+
+		if result.GetStatus() == shim.OK {
+			p := &pResp{}
+			err := json.Unmarshal(result.GetPayload(), p)
+			if err != nil {
+				panic(err)
+			}
+			assert.NotNil(p.Payload.Converted, test.testNarrative+" failed - converted funds missing")
+			expectedAvl, _ := decimal.NewFromString(test.expectedAvl)
+			expectedSpent, _ := decimal.NewFromString(test.expectedSpent)
+			assert.Equal(test.currency, p.Payload.Converted.Currency, test.testNarrative+" failed - reporting currency mismatch")
+			assert.True(expectedAvl.Equal(p.Payload.Converted.AvlFund), test.testNarrative+" failed - converted available fund mismatch")
+			assert.True(expectedSpent.Equal(p.Payload.Converted.SpentFund), test.testNarrative+" failed - converted spent fund mismatch")
+			assert.Len(p.Payload.Converted.RatesUsed, test.expectedRates, test.testNarrative+" failed - rates used mismatch")
+			assert.Equal(pastDt, p.Payload.Converted.RatesUsed[0].AsOf, test.testNarrative+" failed - rate not effective on transaction date")
+		}
+	}
+}
+
+// Verifies converted funds reconcile with stored balances held before project history was kept, and
+// settled months of history are folded into the report kept on the project
+func TestFXReport(t *testing.T) {
+	fmt.Println("Executing Test - FXReport")
+	defer os.Unsetenv("ROLE")
+
+	// Funds of USD 500 held before history was kept & EUR 150 donated over two settled months. Balances
+	// are those aggregated from all of them
+	prjDoc := `{"docType":"` + GPRJCT + `","projectID":"P101","data":{"projectName":"Prj101","runBy":"Test Caller","startDt":"2019-01-01T00:00:00Z","baseCurrency":"USD","avlFund":"500","spentFund":"20","balances":{"USD":{"avlFund":"500","spentFund":"20"},"EUR":{"avlFund":"150"}}}}`
+	history := []struct {
+		index string
+		parts []string
+	}{
+		{HSTIDX, []string{"P101", "2020-06", "2020-06-01T10:00:00Z", "1", "EUR", "D001", "100"}},
+		{MTHIDX, []string{"P101", "2020-06"}},
+		{HSTIDX, []string{"P101", "2021-03", "2021-03-05T10:00:00Z", "1", "EUR", "D002", "50"}},
+		{MTHIDX, []string{"P101", "2021-03"}},
+	}
+
+	// Test data - Refer to test narratives for test description. Steps are executed in order
+	var reportTable = []struct {
+		donation      string
+		purge         bool
+		expectedAvl   string
+		expectedSpent string
+		expectedFold  string
+		testNarrative string
+	}{
+		{"", false, "680", "20", "680", "Happy scenario - opening balance & history reconcile with balances"},
+		{"10", false, "692", "20", "680", "Happy scenario - movement of current month converted on read"},
+		{"", true, "692", "20", "680", "Happy scenario - settled months not read again"},
+	}
+
+	// struct for parsing the shim APIs response
+	type pResp struct {
+		Payload project `json:"payload"`
+	}
+	assert := assert.New(t)
+
+	// Instantiate mockStub using AidChaincode as the target chaincode to unit test
+	stub := shim.NewMockStub("TestStub", new(AidChaincode))
+	assert.NotNil(stub, "Stub is nil, Test stub creation failed")
+
+	os.Setenv("ROLE", ROLEORACLE)
+	result := stub.MockInvoke(uuid.New().String(), [][]byte{[]byte(SetFXRate), []byte("EUR/USD"), []byte("1.2"), []byte("2020-01-01")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Saving test FX rate to state failed.")
+	os.Unsetenv("ROLE")
+	result = stub.MockInvoke(uuid.New().String(), [][]byte{[]byte(AddItem), []byte("Itm001"), []byte("Item001"), []byte("Medicine")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Saving test item to state failed.")
+
+	historyKeys := []string{}
+	stub.MockTransactionStart(uuid.New().String())
+	err := stub.PutState("P101", []byte(prjDoc))
+	if err != nil {
+		panic(err)
+	}
+	for _, h := range history {
+		key, _ := stub.CreateCompositeKey(h.index, h.parts)
+		historyKeys = append(historyKeys, key)
+		err = stub.PutState(key, []byte{0x00})
+		if err != nil {
+			panic(err)
+		}
+	}
+	stub.MockTransactionEnd("")
+
+	// Executing tests
+	settled := time.Now().UTC().Add(-time.Duration(HSTSETTLE) * time.Hour).Format(MONTHFMT)
+	for _, test := range reportTable {
+		if len(test.donation) != 0 {
+			result := stub.MockInvoke(uuid.New().String(),
+				[][]byte{[]byte(AddDonation), []byte("D101"), []byte("vishal"), []byte("P101"), []byte("Itm001"), []byte(test.donation), []byte(""), []byte("EUR")})
+			assert.EqualValues(shim.OK, result.GetStatus(), test.testNarrative+" failed - "+result.GetMessage())
+		}
+		if test.purge {
+			stub.MockTransactionStart(uuid.New().String())
+			for _, key := range historyKeys {
+				stub.DelState(key)
+			}
+			stub.MockTransactionEnd("")
+		}
+
+		result := stub.MockInvoke(uuid.New().String(), [][]byte{[]byte(GetProject), []byte("P101"), []byte(DEFCCY)})
+		assert.EqualValues(shim.OK, result.GetStatus(), test.testNarrative+" failed - "+result.GetMessage())
+
+		p := &pResp{}
+		err := json.Unmarshal(result.GetPayload(), p)
+		if err != nil {
+			panic(err)
+		}
+		assert.Equal(test.expectedAvl, p.Payload.Converted.AvlFund.String(), test.testNarrative+" failed - converted available fund mismatch")
+		assert.Equal(test.expectedSpent, p.Payload.Converted.SpentFund.String(), test.testNarrative+" failed - converted spent fund mismatch")
+		assert.Len(p.Payload.Converted.RatesUsed, 1, test.testNarrative+" failed - rates used mismatch")
+		report := p.Payload.Data.Reports[DEFCCY]
+		assert.Equal(settled, report.Checkpoint, test.testNarrative+" failed - report checkpoint mismatch")
+		assert.Equal(test.expectedFold, report.Funds.AvlFund.String(), test.testNarrative+" failed - folded available fund mismatch")
+	}
+}
@@ -1,8 +1,12 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
+
 	"github.com/hyperledger/fabric/core/chaincode/shim"
 	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/shopspring/decimal"
 )
 
 // AidChaincode - define a type for chaincode.
@@ -20,21 +24,159 @@ const (
 	CODEGENEXCEPTION        string = "P5001" // Unknown exception
 	CODEAlRDEXIST           string = "P5002" // Not unique
 	CODENOTALLWD            string = "P4004" // Operation not allowed
+	CODEFORBIDDEN           string = "P4005" // Caller not authorized
+	CODESANCTIONED          string = "P4006" // Party on sanctions blocklist
+	CODEAMTFORMAT           string = "P4007" // Malformed amount
+	CODEAMTPRECISION        string = "P4008" // Amount with more decimals than FIXEDPT or its currency allows
+	CODEAMTRANGE            string = "P4009" // Amount over the max amount
 
 	// Couch DB Doc types for asset
 	GPRJCT string = "GPRJCT"
 	GITEM  string = "GITEM"
 	DONIN  string = "DONIN"
 	DONOUT string = "DONOUT"
+	DONREV string = "DONREV"
+	PLEDGE string = "PLEDGE"
+	DONREC string = "DONREC"
+	DONMTC string = "DONMTC"
+	FXRATE string = "FXRATE"
+	INKIN  string = "INKIN"
+	INKOUT string = "INKOUT"
+	VOUCHR string = "VOUCHR"
+	VENDOR string = "VENDOR"
+	BLKPTY string = "BLKPTY"
+	AMLCFG string = "AMLCFG"
+
+	// Pledge status
+	PLGOPEN string = "OPEN"
+	PLGFULL string = "FULFILLED"
+	PLGCNCL string = "CANCELLED"
+
+	// Voucher status
+	VCHISSD string = "ISSUED"
+	VCHRDMD string = "REDEEMED"
+	VCHEXPD string = "EXPIRED"
+
+	// Donation review status, donations over AML limits are parked for review
+	DONRVW  string = "REVIEW"
+	DONAPRV string = "APPROVED"
+	DONRJCT string = "REJECTED"
+
+	// Vendor status
+	VNDACTV string = "ACTIVE"
+	VNDSUSP string = "SUSPENDED"
+
+	// Recurring donation intervals
+	WEEKLY    string = "WEEKLY"
+	MONTHLY   string = "MONTHLY"
+	QUARTERLY string = "QUARTERLY"
+	YEARLY    string = "YEARLY"
 
 	// Range index name - to perform range queries
-	INDXNM string = "bitmask~txnID~amount" //bitmask is "0" for donation (spending) & "1" donation(incoming)
+	// bitmask is "0" for donation (spending), "1" donation(incoming), "2" spend reversal,
+	// "3" pledge, "4" pledge release (fulfilment or cancellation), "5" voucher reservation &
+	// "6" reservation release (redemption or expiry)
+	INDXNM string = "projectID~bitmask~currency~txnID~amount"
+	// range index of deltas written before deltas were kept per project & currency, bitmask is "0" or "1"
+	// only & amounts are in DEFCCY. Aggregated along with INDXNM until none is left
+	LGCYIDX string = "bitmask~txnID~amount"
+	CMPIDX  string = "projectID~campaignID" // matching campaigns of a project
+	// fund movements of a project by month, kept permanently unlike deltas, for reporting in another currency
+	HSTIDX string = "projectID~month~timeStamp~bitmask~currency~txnID~amount"
+	MTHIDX string = "projectID~month"                          // months of project history with fund movements
+	FXIDX  string = "pair~asOf"                                // FX rates of a currency pair by effective date
+	CATIDX string = "projectID~category~txnID~amount"          // spends & vouchers of a project budget line, reversals are negative
+	INVIDX string = "projectID~itemID~unit~txnID~quantity"     // project inventory movements, distributions are negative
+	SPDIDX string = "spend~projectID~txnID"                    // spends of a project, maintained by the asset repository
+	VNDIDX string = "vendorID~projectID~currency~txnID~amount" // spends paid to a vendor, reversals are negative
+	SCRIDX string = "txnID~party~txID"                         // sanctions screenings of each attempt of a transaction
+	DNRIDX string = "donor~timeStamp~currency~txnID~amount"    // donations of a donor, rejected donations are negative
+	RVWIDX string = "projectID~txnID"                          // donations pending AML review
+	ASTIDX string = "docType~key"                              // assets by doc type, maintained by the asset repository
+
+	DEFCCY string = "USD" // Default base currency of a project. Amounts can not exceed the precision of their currency
+
+	DATEFMT  string = "2006-01-02" // Date input format i.e. YYYY-MM-DD
+	MONTHFMT string = "2006-01"    // Month of project history i.e. YYYY-MM
+	// Hours after month end when a month of project history is settled & folded into reports, transactions
+	// carry client timestamps so movements of a month may commit shortly after it ends
+	HSTSETTLE int = 24
+
+	FIXEDPT   int    = 8               // Max no of decimals of amounts, rates & quantities
+	MAXAMT    string = "1000000000000" // Default max amount, overridden by the 1st argument of Init
+	AMTMAXKEY string = "AMOUNT_MAX"    // Ledger key of the max amount in force
 
-	FIXEDPT int32 = 4 // All currency values rounded off to 4 decimals i.e. 0.0000
+	AMLKEY    string = "AML_LIMITS" // Ledger key of AML limits in force
+	AMLWINDOW int    = 30           // Rolling window of donor total, in days
+	ANONDNR   string = "anonymous"  // Donor identifier of an anonymous donation
+
+	// Caller roles, carried as ECert attribute ROLEATTR
+	ROLEATTR     string = "aidcc.role"
+	ROLEVERIFIER string = "verifier"      // approves project milestones & attaches spend evidence
+	ROLEORACLE   string = "fxoracle"      // publishes FX rates
+	ROLEADMIN    string = "admin"         // acts on any project on behalf of its owner & migrates ledger state
+	ROLEVENDOR   string = "vendor"        // redeems beneficiary vouchers
+	ROLEISSUER   string = "voucherissuer" // issues beneficiary vouchers against project funds
+	ROLEVNDADMIN string = "vendoradmin"   // manages vendor registry
+	ROLECMPL     string = "compliance"    // maintains sanctions blocklist
+
+	MAXBATCH int = 500 // Max no of documents scanned by a MigrateState batch
 )
 
-// Init - Implements shim.Chaincode interface Init() method
+// errorCode - response code with its meaning, for clients to localize messages
+type errorCode struct {
+	Code    string `json:"code"`
+	Name    string `json:"name"`
+	Meaning string `json:"meaning"`
+}
+
+// errorCatalogue - every response code of the chaincode. Codes are never reused, new codes are appended
+var errorCatalogue = []errorCode{
+	{CODEALLAOK, "CODEALLAOK", "Success"},
+	{CODENOTFOUND, "CODENOTFOUND", "Resource not found"},
+	{CODEUNKNOWNINVOKE, "CODEUNKNOWNINVOKE", "Unknown function invoked"},
+	{CODEUNPROCESSABLEENTITY, "CODEUNPROCESSABLEENTITY", "Invalid input"},
+	{CODENOTALLWD, "CODENOTALLWD", "Operation not allowed in the current state"},
+	{CODEFORBIDDEN, "CODEFORBIDDEN", "Caller not authorized"},
+	{CODESANCTIONED, "CODESANCTIONED", "Party on sanctions blocklist"},
+	{CODEGENEXCEPTION, "CODEGENEXCEPTION", "Unknown exception"},
+	{CODEAlRDEXIST, "CODEAlRDEXIST", "Resource already exists"},
+	{CODEAMTFORMAT, "CODEAMTFORMAT", "Amount is not a plain decimal number"},
+	{CODEAMTPRECISION, "CODEAMTPRECISION", "Amount has too many decimals"},
+	{CODEAMTRANGE, "CODEAMTRANGE", "Amount is over the max amount"},
+}
+
+// errorCatalogueState - response listing the error catalogue
+func errorCatalogueState(stub shim.ChaincodeStubInterface) pb.Response {
+	b, _ := json.Marshal(errorCatalogue)
+	r := response{CODEALLAOK, "OK", b}
+	return shim.Success((r.formatResponse(stub)))
+}
+
+// Init - Implements shim.Chaincode interface Init() method. 1st argument i.e. max amount accepted by
+// invokes is optional, the max amount in force is kept when omitted
 func (t *AidChaincode) Init(stub shim.ChaincodeStubInterface) pb.Response {
+	_, args := stub.GetFunctionAndParameters()
+	if len(args) > 1 {
+		cErr := &chainError{"Init", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting optional max amount only")}
+		return errorResponse(stub, cErr)
+	}
+	if len(args) == 1 && len(args[0]) != 0 {
+		// the max amount itself is not limited, 10^30 is beyond any amount
+		max, cErr := parseAmount(args[0], decimal.New(1, 30))
+		if cErr != nil {
+			return errorResponse(stub, cErr)
+		}
+		if max.Sign() <= 0 {
+			cErr = &chainError{"Init", args[0], CODEUNPROCESSABLEENTITY, errors.New("Max amount can not less than or equal to zero")}
+			return errorResponse(stub, cErr)
+		}
+		err := stub.PutState(AMTMAXKEY, []byte(max.String()))
+		if err != nil {
+			cErr = &chainError{"Init", AMTMAXKEY, CODEGENEXCEPTION, err}
+			return errorResponse(stub, cErr)
+		}
+	}
 	r := response{(CODEALLAOK), "AIDcc started", nil}
-	return shim.Success((r.formatResponse()))
+	return shim.Success((r.formatResponse(stub)))
 }
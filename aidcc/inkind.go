@@ -0,0 +1,298 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/shopspring/decimal"
+	"github.com/vishal3152/AIDChaincode/aidcc/events"
+)
+
+// Asset model for in-kind donation & distribution. All asset models are kept in
+// private scope i.e. they are not exported and ramin invisible to other packages
+// Goods donated to a project are recorded as quantities of an item and kept as project inventory.
+// Goods distributed to beneficiaries are recorded as inventory spends and can not exceed stock on hand.
+type inKindBase struct {
+	ProjectID string          `json:"projectID"`
+	ItemID    string          `json:"itemID"`
+	Quantity  decimal.Decimal `json:"quantity"`
+	Unit      string          `json:"unit"` // unit of measure e.g. kg, box, dose
+	TimeStamp time.Time       `json:"timeStamp"`
+}
+
+type inKindDonation struct {
+	ObjectType string          `json:"docType"` // in-kind donation Type 'INKIN'
+	versioned                  // schema version of the document
+	TxnID      string          `json:"txnID"` // asset unique key
+	Donor      string          `json:"donor"`
+	EstValue   decimal.Decimal `json:"estValue"` // estimated value of the goods donated
+	Currency   string          `json:"currency"` // ISO 4217 code of estimated value
+	Data       inKindBase      `json:"data"`     // composition
+}
+
+// key - ledger key of the in-kind donation
+func (d *inKindDonation) key() string {
+	return d.TxnID
+}
+
+// docType - doc type of in-kind donation assets
+func (d *inKindDonation) docType() string {
+	return INKIN
+}
+
+// validate - check in-kind donation invariants before it is written
+func (d *inKindDonation) validate() *chainError {
+
+	cErr := checkIdentity(d, d.ObjectType)
+	if cErr != nil {
+		return cErr
+	}
+	if d.Data.Quantity.Sign() <= 0 {
+		return &chainError{"validate", d.key(), CODEUNPROCESSABLEENTITY, errors.New("Quantity must be positive")}
+	}
+	return nil
+}
+
+type inKindSpend struct {
+	ObjectType string     `json:"docType"` // inventory spend Type 'INKOUT'
+	versioned             // schema version of the document
+	TxnID      string     `json:"txnID"` // asset unique key
+	Benficiary string     `json:"beneficiary"`
+	Data       inKindBase `json:"data"` // composition
+}
+
+// key - ledger key of the inventory spend
+func (s *inKindSpend) key() string {
+	return s.TxnID
+}
+
+// docType - doc type of inventory spend assets
+func (s *inKindSpend) docType() string {
+	return INKOUT
+}
+
+// validate - check inventory spend invariants before it is written
+func (s *inKindSpend) validate() *chainError {
+
+	cErr := checkIdentity(s, s.ObjectType)
+	if cErr != nil {
+		return cErr
+	}
+	if s.Data.Quantity.Sign() <= 0 {
+		return &chainError{"validate", s.key(), CODEUNPROCESSABLEENTITY, errors.New("Quantity must be positive")}
+	}
+	return nil
+}
+
+// stockLine - quantity of an item on hand under a project
+type stockLine struct {
+	ItemID   string          `json:"itemID"`
+	Unit     string          `json:"unit"`
+	Quantity decimal.Decimal `json:"quantity"`
+}
+
+// Write in-kind donation to ledger, donated quantity is added to project inventory
+func (d *inKindDonation) putState(stub shim.ChaincodeStubInterface) pb.Response {
+
+	cErr := checkInKind(stub, "putInKind", d.TxnID, d.Data)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	// resolve estimated value currency, defaults to project's base currency
+	d.Currency, d.EstValue, cErr = resolveCurrency(stub, "putInKind", d.TxnID, d.Data.ProjectID, d.Currency, d.EstValue)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	cErr = newRepository(stub, "putInKind").save(d)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	cErr = putStock(stub, "putInKind", d.TxnID, d.Data, d.Data.Quantity)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	// Emit transaction event for listeners
+	cErr = emitEvent(stub, "putInKind", d.TxnID, events.InKindReceived, &events.InKind{TxnID: d.TxnID, ProjectID: d.Data.ProjectID, ItemID: d.Data.ItemID, Quantity: d.Data.Quantity, Unit: d.Data.Unit, Donor: d.Donor, EstValue: d.EstValue, Currency: d.Currency})
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	r := response{CODEALLAOK, d.TxnID, nil}
+	return shim.Success((r.formatResponse(stub)))
+}
+
+// Read in-kind donation state from the ledger
+func (d *inKindDonation) getState(stub shim.ChaincodeStubInterface) pb.Response {
+
+	inKind, cErr := queryAsset(stub, d.TxnID)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	r := response{CODEALLAOK, "OK", inKind}
+	return shim.Success((r.formatResponse(stub)))
+}
+
+// Write inventory spend to ledger, distributed quantity can not exceed stock on hand
+func (s *inKindSpend) putState(stub shim.ChaincodeStubInterface) pb.Response {
+
+	cErr := checkInKind(stub, "putInKindSpend", s.TxnID, s.Data)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	// check if project has the item in stock before distributing
+	stock, cErr := stockOnHand(stub, s.Data.ProjectID, s.Data.ItemID)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	onHand, _ := decimal.NewFromString("0")
+	for _, l := range stock {
+		if l.Unit == s.Data.Unit {
+			onHand = l.Quantity
+		}
+	}
+	if s.Data.Quantity.GreaterThan(onHand) {
+		cErr = &chainError{"putInKindSpend", s.TxnID, CODENOTALLWD, errors.New("Distribution exceeds stock on hand of " + onHand.String() + " " + s.Data.Unit)}
+		return errorResponse(stub, cErr)
+	}
+
+	cErr = newRepository(stub, "putInKindSpend").save(s)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	cErr = putStock(stub, "putInKindSpend", s.TxnID, s.Data, s.Data.Quantity.Neg())
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	// Emit transaction event for listeners
+	cErr = emitEvent(stub, "distributeInKind", s.TxnID, events.InKindDistributed, &events.InKind{TxnID: s.TxnID, ProjectID: s.Data.ProjectID, ItemID: s.Data.ItemID, Quantity: s.Data.Quantity, Unit: s.Data.Unit, Beneficiary: s.Benficiary})
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	r := response{CODEALLAOK, s.TxnID, nil}
+	return shim.Success((r.formatResponse(stub)))
+}
+
+// Read inventory spend state from the ledger
+func (s *inKindSpend) getState(stub shim.ChaincodeStubInterface) pb.Response {
+
+	inKind, cErr := queryAsset(stub, s.TxnID)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	r := response{CODEALLAOK, "OK", inKind}
+	return shim.Success((r.formatResponse(stub)))
+}
+
+// inventoryState - read stock on hand under the project, for all items unless an item is requested
+func (p *project) inventoryState(stub shim.ChaincodeStubInterface, itemID string) pb.Response {
+
+	cErr := p.load(stub, "readInventory")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	stock, cErr := stockOnHand(stub, p.ProjectID, itemID)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	b, err := json.Marshal(stock)
+	if err != nil {
+		cErr = &chainError{"readInventory", p.ProjectID, CODEGENEXCEPTION, err}
+		return errorResponse(stub, cErr)
+	}
+	r := response{CODEALLAOK, p.ProjectID, b}
+	return shim.Success((r.formatResponse(stub)))
+}
+
+// checkInKind - check if affiliated project & item exist and txnID is unique
+func checkInKind(stub shim.ChaincodeStubInterface, fcn string, txnID string, data inKindBase) *chainError {
+
+	c, cErr := checkAsset(stub, data.ProjectID)
+	if cErr != nil {
+		return cErr
+	} else if !c {
+		return &chainError{fcn, txnID, CODENOTFOUND, errors.New("Affiliated project not found")}
+	}
+	c, cErr = checkAsset(stub, data.ItemID)
+	if cErr != nil {
+		return cErr
+	} else if !c {
+		return &chainError{fcn, txnID, CODENOTFOUND, errors.New("Affiliated item not found")}
+	}
+	c, cErr = checkAsset(stub, txnID)
+	if cErr != nil {
+		return cErr
+	} else if c {
+		return &chainError{fcn, txnID, CODEAlRDEXIST, errors.New("Asset with key already exists")}
+	}
+	return nil
+}
+
+// putStock - add indexkey for range query :- each stock movement is kept under the project inventory,
+// distributions with a negative quantity
+func putStock(stub shim.ChaincodeStubInterface, fcn string, txnID string, data inKindBase, quantity decimal.Decimal) *chainError {
+
+	indexKey, err := stub.CreateCompositeKey(INVIDX, []string{data.ProjectID, data.ItemID, data.Unit, txnID, quantity.String()})
+	if err != nil {
+		return &chainError{fcn, txnID, CODEGENEXCEPTION, err}
+	}
+	err = stub.PutState(indexKey, []byte{0x00})
+	if err != nil {
+		return &chainError{fcn, txnID, CODEGENEXCEPTION, err}
+	}
+	return nil
+}
+
+// stockOnHand - sum of stock movements under the project per item & unit, all items if itemID is empty
+func stockOnHand(stub shim.ChaincodeStubInterface, projectID string, itemID string) ([]stockLine, *chainError) {
+
+	keys := []string{projectID}
+	if len(itemID) != 0 {
+		keys = append(keys, itemID)
+	}
+	invItr, err := stub.GetStateByPartialCompositeKey(INVIDX, keys)
+	if err != nil {
+		return nil, &chainError{"readInventory", projectID, CODEGENEXCEPTION, err}
+	}
+	//Close itrerator when done reading
+	defer invItr.Close()
+	lines := make(map[string]*stockLine)
+	for invItr.HasNext() {
+		rangeItem, err := invItr.Next()
+		if err != nil {
+			return nil, &chainError{"readInventory", projectID, CODEGENEXCEPTION, err}
+		}
+		_, compositeKeyParts, err := stub.SplitCompositeKey(rangeItem.Key)
+		if err != nil {
+			return nil, &chainError{"readInventory", projectID, CODEGENEXCEPTION, err}
+		}
+		// compositeKeyParts[1] represents item ID, [2] unit & [4] quantity
+		lineKey := compositeKeyParts[1] + "~" + compositeKeyParts[2]
+		if _, ok := lines[lineKey]; !ok {
+			lines[lineKey] = &stockLine{ItemID: compositeKeyParts[1], Unit: compositeKeyParts[2]}
+		}
+		quantity, _ := decimal.NewFromString(compositeKeyParts[4])
+		lines[lineKey].Quantity = lines[lineKey].Quantity.Add(quantity)
+	}
+
+	// report stock lines in a deterministic order
+	lineKeys := make([]string, 0, len(lines))
+	for k := range lines {
+		lineKeys = append(lineKeys, k)
+	}
+	sort.Strings(lineKeys)
+	stock := make([]stockLine, 0, len(lines))
+	for _, k := range lineKeys {
+		stock = append(stock, *lines[k])
+	}
+	return stock, nil
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// Verifies scenarios related to in-kind donations and project inventory
+func TestInKindRW(t *testing.T) {
+	fmt.Println("Executing Test - InKindRW")
+
+	// Test data - Refer to test narratives for test description
+	var inKindTable = []struct {
+		txnID          string
+		itemID         string
+		quantity       string
+		unit           string
+		estValue       string
+		currency       string
+		expectedStatus int32
+		testNarrative  string
+	}{
+		{"K101", "Itm001", "100", "box", "500", "", 200, "Happy scenario"},
+		{"K102", "Itm002", "50", "kg", "80", "EUR", 200, "Happy scenario - value in another currency"},
+		{"K103", "Itm001", "0", "box", "500", "", 500, "Check for positive quantity"},
+		{"K103", "Itm001", "10", "", "500", "", 500, "Check for missing unit of measure"},
+		{"K103", "Itm001", "10", "box", "0", "", 500, "Check for positive estimated value"},
+		{"K103", "Itm001", "10", "box", "50", "ABC", 500, "Check for unsupported currency"},
+		{"K103", "Itm003", "10", "box", "50", "", 500, "Check for item ID existence"},
+		{"K101", "Itm001", "10", "box", "50", "", 500, "Check for duplicate txn ID"},
+	}
+	// Distributions are executed in order
+	var distributionTable = []struct {
+		txnID          string
+		quantity       string
+		unit           string
+		expectedStatus int32
+		testNarrative  string
+	}{
+		{"O101", "30", "box", 200, "Happy scenario"},
+		{"O102", "80", "box", 500, "Check for distribution over stock on hand"},
+		{"O103", "5", "kg", 500, "Check for distribution in a unit not in stock"},
+		{"O104", "70", "box", 200, "Distribution of entire stock on hand"},
+		{"O105", "1", "box", 500, "Check for distribution once out of stock"},
+	}
+	// Expected stock on hand
+	var stockTable = []struct {
+		itemID           string
+		unit             string
+		expectedQuantity string
+	}{
+		{"Itm001", "box", "0"},
+		{"Itm002", "kg", "50"},
+	}
+
+	// struct for parsing the shim APIs response
+	type kResp struct {
+		Code    string         `json:"code"`
+		Message string         `json:"message"`
+		Payload inKindDonation `json:"payload"`
+	}
+	type iResp struct {
+		Code    string      `json:"code"`
+		Message string      `json:"message"`
+		Payload []stockLine `json:"payload"`
+	}
+	assert := assert.New(t)
+
+	// Instantiate mockStub using AidChaincode as the target chaincode to unit test
+	stub := shim.NewMockStub("TestStub", new(AidChaincode))
+	assert.NotNil(stub, "Stub is nil, Test stub creation failed")
+
+	uid := uuid.New().String()
+
+	// Add test project ID i.e. P101 and items to state.
+	result := stub.MockInvoke(uid,
+		[][]byte{[]byte(AddProject),
+			[]byte("P101"),
+			[]byte("Prj101")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Saving test project to state failed.")
+
+	for _, itm := range [][]string{{"Itm001", "Medicine"}, {"Itm002", "Food"}} {
+		result = stub.MockInvoke(uid,
+			[][]byte{[]byte(AddItem),
+				[]byte(itm[0]),
+				[]byte(itm[1]),
+				[]byte(itm[1])})
+		assert.EqualValues(shim.OK, result.GetStatus(), "Saving test item to state failed.")
+	}
+
+	// Executing in-kind donation tests
+	for _, test := range inKindTable {
+		result := stub.MockInvoke(uid,
+			[][]byte{[]byte(AddInKindDonation),
+				[]byte(test.txnID),
+				[]byte("vishal"),
+				[]byte("P101"),
+				[]byte(test.itemID),
+				[]byte(test.quantity),
+				[]byte(test.unit),
+				[]byte(test.estValue),
+				[]byte(test.currency)})
+
+		assert.Equal(test.expectedStatus, result.GetStatus(), test.testNarrative+" failed - "+(result.GetMessage()))
+	}
+
+	// Verify in-kind donation read
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(GetInKind),
+			[]byte("K101")})
+	assert.EqualValues(shim.OK, result.GetStatus(), GetInKind+" failed to read the in-kind donation")
+
+	k := &kResp{}
+	err := json.Unmarshal(result.GetPayload(), k)
+	if err != nil {
+		panic(err)
+	}
+	expectedValue, _ := decimal.NewFromString("500")
+	assert.True(expectedValue.Equal(k.Payload.EstValue), "Estimated value mismatch")
+	assert.Equal(DEFCCY, k.Payload.Currency, "Estimated value not in project base currency")
+
+	// Executing distribution tests
+	for _, test := range distributionTable {
+		result := stub.MockInvoke(uid,
+			[][]byte{[]byte(DistributeInKind),
+				[]byte(test.txnID),
+				[]byte("beneficiary"),
+				[]byte("P101"),
+				[]byte("Itm001"),
+				[]byte(test.quantity),
+				[]byte(test.unit)})
+
+		assert.Equal(test.expectedStatus, result.GetStatus(), test.testNarrative+" failed - "+(result.GetMessage()))
+	}
+
+	// Verify stock on hand per item
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(GetInventory),
+			[]byte("P101")})
+	assert.EqualValues(shim.OK, result.GetStatus(), GetInventory+" failed to read the project inventory")
+
+	inv := &iResp{}
+	err = json.Unmarshal(result.GetPayload(), inv)
+	if err != nil {
+		panic(err)
+	}
+	assert.Len(inv.Payload, len(stockTable), "Stock lines mismatch")
+	for i, test := range stockTable {
+		expectedQuantity, _ := decimal.NewFromString(test.expectedQuantity)
+		assert.Equal(test.itemID, inv.Payload[i].ItemID, "Stock item mismatch")
+		assert.Equal(test.unit, inv.Payload[i].Unit, "Stock unit mismatch")
+		assert.True(expectedQuantity.Equal(inv.Payload[i].Quantity), test.itemID+" stock on hand mismatch")
+	}
+
+	// Verify stock on hand of a single item
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(GetInventory),
+			[]byte("P101"),
+			[]byte("Itm002")})
+	assert.EqualValues(shim.OK, result.GetStatus(), GetInventory+" failed to read the item inventory")
+
+	inv = &iResp{}
+	err = json.Unmarshal(result.GetPayload(), inv)
+	if err != nil {
+		panic(err)
+	}
+	assert.Len(inv.Payload, 1, "Item stock lines mismatch")
+}
@@ -5,18 +5,39 @@ import (
 	pb "github.com/hyperledger/fabric/protos/peer"
 )
 
-// AidAssetInterface interface must be implemented by all asset types defined
-// under AidChaincode. The interface enforces that all asset should implement atleast two methods i.e.:
+// AidStateInterface interface must be implemented by all asset types defined under AidChaincode,
+// including those kept under a parent asset e.g. budget lines under project. The interface enforces
+// that all asset should implement atleast two methods i.e.:
 // putState() -> Writes asset state to ledger
 // getState() -> Read asset state from ledger
-type AidAssetInterface interface {
+type AidStateInterface interface {
 	putState(stub shim.ChaincodeStubInterface) pb.Response
 	getState(stub shim.ChaincodeStubInterface) pb.Response
 }
 
-func saveAsset(stub shim.ChaincodeStubInterface, i AidAssetInterface) pb.Response {
+// AidAssetInterface interface must be implemented by all asset types kept under their own key. It extends
+// AidStateInterface with the methods the asset repository relies on i.e.:
+// key() -> Ledger key of the asset
+// docType() -> Doc type of the asset type, 'docType' field of asset state
+// validate() -> Check asset invariants, before every write
+// setVersion() -> Stamp schema version of the document, promoted from embedded versioned
+type AidAssetInterface interface {
+	AidStateInterface
+	key() string
+	docType() string
+	validate() *chainError
+	setVersion(version int)
+}
+
+// AidIndexedInterface may be implemented by assets listed by secondary index keys besides their doc type:
+// indexKeys() -> Index name followed by attributes of each index key, written & deleted along with the asset
+type AidIndexedInterface interface {
+	indexKeys() [][]string
+}
+
+func saveAsset(stub shim.ChaincodeStubInterface, i AidStateInterface) pb.Response {
 	return i.putState(stub)
 }
-func readAsset(stub shim.ChaincodeStubInterface, i AidAssetInterface) pb.Response {
+func readAsset(stub shim.ChaincodeStubInterface, i AidStateInterface) pb.Response {
 	return i.getState(stub)
 }
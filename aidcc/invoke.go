@@ -1,7 +1,6 @@
 package main
 
 import (
-	"errors"
 	"fmt"
 
 	"github.com/hyperledger/fabric/core/chaincode/shim"
@@ -10,15 +9,61 @@ import (
 
 // These are function names from Invoke first parameter
 const (
-	Init        string = "Init"
-	AddProject  string = "AddProject"
-	AddItem     string = "AddItem"
-	AddDonation string = "AddDonation"
-	AddSpend    string = "AddSpend"
-	GetProject  string = "GetProject"
-	GetItem     string = "GetItem"
-	GetDonation string = "GetDonation"
-	GetSpend    string = "GetSpend"
+	Init                 string = "Init"
+	AddProject           string = "AddProject"
+	AddItem              string = "AddItem"
+	AddDonation          string = "AddDonation"
+	AddSpend             string = "AddSpend"
+	ReverseSpend         string = "ReverseSpend"
+	AddPledge            string = "AddPledge"
+	CancelPledge         string = "CancelPledge"
+	AddRecurringDonation string = "AddRecurringDonation"
+	RecordInstalment     string = "RecordInstalment"
+	AddMatchingCampaign  string = "AddMatchingCampaign"
+	AddMilestone         string = "AddMilestone"
+	ApproveMilestone     string = "ApproveMilestone"
+	SetFXRate            string = "SetFXRate"
+	AddBudgetLine        string = "AddBudgetLine"
+	AddInKindDonation    string = "AddInKindDonation"
+	DistributeInKind     string = "DistributeInKind"
+	AddSpendEvidence     string = "AddSpendEvidence"
+	IssueVoucher         string = "IssueVoucher"
+	RedeemVoucher        string = "RedeemVoucher"
+	ExpireVoucher        string = "ExpireVoucher"
+	AddVendor            string = "AddVendor"
+	SetVendorStatus      string = "SetVendorStatus"
+	AddBlockedParty      string = "AddBlockedParty"
+	RemoveBlockedParty   string = "RemoveBlockedParty"
+	RecordScreening      string = "RecordScreening"
+	SetAMLLimits         string = "SetAMLLimits"
+	ApproveDonation      string = "ApproveDonation"
+	RejectDonation       string = "RejectDonation"
+	MigrateState         string = "MigrateState"
+	GetProject           string = "GetProject"
+	GetItem              string = "GetItem"
+	GetDonation          string = "GetDonation"
+	GetSpend             string = "GetSpend"
+	GetReversal          string = "GetReversal"
+	GetPledge            string = "GetPledge"
+	GetRecurringDonation string = "GetRecurringDonation"
+	GetMissedInstalments string = "GetMissedInstalments"
+	GetMatchingCampaign  string = "GetMatchingCampaign"
+	GetMilestone         string = "GetMilestone"
+	GetFXRate            string = "GetFXRate"
+	GetBudgetUtilisation string = "GetBudgetUtilisation"
+	GetInKind            string = "GetInKind"
+	GetInventory         string = "GetInventory"
+	VerifySpendEvidence  string = "VerifySpendEvidence"
+	GetUnevidencedSpends string = "GetUnevidencedSpends"
+	GetVoucher           string = "GetVoucher"
+	GetVendor            string = "GetVendor"
+	GetVendorSpending    string = "GetVendorSpending"
+	GetBlockedParty      string = "GetBlockedParty"
+	GetScreenings        string = "GetScreenings"
+	GetAMLLimits         string = "GetAMLLimits"
+	GetReviewQueue       string = "GetReviewQueue"
+	GetErrorCatalogue    string = "GetErrorCatalogue"
+	ListFunctions        string = "ListFunctions"
 )
 
 // Invoke - Implements shim.Chaincode interface Invoke() method
@@ -29,26 +74,15 @@ func (t *AidChaincode) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 	function, args := stub.GetFunctionAndParameters()
 	logger.Info(fmt.Sprintf("Starting Phantom chaincode Invoke for %s and no of argument passed are %d", function, len(args)))
 
-	if function == Init {
-		return t.Init(stub)
-	} else if function == AddProject {
-		return validateProjectW(stub, args)
-	} else if function == AddItem {
-		return validateItemW(stub, args)
-	} else if function == AddDonation {
-		return validateDonationW(stub, args)
-	} else if function == AddSpend {
-		return validateSpendW(stub, args)
-	} else if function == GetProject {
-		return validateProjectR(stub, args)
-	} else if function == GetItem {
-		return validateItemR(stub, args)
-	} else if function == GetDonation {
-		return validateDonationR(stub, args)
-	} else if function == GetSpend {
-		return validateSpendR(stub, args)
+	// Events emitted while executing the invoke are collected and flushed as one composite event,
+	// events of a failed invoke are discarded along with the transaction
+	ec := &eventCollector{ChaincodeStubInterface: stub}
+	resp := t.dispatch(ec, function, args)
+	if resp.GetStatus() == shim.OK {
+		cErr := ec.flush()
+		if cErr != nil {
+			return errorResponse(stub, cErr)
+		}
 	}
-
-	e := chainError{"Invoke", "", CODEUNKNOWNINVOKE, errors.New("Unknown function invoke")}
-	return shim.Error(e.Error())
+	return resp
 }
@@ -1,11 +1,9 @@
 package main
 
 import (
-	"encoding/json"
-	"errors"
-
 	"github.com/hyperledger/fabric/core/chaincode/shim"
 	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/vishal3152/AIDChaincode/aidcc/events"
 )
 
 // Asset model for item. All asset models are kept in
@@ -16,41 +14,42 @@ type itemBase struct {
 }
 type item struct {
 	ObjectType string   `json:"docType"` // item Type 'GITEM'
-	ItemID     string   `json:"itemID"`  // asset unique key
-	Data       itemBase `json:"data"`    // composition
+	versioned           // schema version of the document
+	ItemID     string   `json:"itemID"` // asset unique key
+	Data       itemBase `json:"data"`   // composition
+}
+
+// key - ledger key of the item
+func (it *item) key() string {
+	return it.ItemID
+}
+
+// docType - doc type of item assets
+func (it *item) docType() string {
+	return GITEM
+}
+
+// validate - check item invariants before it is written
+func (it *item) validate() *chainError {
+	return checkIdentity(it, it.ObjectType)
 }
 
 // Write asset state to ledger
 func (it *item) putState(stub shim.ChaincodeStubInterface) pb.Response {
 
-	// check if itemID already exists
-	c, cErr := checkAsset(stub, it.ItemID)
+	// Write item to ledger, itemID must be unique
+	cErr := newRepository(stub, "putItem").create(it)
 	if cErr != nil {
-		return shim.Error(cErr.Error())
-	}
-	if c {
-		e := &chainError{"putItem", it.ItemID, CODEAlRDEXIST, errors.New("Asset with key already exists")}
-		return shim.Error(e.Error())
-	}
-
-	// Marshal the Item struct to []byte
-	b, err := json.Marshal(it)
-	if err != nil {
-		cErr = &chainError{"putItem", it.ItemID, CODEGENEXCEPTION, err}
-		return shim.Error(cErr.Error())
-	}
-	// Write key-value to ledger
-	err = stub.PutState(it.ItemID, b)
-	if err != nil {
-		cErr = &chainError{"putItem", it.ItemID, CODEGENEXCEPTION, err}
-		return shim.Error(cErr.Error())
+		return errorResponse(stub, cErr)
 	}
 
 	// Emit transaction event for listeners
-	txID := stub.GetTxID()
-	stub.SetEvent((it.ItemID + "_AID_ITMADD_" + txID), nil)
+	cErr = emitEvent(stub, "putItem", it.ItemID, events.ItemCreated, &events.Item{ItemID: it.ItemID, ItemType: it.Data.ItemType, Narrative: it.Data.Narrative})
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
 	r := response{CODEALLAOK, it.ItemID, nil}
-	return shim.Success((r.formatResponse()))
+	return shim.Success((r.formatResponse(stub)))
 }
 
 // Read item state from the ledger
@@ -58,8 +57,8 @@ func (it *item) getState(stub shim.ChaincodeStubInterface) pb.Response {
 
 	item, cErr := queryAsset(stub, it.ItemID)
 	if cErr != nil {
-		return shim.Error(cErr.Error())
+		return errorResponse(stub, cErr)
 	}
 	r := response{CODEALLAOK, "OK", item}
-	return shim.Success((r.formatResponse()))
+	return shim.Success((r.formatResponse(stub)))
 }
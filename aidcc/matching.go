@@ -0,0 +1,241 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/shopspring/decimal"
+	"github.com/vishal3152/AIDChaincode/aidcc/events"
+)
+
+// Asset model for matching gift campaign. All asset models are kept in
+// private scope i.e. they are not exported and ramin invisible to other packages
+// A sponsor matches every donation made to the project within the validity window at a ratio
+// until the cap is exhausted.
+type matchingBase struct {
+	ProjectID    string          `json:"projectID"`
+	Ratio        decimal.Decimal `json:"ratio"`        // sponsor amount per unit donated e.g. 1 for 1:1 match
+	Cap          decimal.Decimal `json:"cap"`          // maximum amount matched by the sponsor
+	Currency     string          `json:"currency"`     // ISO 4217 code, only donations in the currency are matched
+	MatchedAmt   decimal.Decimal `json:"matchedAmt"`   // amount matched so far
+	RemainingCap decimal.Decimal `json:"remainingCap"` // cap less matched amount
+	StartDt      time.Time       `json:"startDt"`
+	EndDt        time.Time       `json:"endDt"` // inclusive
+}
+
+type matchingCampaign struct {
+	ObjectType string       `json:"docType"` // matching campaign Type 'DONMTC'
+	versioned               // schema version of the document
+	CampaignID string       `json:"campaignID"` // asset unique key
+	Sponsor    string       `json:"sponsor"`
+	Data       matchingBase `json:"data"` // composition
+}
+
+// key - ledger key of the matching campaign
+func (m *matchingCampaign) key() string {
+	return m.CampaignID
+}
+
+// docType - doc type of matching campaign assets
+func (m *matchingCampaign) docType() string {
+	return DONMTC
+}
+
+// validate - check matching campaign invariants before it is written
+func (m *matchingCampaign) validate() *chainError {
+	return checkIdentity(m, m.ObjectType)
+}
+
+// matchKey - derive matched donation key from the original donation txnID and campaign ID
+func matchKey(txnID string, campaignID string) string {
+	return txnID + "_" + campaignID
+}
+
+// Write matching campaign to ledger
+func (m *matchingCampaign) putState(stub shim.ChaincodeStubInterface) pb.Response {
+
+	// check if affiliated project exists
+	c, cErr := checkAsset(stub, m.Data.ProjectID)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	} else if !c {
+		e := &chainError{"putCampaign", m.CampaignID, CODENOTFOUND, errors.New("Affiliated project not found")}
+		return errorResponse(stub, e)
+	}
+
+	// resolve campaign currency, defaults to project's base currency
+	m.Data.Currency, m.Data.Cap, cErr = resolveCurrency(stub, "putCampaign", m.CampaignID, m.Data.ProjectID, m.Data.Currency, m.Data.Cap)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	m.Data.RemainingCap = m.Data.Cap.Sub(m.Data.MatchedAmt)
+
+	// check if campaignID is unique
+	c, cErr = checkAsset(stub, m.CampaignID)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	} else if c {
+		e := &chainError{"putCampaign", m.CampaignID, CODEAlRDEXIST, errors.New("Asset with key already exists")}
+		return errorResponse(stub, e)
+	}
+
+	cErr = m.store(stub, "putCampaign")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	// Add indexkey for range query :- campaigns are looked up by project whenever a donation is made
+	indexKey, err := stub.CreateCompositeKey(CMPIDX, []string{m.Data.ProjectID, m.CampaignID})
+	if err != nil {
+		cErr = &chainError{"putCampaign", m.CampaignID, CODEGENEXCEPTION, err}
+		return errorResponse(stub, cErr)
+	}
+	err = stub.PutState(indexKey, []byte{0x00})
+	if err != nil {
+		cErr = &chainError{"putCampaign", m.CampaignID, CODEGENEXCEPTION, err}
+		return errorResponse(stub, cErr)
+	}
+
+	// Emit transaction event for listeners
+	cErr = emitEvent(stub, "putCampaign", m.CampaignID, events.MatchingCampaignCreated, &events.MatchingCampaign{CampaignID: m.CampaignID, ProjectID: m.Data.ProjectID, Sponsor: m.Sponsor, Ratio: m.Data.Ratio, Cap: m.Data.Cap, Currency: m.Data.Currency, StartDt: m.Data.StartDt, EndDt: m.Data.EndDt})
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	r := response{CODEALLAOK, m.CampaignID, nil}
+	return shim.Success((r.formatResponse(stub)))
+}
+
+// Read matching campaign from the ledger, including its remaining cap
+func (m *matchingCampaign) getState(stub shim.ChaincodeStubInterface) pb.Response {
+
+	cmp, cErr := queryAsset(stub, m.CampaignID)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	r := response{CODEALLAOK, "OK", cmp}
+	return shim.Success((r.formatResponse(stub)))
+}
+
+// match - write a sponsor donation matching the qualifying donation, if campaign is active and
+// has cap remaining. Matched amount is capped at the remaining cap.
+func (m *matchingCampaign) match(stub shim.ChaincodeStubInterface, d *donation) *chainError {
+
+	if d.Data.TimeStamp.Before(m.Data.StartDt) || !d.Data.TimeStamp.Before(m.Data.EndDt.AddDate(0, 0, 1)) {
+		return nil
+	}
+	if !m.Data.RemainingCap.IsPositive() || d.Data.Currency != m.Data.Currency {
+		return nil
+	}
+	precision, _ := precisionOf(m.Data.Currency)
+	amount := d.Data.Amount.Mul(m.Data.Ratio).RoundBank(precision)
+	if amount.GreaterThan(m.Data.RemainingCap) {
+		amount = m.Data.RemainingCap
+	}
+	if !amount.IsPositive() {
+		return nil
+	}
+
+	// check if matched donation txnID is unique
+	matchTxnID := matchKey(d.TxnID, m.CampaignID)
+	c, cErr := checkAsset(stub, matchTxnID)
+	if cErr != nil {
+		return cErr
+	} else if c {
+		return &chainError{"matchDonation", matchTxnID, CODEAlRDEXIST, errors.New("Asset with key already exists")}
+	}
+
+	// check if sponsor is not a sanctioned party, donation of a sanctioned sponsor is not matched while
+	// the qualifying donation is still recorded along with the screening
+	cErr = screenParty(stub, "matchDonation", matchTxnID, "sponsor", m.Sponsor)
+	if cErr != nil && cErr.code == CODESANCTIONED {
+		return nil
+	} else if cErr != nil {
+		return cErr
+	}
+
+	m.Data.MatchedAmt = m.Data.MatchedAmt.Add(amount)
+	m.Data.RemainingCap = m.Data.Cap.Sub(m.Data.MatchedAmt)
+	cErr = m.store(stub, "matchDonation")
+	if cErr != nil {
+		return cErr
+	}
+	d.MatchedBy = append(d.MatchedBy, matchTxnID)
+
+	// sponsor donation is held to AML limits as any other donation, match over any limit is parked for
+	// review while its amount stays reserved under the campaign cap
+	donBase := donationBase{ProjectID: d.Data.ProjectID, ItemID: d.Data.ItemID, Amount: amount, Currency: m.Data.Currency, TimeStamp: d.Data.TimeStamp}
+	md := &donation{ObjectType: DONIN, TxnID: matchTxnID, Donor: m.Sponsor, CampaignID: m.CampaignID, MatchOf: d.TxnID, Data: donBase}
+	reasons, cErr := md.admit(stub, "matchDonation")
+	if cErr != nil {
+		return cErr
+	}
+	if len(reasons) != 0 {
+		cErr = md.park(stub, reasons)
+		if cErr != nil {
+			return cErr
+		}
+		return emitEvent(stub, "matchDonation", matchTxnID, events.DonationHeldForReview, md.event())
+	}
+	cErr = md.write(stub, "matchDonation")
+	if cErr != nil {
+		return cErr
+	}
+	return emitEvent(stub, "matchDonation", matchTxnID, events.DonationRecorded, md.event())
+}
+
+// unmatch - return the amount of a rejected sponsor match to its campaign cap
+func unmatch(stub shim.ChaincodeStubInterface, fcn string, md *donation) *chainError {
+
+	m := &matchingCampaign{CampaignID: md.CampaignID}
+	cErr := newRepository(stub, fcn).read(m)
+	if cErr != nil {
+		return cErr
+	}
+	m.Data.MatchedAmt = m.Data.MatchedAmt.Sub(md.Data.Amount)
+	m.Data.RemainingCap = m.Data.Cap.Sub(m.Data.MatchedAmt)
+	return m.store(stub, fcn)
+}
+
+// store - write campaign state to the ledger
+func (m *matchingCampaign) store(stub shim.ChaincodeStubInterface, fcn string) *chainError {
+	return newRepository(stub, fcn).save(m)
+}
+
+// applyMatching - apply all matching campaigns of the donation's project to the donation
+func applyMatching(stub shim.ChaincodeStubInterface, d *donation) *chainError {
+
+	cmpItr, err := stub.GetStateByPartialCompositeKey(CMPIDX, []string{d.Data.ProjectID})
+	if err != nil {
+		return &chainError{"matchDonation", d.TxnID, CODEGENEXCEPTION, err}
+	}
+	//Close itrerator when done reading
+	defer cmpItr.Close()
+	for cmpItr.HasNext() {
+		rangeItem, err := cmpItr.Next()
+		if err != nil {
+			return &chainError{"matchDonation", d.TxnID, CODEGENEXCEPTION, err}
+		}
+		_, compositeKeyParts, err := stub.SplitCompositeKey(rangeItem.Key)
+		if err != nil {
+			return &chainError{"matchDonation", d.TxnID, CODEGENEXCEPTION, err}
+		}
+		// compositeKeyParts[1] represents campaign ID
+		cmpBytes, cErr := queryAsset(stub, compositeKeyParts[1])
+		if cErr != nil {
+			return cErr
+		}
+		m := &matchingCampaign{}
+		err = json.Unmarshal(cmpBytes, m)
+		if err != nil {
+			return &chainError{"matchDonation", d.TxnID, CODEGENEXCEPTION, err}
+		}
+		cErr = m.match(stub, d)
+		if cErr != nil {
+			return cErr
+		}
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// Verifies scenarios related to matching gift campaigns
+func TestMatchingRW(t *testing.T) {
+	fmt.Println("Executing Test - MatchingRW")
+
+	today := time.Now().UTC()
+	startDt := today.AddDate(0, 0, -1).Format(DATEFMT)
+	endDt := today.AddDate(0, 0, 1).Format(DATEFMT)
+	pastDt := today.AddDate(0, 0, -7).Format(DATEFMT)
+
+	// Test data - Refer to test narratives for test description
+	var campaignTable = []struct {
+		campaignID     string
+		projectID      string
+		ratio          string
+		cap            string
+		startDt        string
+		endDt          string
+		expectedStatus int32
+		testNarrative  string
+	}{
+		{"C101", "P101", "1", "150", startDt, endDt, 200, "Happy scenario"},
+		{"C102", "P101", "2", "500", pastDt, pastDt, 200, "Happy scenario - expired campaign"},
+		{"C103", "P101", "0", "150", startDt, endDt, 500, "Check for positive ratio"},
+		{"C103", "P101", "1", "", startDt, endDt, 500, "Check for positive cap"},
+		{"C103", "P101", "1", "150", "", endDt, 500, "Check for start date"},
+		{"C103", "P101", "1", "150", endDt, startDt, 500, "Check for end date before start date"},
+		{"C103", "P102", "1", "150", startDt, endDt, 500, "Check for project ID existence"},
+		{"C101", "P101", "1", "150", startDt, endDt, 500, "Check for duplicate campaign ID"},
+	}
+	// Donations are executed in order against campaign C101 with cap 150
+	var donationTable = []struct {
+		txnID           string
+		amount          string
+		expectedMatch   string
+		expectedRemains string
+		testNarrative   string
+	}{
+		{"D101", "100", "100", "50", "Full match"},
+		{"D102", "100", "50", "0", "Match capped at remaining cap"},
+		{"D103", "100", "0", "0", "No match once cap is exhausted"},
+	}
+
+	// struct for parsing the shim APIs response
+	type mResp struct {
+		Code    string           `json:"code"`
+		Message string           `json:"message"`
+		Payload matchingCampaign `json:"payload"`
+	}
+	type dResp struct {
+		Code    string   `json:"code"`
+		Message string   `json:"message"`
+		Payload donation `json:"payload"`
+	}
+	type pResp struct {
+		Code    string  `json:"code"`
+		Message string  `json:"message"`
+		Payload project `json:"payload"`
+	}
+	assert := assert.New(t)
+
+	// Instantiate mockStub using AidChaincode as the target chaincode to unit test
+	stub := shim.NewMockStub("TestStub", new(AidChaincode))
+	assert.NotNil(stub, "Stub is nil, Test stub creation failed")
+
+	uid := uuid.New().String()
+
+	// Add test project ID i.e. P101 and item ID i.e. Itm001 to state.
+	result := stub.MockInvoke(uid,
+		[][]byte{[]byte(AddProject),
+			[]byte("P101"),
+			[]byte("Prj101")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Saving test project to state failed.")
+
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(AddItem),
+			[]byte("Itm001"),
+			[]byte("Item001"),
+			[]byte("Medicine")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Saving test item to state failed.")
+
+	// Executing campaign tests
+	for _, test := range campaignTable {
+		result := stub.MockInvoke(uid,
+			[][]byte{[]byte(AddMatchingCampaign),
+				[]byte(test.campaignID),
+				[]byte("acme"),
+				[]byte(test.projectID),
+				[]byte(test.ratio),
+				[]byte(test.cap),
+				[]byte(test.startDt),
+				[]byte(test.endDt)})
+
+		assert.Equal(test.expectedStatus, result.GetStatus(), test.testNarrative+" failed - "+(result.GetMessage()))
+	}
+
+	// Executing donation tests
+	total := decimal.New(0, 0)
+	for _, test := range donationTable {
+		result := stub.MockInvoke(uid,
+			[][]byte{[]byte(AddDonation),
+				[]byte(test.txnID),
+				[]byte("vishal"),
+				[]byte("P101"),
+				[]byte("Itm001"),
+				[]byte(test.amount)})
+		assert.EqualValues(shim.OK, result.GetStatus(), test.testNarrative+" failed - "+(result.GetMessage()))
+
+		amount, _ := decimal.NewFromString(test.amount)
+		expectedMatch, _ := decimal.NewFromString(test.expectedMatch)
+		total = total.Add(amount).Add(expectedMatch)
+
+		// Verify matched donation is linked to the original donation
+		result = stub.MockInvoke(uid,
+			[][]byte{[]byte(GetDonation),
+				[]byte(matchKey(test.txnID, "C101"))})
+		if expectedMatch.IsZero() {
+			assert.EqualValues(shim.ERROR, result.GetStatus(), test.testNarrative+" failed - unexpected matched donation")
+		} else {
+			assert.EqualValues(shim.OK, result.GetStatus(), test.testNarrative+" failed - matched donation not found")
+			d := &dResp{}
+			err := json.Unmarshal(result.GetPayload(), d)
+			if err != nil {
+				panic(err)
+			}
+			assert.Equal(test.txnID, d.Payload.MatchOf, test.testNarrative+" failed - matched donation not linked")
+			assert.Equal("acme", d.Payload.Donor, test.testNarrative+" failed - matched donation not from sponsor")
+			assert.True(expectedMatch.Equal(d.Payload.Data.Amount), test.testNarrative+" failed - matched amount mismatch")
+		}
+
+		// Verify remaining cap
+		result = stub.MockInvoke(uid,
+			[][]byte{[]byte(GetMatchingCampaign),
+				[]byte("C101")})
+		assert.EqualValues(shim.OK, result.GetStatus(), GetMatchingCampaign+" failed to read the campaign data")
+
+		m := &mResp{}
+		err := json.Unmarshal(result.GetPayload(), m)
+		if err != nil {
+			panic(err)
+		}
+		expectedRemains, _ := decimal.NewFromString(test.expectedRemains)
+		assert.True(expectedRemains.Equal(m.Payload.Data.RemainingCap), test.testNarrative+" failed - remaining cap mismatch")
+	}
+
+	// Verify donations and matched donations reflect under project fund, expired campaign never matches
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(GetProject),
+			[]byte("P101")})
+	assert.EqualValues(shim.OK, result.GetStatus(), GetProject+" failed to read the project data")
+
+	p := &pResp{}
+	err := json.Unmarshal(result.GetPayload(), p)
+	if err != nil {
+		panic(err)
+	}
+	assert.True(total.Equal(p.Payload.Data.AvlFund), "Matched donations fail to reflect under project fund")
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"unicode/utf8"
+
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/vishal3152/AIDChaincode/aidcc/events"
+)
+
+// Every asset document carries the schema version of its asset type. Documents written before versioning
+// carry none i.e. version 0. Older documents are upgraded to the latest version whenever they are read, and
+// rewritten in place by MigrateState. A change to the stored shape of an asset type raises its version in
+// schemaVersions and registers the step upgrading a document from the previous version in schemaUpgrades.
+
+// versioned - schema version of an asset document, embedded by all assets kept under their own key
+type versioned struct {
+	SchemaVersion int `json:"schemaVersion"`
+}
+
+// setVersion - stamp the schema version of the document
+func (v *versioned) setVersion(version int) {
+	v.SchemaVersion = version
+}
+
+// schemaVersions - latest schema version by doc type
+var schemaVersions = map[string]int{
+	GPRJCT: 1, GITEM: 1, DONIN: 1, DONREV: 1, PLEDGE: 1, DONREC: 1, DONMTC: 1,
+	FXRATE: 1, INKIN: 1, INKOUT: 1, VOUCHR: 1, VENDOR: 1, BLKPTY: 1, AMLCFG: 1, DONOUT: 1,
+}
+
+// schemaUpgrades - steps upgrading a document of a doc type from a version to the next one. Versions
+// without a step differ in the version stamp only
+var schemaUpgrades = map[string]map[int]func(doc map[string]interface{}){
+	GPRJCT: {0: upgradeProject},
+}
+
+// assetTypes - empty asset of a doc type, to read documents of the type into
+var assetTypes = map[string]func() AidAssetInterface{
+	GPRJCT: func() AidAssetInterface { return &project{} },
+	GITEM:  func() AidAssetInterface { return &item{} },
+	DONIN:  func() AidAssetInterface { return &donation{} },
+	DONOUT: func() AidAssetInterface { return &spend{} },
+	DONREV: func() AidAssetInterface { return &reversal{} },
+	PLEDGE: func() AidAssetInterface { return &pledge{} },
+	DONREC: func() AidAssetInterface { return &recurringDonation{} },
+	DONMTC: func() AidAssetInterface { return &matchingCampaign{} },
+	FXRATE: func() AidAssetInterface { return &fxRate{} },
+	INKIN:  func() AidAssetInterface { return &inKindDonation{} },
+	INKOUT: func() AidAssetInterface { return &inKindSpend{} },
+	VOUCHR: func() AidAssetInterface { return &voucher{} },
+	VENDOR: func() AidAssetInterface { return &vendor{} },
+	BLKPTY: func() AidAssetInterface { return &blockedParty{} },
+	AMLCFG: func() AidAssetInterface { return &amlLimits{} },
+}
+
+// migrationBatch - outcome of a MigrateState batch
+type migrationBatch struct {
+	AssetType string `json:"assetType"`
+	Scanned   int    `json:"scanned"`  // documents of the type in the batch
+	Migrated  int    `json:"migrated"` // documents rewritten to the latest version
+	Bookmark  string `json:"bookmark"` // key to resume from, empty once all documents are migrated
+}
+
+// upgradeDoc - upgrade a stored document to the latest schema version of its doc type. Documents that are
+// not assets or already at the latest version are returned as is
+func upgradeDoc(b []byte) ([]byte, bool, error) {
+
+	doc := make(map[string]interface{})
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if dec.Decode(&doc) != nil {
+		return b, false, nil
+	}
+	docType, _ := doc["docType"].(string)
+	latest, ok := schemaVersions[docType]
+	if !ok {
+		return b, false, nil
+	}
+	version := 0
+	if n, ok := doc["schemaVersion"].(json.Number); ok {
+		v, err := n.Int64()
+		if err != nil {
+			return nil, false, err
+		}
+		version = int(v)
+	}
+	if version >= latest {
+		return b, false, nil
+	}
+	for ; version < latest; version++ {
+		if upgrade, ok := schemaUpgrades[docType][version]; ok {
+			upgrade(doc)
+		}
+	}
+	doc["schemaVersion"] = latest
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return nil, false, err
+	}
+	return b, true, nil
+}
+
+// migrateState - rewrite a batch of documents of the doc type to the latest schema version, starting at the
+// bookmark. Documents are rewritten through the repository, hence also indexed by doc type
+func migrateState(stub shim.ChaincodeStubInterface, assetType string, batchSize int, bookmark string) pb.Response {
+
+	// Range covers simple keys only, composite keys start with 0x00 and sort before the 1st simple key
+	startKey := bookmark
+	if len(startKey) == 0 {
+		startKey = "\x01"
+	}
+	stateItr, err := stub.GetStateByRange(startKey, string(utf8.MaxRune))
+	if err != nil {
+		cErr := &chainError{"migrateState", assetType, CODEGENEXCEPTION, err}
+		return errorResponse(stub, cErr)
+	}
+	defer stateItr.Close()
+
+	batch := &migrationBatch{AssetType: assetType}
+	repo := newRepository(stub, "migrateState")
+	for stateItr.HasNext() {
+		kv, err := stateItr.Next()
+		if err != nil {
+			cErr := &chainError{"migrateState", assetType, CODEGENEXCEPTION, err}
+			return errorResponse(stub, cErr)
+		}
+		probe := struct {
+			ObjectType string `json:"docType"`
+		}{}
+		if json.Unmarshal(kv.Value, &probe) != nil || probe.ObjectType != assetType {
+			continue
+		}
+		// batch is full, resume from this document
+		if batch.Scanned == batchSize {
+			batch.Bookmark = kv.Key
+			break
+		}
+		batch.Scanned++
+
+		b, upgraded, err := upgradeDoc(kv.Value)
+		if err != nil {
+			cErr := &chainError{"migrateState", kv.Key, CODEGENEXCEPTION, err}
+			return errorResponse(stub, cErr)
+		}
+		if !upgraded {
+			continue
+		}
+		a := assetTypes[assetType]()
+		err = json.Unmarshal(b, a)
+		if err != nil {
+			cErr := &chainError{"migrateState", kv.Key, CODEGENEXCEPTION, err}
+			return errorResponse(stub, cErr)
+		}
+		cErr := repo.save(a)
+		if cErr != nil {
+			return errorResponse(stub, cErr)
+		}
+		batch.Migrated++
+	}
+
+	// Emit transaction event for listeners
+	cErr := emitEvent(stub, "migrateState", assetType, events.StateMigrated, &events.Migration{AssetType: assetType, Migrated: batch.Migrated, Bookmark: batch.Bookmark})
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	b, _ := json.Marshal(batch)
+	r := response{CODEALLAOK, assetType, b}
+	return shim.Success((r.formatResponse(stub)))
+}
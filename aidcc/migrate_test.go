@@ -0,0 +1,202 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// Verifies scenarios related to schema versions & MigrateState
+func TestMigrateState(t *testing.T) {
+	fmt.Println("Executing Test - MigrateState")
+	defer os.Unsetenv("ROLE")
+
+	// Legacy documents, written before schema versioning
+	legacyDocs := map[string]string{
+		"Itm001": `{"docType":"` + GITEM + `","itemID":"Itm001","data":{"itemType":"Item001","narrative":"Medicine"}}`,
+		"Itm002": `{"docType":"` + GITEM + `","itemID":"Itm002","data":{"itemType":"Item002","narrative":"Food"}}`,
+		"Itm003": `{"docType":"` + GITEM + `","itemID":"Itm003","data":{"itemType":"Item003","narrative":"Shelter"}}`,
+		"P101":   `{"docType":"` + GPRJCT + `","projectID":"P101","data":{"projectName":"Prj101"}}`,
+	}
+
+	// Test data - Refer to test narratives for test description. Invokes are executed in order
+	var migrateTable = []struct {
+		args             []string
+		role             string
+		expectedStatus   int32
+		expectedCode     string
+		expectedMigrated int
+		resume           bool // bookmark expected, passed on to the next invoke
+		testNarrative    string
+	}{
+		{[]string{GITEM, "2"}, ROLEVERIFIER, 500, CODEFORBIDDEN, 0, false, "Check for caller role"},
+		{[]string{GITEM}, ROLEADMIN, 500, CODEUNPROCESSABLEENTITY, 0, false, "Check for no of input args"},
+		{[]string{"AidDonor", "2"}, ROLEADMIN, 500, CODEUNPROCESSABLEENTITY, 0, false, "Check for unknown asset type"},
+		{[]string{GITEM, "0"}, ROLEADMIN, 500, CODEUNPROCESSABLEENTITY, 0, false, "Check for batch size below 1"},
+		{[]string{GITEM, "five"}, ROLEADMIN, 500, CODEUNPROCESSABLEENTITY, 0, false, "Check for non numeric batch size"},
+		{[]string{GITEM, "2"}, ROLEADMIN, 200, CODEALLAOK, 2, true, "Happy scenario - 1st batch"},
+		{[]string{GITEM, "2"}, ROLEADMIN, 200, CODEALLAOK, 1, false, "Happy scenario - resume from bookmark"},
+		{[]string{GITEM, "5"}, ROLEADMIN, 200, CODEALLAOK, 0, false, "Happy scenario - rerun skips migrated documents"},
+	}
+
+	// struct for parsing the response envelope
+	type mResp struct {
+		Code    string         `json:"code"`
+		Payload migrationBatch `json:"payload"`
+	}
+	assert := assert.New(t)
+
+	// Instantiate mockStub using AidChaincode as the target chaincode to unit test
+	stub := shim.NewMockStub("TestStub", new(AidChaincode))
+	assert.NotNil(stub, "Stub is nil, Test stub creation failed")
+
+	stub.MockTransactionStart(uuid.New().String())
+	for key, doc := range legacyDocs {
+		err := stub.PutState(key, []byte(doc))
+		if err != nil {
+			panic(err)
+		}
+	}
+	stub.MockTransactionEnd("")
+
+	// Legacy documents are upgraded on read
+	result := stub.MockInvoke(uuid.New().String(), [][]byte{[]byte(GetItem), []byte("Itm001")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "GetItem failed to read legacy item - "+result.GetMessage())
+	iResp := struct {
+		Payload item `json:"payload"`
+	}{}
+	err := json.Unmarshal(result.GetPayload(), &iResp)
+	if err != nil {
+		panic(err)
+	}
+	assert.Equal(schemaVersions[GITEM], iResp.Payload.SchemaVersion, "Legacy item not upgraded on read")
+	assert.Equal("Medicine", iResp.Payload.Data.Narrative, "Legacy item narrative mismatch")
+
+	// Executing MigrateState tests
+	bookmark := ""
+	for _, test := range migrateTable {
+		os.Setenv("ROLE", test.role)
+		args := [][]byte{[]byte(MigrateState)}
+		for _, arg := range test.args {
+			args = append(args, []byte(arg))
+		}
+		if len(bookmark) != 0 {
+			args = append(args, []byte(bookmark))
+		}
+		result := stub.MockInvoke(uuid.New().String(), args)
+		assert.Equal(test.expectedStatus, result.GetStatus(), test.testNarrative+" failed - "+result.GetMessage())
+
+		m := &mResp{}
+		err := json.Unmarshal(result.GetPayload(), m)
+		if err != nil {
+			panic(err)
+		}
+		assert.Equal(test.expectedCode, m.Code, test.testNarrative+" failed - code mismatch")
+		if test.expectedStatus != 200 {
+			continue
+		}
+		assert.Equal(test.expectedMigrated, m.Payload.Migrated, test.testNarrative+" failed - migrated count mismatch")
+		assert.Equal(test.resume, len(m.Payload.Bookmark) != 0, test.testNarrative+" failed - bookmark mismatch")
+		bookmark = m.Payload.Bookmark
+	}
+
+	// Verify stored documents, migrated items are versioned & listed by the repository
+	stub.MockTransactionStart(uuid.New().String())
+	defer stub.MockTransactionEnd("")
+	for key := range legacyDocs {
+		probe := &versioned{}
+		err := json.Unmarshal(stub.State[key], probe)
+		if err != nil {
+			panic(err)
+		}
+		if key == "P101" {
+			assert.Equal(0, probe.SchemaVersion, "Document of another asset type migrated")
+		} else {
+			assert.Equal(schemaVersions[GITEM], probe.SchemaVersion, "Stored item "+key+" not migrated")
+		}
+	}
+	items, cErr := newRepository(stub, "testMigrateState").list(GITEM, assetTypes[GITEM])
+	assert.Nil(cErr, "Repository failed to list migrated items")
+	assert.Len(items, 3, "Migrated items not indexed")
+}
+
+// Verifies projects written before schema versioning are upgraded to funds per currency
+func TestMigrateProject(t *testing.T) {
+	fmt.Println("Executing Test - MigrateProject")
+	defer os.Unsetenv("ROLE")
+
+	// Projects as written before the series, funds held at top level & no base currency
+	legacyDocs := map[string]string{
+		"P101": `{"docType":"` + GPRJCT + `","projectID":"P101","data":{"projectName":"Prj101","runBy":"Test Caller","startDt":"2019-01-01T00:00:00Z","avlFund":"500","spentFund":"20"}}`,
+		"P102": `{"docType":"` + GPRJCT + `","projectID":"P102","data":{"projectName":"Prj102","runBy":"Test Caller","startDt":"2019-01-01T00:00:00Z","avlFund":"500","spentFund":"20"}}`,
+	}
+
+	// struct for parsing the response envelope
+	type pResp struct {
+		Payload project `json:"payload"`
+	}
+	type mResp struct {
+		Code    string         `json:"code"`
+		Payload migrationBatch `json:"payload"`
+	}
+	assert := assert.New(t)
+
+	// Instantiate mockStub using AidChaincode as the target chaincode to unit test
+	stub := shim.NewMockStub("TestStub", new(AidChaincode))
+	assert.NotNil(stub, "Stub is nil, Test stub creation failed")
+
+	stub.MockTransactionStart(uuid.New().String())
+	for key, doc := range legacyDocs {
+		err := stub.PutState(key, []byte(doc))
+		if err != nil {
+			panic(err)
+		}
+	}
+	stub.MockTransactionEnd("")
+
+	// verify upgraded funds & base currency of a project
+	verify := func(p *project, narrative string) {
+		avlFund, _ := decimal.NewFromString("500")
+		spentFund, _ := decimal.NewFromString("20")
+		assert.Equal(schemaVersions[GPRJCT], p.SchemaVersion, narrative+" - schema version mismatch")
+		assert.Equal(DEFCCY, p.Data.BaseCurrency, narrative+" - base currency mismatch")
+		if assert.Len(p.Data.Balances, 1, narrative+" - balance currencies mismatch") {
+			assert.True(avlFund.Equal(p.Data.Balances[DEFCCY].AvlFund), narrative+" - available fund mismatch")
+			assert.True(spentFund.Equal(p.Data.Balances[DEFCCY].SpentFund), narrative+" - spent fund mismatch")
+		}
+	}
+
+	// Legacy project is upgraded on read
+	result := stub.MockInvoke(uuid.New().String(), [][]byte{[]byte(GetProject), []byte("P101")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "GetProject failed to read legacy project - "+result.GetMessage())
+	p := &pResp{}
+	err := json.Unmarshal(result.GetPayload(), p)
+	if err != nil {
+		panic(err)
+	}
+	verify(&p.Payload, "Legacy project read")
+
+	// Legacy project not yet read is rewritten by MigrateState
+	os.Setenv("ROLE", ROLEADMIN)
+	result = stub.MockInvoke(uuid.New().String(), [][]byte{[]byte(MigrateState), []byte(GPRJCT), []byte("5")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "MigrateState failed to migrate legacy project - "+result.GetMessage())
+	m := &mResp{}
+	err = json.Unmarshal(result.GetPayload(), m)
+	if err != nil {
+		panic(err)
+	}
+	assert.Equal(1, m.Payload.Migrated, "Legacy project not migrated")
+
+	stored := &project{}
+	err = json.Unmarshal(stub.State["P102"], stored)
+	if err != nil {
+		panic(err)
+	}
+	verify(stored, "Stored project")
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/shopspring/decimal"
+	"github.com/vishal3152/AIDChaincode/aidcc/events"
+)
+
+// Asset model for project milestone. All asset models are kept in
+// private scope i.e. they are not exported and ramin invisible to other packages
+// Milestones are kept under the project. Once a project defines milestones, cumulative spends
+// are capped at the sum of approved milestone budgets.
+type milestone struct {
+	MilestoneID string          `json:"milestoneID"`
+	Narrative   string          `json:"narrative"`
+	Budget      decimal.Decimal `json:"budget"`
+	Approved    bool            `json:"approved"`
+	ApprovedBy  string          `json:"approvedBy,omitempty"`
+	ApprovedDt  time.Time       `json:"approvedDt"`
+}
+
+type projectMilestone struct {
+	ProjectID string    `json:"projectID"` // affiliated project, milestone ID is unique within the project
+	Data      milestone `json:"data"`      // composition
+}
+
+// Write milestone under the project
+func (pm *projectMilestone) putState(stub shim.ChaincodeStubInterface) pb.Response {
+
+	p := &project{ProjectID: pm.ProjectID}
+	cErr := p.load(stub, "putMilestone")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	// milestones cap all spends of the project, only the project owner may add them
+	cErr = checkProjectOwner(stub, "putMilestone", p)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	// check if milestoneID is unique within the project
+	for _, m := range p.Data.Milestones {
+		if m.MilestoneID == pm.Data.MilestoneID {
+			cErr = &chainError{"putMilestone", pm.Data.MilestoneID, CODEAlRDEXIST, errors.New("Milestone already exists under the project")}
+			return errorResponse(stub, cErr)
+		}
+	}
+
+	// budget is in project's base currency
+	cErr = checkPrecision("putMilestone", pm.Data.MilestoneID, p.baseCurrency(), pm.Data.Budget)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	p.Data.Milestones = append(p.Data.Milestones, pm.Data)
+	cErr = p.store(stub, "putMilestone")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	// Emit transaction event for listeners
+	cErr = emitEvent(stub, "putMilestone", pm.ProjectID, events.MilestoneAdded, &events.Milestone{ProjectID: pm.ProjectID, MilestoneID: pm.Data.MilestoneID, Narrative: pm.Data.Narrative, Budget: pm.Data.Budget})
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	r := response{CODEALLAOK, pm.Data.MilestoneID, nil}
+	return shim.Success((r.formatResponse(stub)))
+}
+
+// Read milestone from the project
+func (pm *projectMilestone) getState(stub shim.ChaincodeStubInterface) pb.Response {
+
+	cErr := pm.load(stub, "readMilestone")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	b, err := json.Marshal(pm)
+	if err != nil {
+		cErr = &chainError{"readMilestone", pm.Data.MilestoneID, CODEGENEXCEPTION, err}
+		return errorResponse(stub, cErr)
+	}
+	r := response{CODEALLAOK, "OK", b}
+	return shim.Success((r.formatResponse(stub)))
+}
+
+// Approve milestone, releasing its budget for spending
+func (pm *projectMilestone) approveState(stub shim.ChaincodeStubInterface, verifier string, timeStamp time.Time) pb.Response {
+
+	p := &project{ProjectID: pm.ProjectID}
+	cErr := p.load(stub, "approveMilestone")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	for i := range p.Data.Milestones {
+		m := &p.Data.Milestones[i]
+		if m.MilestoneID != pm.Data.MilestoneID {
+			continue
+		}
+		if m.Approved {
+			cErr = &chainError{"approveMilestone", m.MilestoneID, CODENOTALLWD, errors.New("Milestone already approved by " + m.ApprovedBy)}
+			return errorResponse(stub, cErr)
+		}
+		m.Approved = true
+		m.ApprovedBy = verifier
+		m.ApprovedDt = timeStamp
+		cErr = p.store(stub, "approveMilestone")
+		if cErr != nil {
+			return errorResponse(stub, cErr)
+		}
+
+		// Emit transaction event for listeners
+		cErr = emitEvent(stub, "approveMilestone", pm.ProjectID, events.MilestoneApproved, &events.Milestone{ProjectID: pm.ProjectID, MilestoneID: m.MilestoneID, Narrative: m.Narrative, Budget: m.Budget, ApprovedBy: m.ApprovedBy})
+		if cErr != nil {
+			return errorResponse(stub, cErr)
+		}
+		r := response{CODEALLAOK, m.MilestoneID, nil}
+		return shim.Success((r.formatResponse(stub)))
+	}
+
+	cErr = &chainError{"approveMilestone", pm.Data.MilestoneID, CODENOTFOUND, errors.New("Milestone not found under the project")}
+	return errorResponse(stub, cErr)
+}
+
+// load - read milestone from the project into the receiver
+func (pm *projectMilestone) load(stub shim.ChaincodeStubInterface, fcn string) *chainError {
+
+	p := &project{ProjectID: pm.ProjectID}
+	cErr := p.load(stub, fcn)
+	if cErr != nil {
+		return cErr
+	}
+	for _, m := range p.Data.Milestones {
+		if m.MilestoneID == pm.Data.MilestoneID {
+			pm.Data = m
+			return nil
+		}
+	}
+	return &chainError{fcn, pm.Data.MilestoneID, CODENOTFOUND, errors.New("Milestone not found under the project")}
+}
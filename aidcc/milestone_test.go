@@ -0,0 +1,172 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/stretchr/testify/assert"
+)
+
+// Verifies scenarios related to project milestones and milestone based spending
+func TestMilestoneRW(t *testing.T) {
+	fmt.Println("Executing Test - MilestoneRW")
+	defer os.Unsetenv("ROLE")
+	defer os.Unsetenv("CALLER")
+
+	// Test data - Refer to test narratives for test description
+	var milestoneTable = []struct {
+		projectID      string
+		milestoneID    string
+		narrative      string
+		budget         string
+		caller         string // project owner if empty
+		role           string
+		expectedStatus int32
+		testNarrative  string
+	}{
+		{"P101", "M1", "Procurement", "100", "", "", 200, "Happy scenario"},
+		{"P101", "M2", "Distribution", "200", "", "", 200, "Happy scenario"},
+		{"", "M3", "Distribution", "200", "", "", 500, "Check for missing project ID"},
+		{"P101", "", "Distribution", "200", "", "", 500, "Check for missing milestone ID"},
+		{"P101", "M3", "", "200", "", "", 500, "Check for missing narrative"},
+		{"P101", "M3", "Distribution", "0", "", "", 500, "Check for positive budget"},
+		{"P102", "M3", "Distribution", "200", "", "", 500, "Check for project ID existence"},
+		{"P101", "M1", "Procurement", "100", "", "", 500, "Check for duplicate milestone ID"},
+		{"P101", "M3", "Distribution", "200", "intruder", "donor", 500, "Check for caller not running the project"},
+		{"P101", "M4", "Monitoring", "50", "intruder", ROLEADMIN, 200, "Happy scenario - admin adds milestone of any project"},
+	}
+	// Spends & approvals are executed in order
+	var spendTable = []struct {
+		approve        string
+		role           string
+		approveStatus  int32
+		txnID          string
+		amount         string
+		expectedStatus int32
+		testNarrative  string
+	}{
+		{"", "", 0, "S101", "50", 500, "Check for spend without approved milestone"},
+		{"M1", "donor", 500, "S102", "50", 500, "Check for approval by unauthorized role"},
+		{"M1", ROLEVERIFIER, 200, "S103", "80", 200, "Spend within approved milestone budget"},
+		{"M1", ROLEVERIFIER, 500, "S104", "30", 500, "Check for duplicate approval & cumulative spend over approved budget"},
+		{"M2", ROLEVERIFIER, 200, "S105", "30", 200, "Spend within budget after approval of next milestone"},
+		{"M3", ROLEVERIFIER, 500, "S106", "500", 500, "Check for milestone existence & spend over approved budget"},
+	}
+
+	// struct for parsing the shim APIs response
+	type mResp struct {
+		Code    string           `json:"code"`
+		Message string           `json:"message"`
+		Payload projectMilestone `json:"payload"`
+	}
+	assert := assert.New(t)
+
+	// Instantiate mockStub using AidChaincode as the target chaincode to unit test
+	stub := shim.NewMockStub("TestStub", new(AidChaincode))
+	assert.NotNil(stub, "Stub is nil, Test stub creation failed")
+
+	uid := uuid.New().String()
+
+	// Add test project, item & donation to state.
+	result := stub.MockInvoke(uid,
+		[][]byte{[]byte(AddProject),
+			[]byte("P101"),
+			[]byte("Prj101")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Saving test project to state failed.")
+
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(AddItem),
+			[]byte("Itm001"),
+			[]byte("Item001"),
+			[]byte("Medicine")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Saving test item to state failed.")
+
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(AddDonation),
+			[]byte("D101"),
+			[]byte("vishal"),
+			[]byte("P101"),
+			[]byte("Itm001"),
+			[]byte("500")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Saving test donation entry to state failed.")
+
+	// Executing milestone tests
+	for _, test := range milestoneTable {
+		os.Setenv("CALLER", test.caller)
+		os.Setenv("ROLE", test.role)
+		result := stub.MockInvoke(uid,
+			[][]byte{[]byte(AddMilestone),
+				[]byte(test.projectID),
+				[]byte(test.milestoneID),
+				[]byte(test.narrative),
+				[]byte(test.budget)})
+
+		assert.Equal(test.expectedStatus, result.GetStatus(), test.testNarrative+" failed - "+(result.GetMessage()))
+
+		if result.GetStatus() == shim.OK {
+			// verify milestone read
+			result = stub.MockInvoke(uid,
+				[][]byte{[]byte(GetMilestone),
+					[]byte(test.projectID),
+					[]byte(test.milestoneID)})
+			assert.EqualValues(shim.OK, result.GetStatus(), GetMilestone+" failed to read the milestone data")
+
+			r := &mResp{}
+			err := json.Unmarshal(result.GetPayload(), r)
+			if err != nil {
+				panic(err)
+			}
+			assert.Equal(test.milestoneID, r.Payload.Data.MilestoneID, "Reterived milestone ID mismatch")
+			assert.False(r.Payload.Data.Approved, "New milestone must not be approved")
+		}
+	}
+	os.Unsetenv("CALLER")
+	os.Unsetenv("ROLE")
+
+	// Refresh Available funds under Project
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(GetProject),
+			[]byte("P101")})
+	assert.EqualValues(shim.OK, result.GetStatus(), GetProject+" failed to read the project data")
+
+	// Executing spend tests
+	for _, test := range spendTable {
+		if len(test.approve) != 0 {
+			os.Setenv("ROLE", test.role)
+			result := stub.MockInvoke(uid,
+				[][]byte{[]byte(ApproveMilestone),
+					[]byte("P101"),
+					[]byte(test.approve)})
+			assert.Equal(test.approveStatus, result.GetStatus(), test.testNarrative+" failed - "+(result.GetMessage()))
+		}
+
+		result := stub.MockInvoke(uid,
+			[][]byte{[]byte(AddSpend),
+				[]byte(test.txnID),
+				[]byte("vishal"),
+				[]byte("P101"),
+				[]byte("Itm001"),
+				[]byte(test.amount)})
+
+		assert.Equal(test.expectedStatus, result.GetStatus(), test.testNarrative+" failed - "+(result.GetMessage()))
+	}
+
+	// Verify approval is recorded against the milestone
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(GetMilestone),
+			[]byte("P101"),
+			[]byte("M1")})
+	assert.EqualValues(shim.OK, result.GetStatus(), GetMilestone+" failed to read the milestone data")
+
+	r := &mResp{}
+	err := json.Unmarshal(result.GetPayload(), r)
+	if err != nil {
+		panic(err)
+	}
+	assert.True(r.Payload.Data.Approved, "Milestone approval not recorded")
+	assert.Equal("Test Caller", r.Payload.Data.ApprovedBy, "Milestone approver not recorded")
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"errors"
+	"time"
+
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/shopspring/decimal"
+	"github.com/vishal3152/AIDChaincode/aidcc/events"
+)
+
+// Asset model for pledge. All asset models are kept in
+// private scope i.e. they are not exported and ramin invisible to other packages
+// A pledge records an amount committed by a donor but not yet paid. Donations referencing
+// the pledge fulfil it, fully or partially.
+type pledgeBase struct {
+	ProjectID    string          `json:"projectID"`
+	ItemID       string          `json:"itemID"`
+	Amount       decimal.Decimal `json:"amount"`       // pledged amount
+	Currency     string          `json:"currency"`     // ISO 4217 code, fulfilling donations must be in the same currency
+	FulfilledAmt decimal.Decimal `json:"fulfilledAmt"` // sum of donations made against the pledge
+	Fulfilments  []string        `json:"fulfilments"`  // txnIDs of donations made against the pledge
+	Status       string          `json:"status"`       // OPEN, FULFILLED or CANCELLED
+	TimeStamp    time.Time       `json:"timeStamp"`
+}
+
+type pledge struct {
+	ObjectType string     `json:"docType"` // pledge Type 'PLEDGE'
+	versioned             // schema version of the document
+	PledgeID   string     `json:"pledgeID"` // asset unique key
+	Donor      string     `json:"donor"`
+	Data       pledgeBase `json:"data"` // composition
+}
+
+// key - ledger key of the pledge
+func (pl *pledge) key() string {
+	return pl.PledgeID
+}
+
+// docType - doc type of pledge assets
+func (pl *pledge) docType() string {
+	return PLEDGE
+}
+
+// validate - check pledge invariants before it is written
+func (pl *pledge) validate() *chainError {
+
+	cErr := checkIdentity(pl, pl.ObjectType)
+	if cErr != nil {
+		return cErr
+	}
+	if pl.Data.Amount.Sign() <= 0 {
+		return &chainError{"validate", pl.key(), CODEUNPROCESSABLEENTITY, errors.New("Amount must be positive")}
+	}
+	return nil
+}
+
+// outstanding - pledged amount not yet fulfilled
+func (pl *pledge) outstanding() decimal.Decimal {
+	return pl.Data.Amount.Sub(pl.Data.FulfilledAmt)
+}
+
+// Write pledge to ledger
+func (pl *pledge) putState(stub shim.ChaincodeStubInterface) pb.Response {
+
+	// check if affiliated project exists
+	c, cErr := checkAsset(stub, pl.Data.ProjectID)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	} else if !c {
+		e := &chainError{"putPledge", pl.PledgeID, CODENOTFOUND, errors.New("Affiliated project not found")}
+		return errorResponse(stub, e)
+	}
+
+	// resolve pledge currency, defaults to project's base currency
+	pl.Data.Currency, pl.Data.Amount, cErr = resolveCurrency(stub, "putPledge", pl.PledgeID, pl.Data.ProjectID, pl.Data.Currency, pl.Data.Amount)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	// check if affiliated item exists
+	c, cErr = checkAsset(stub, pl.Data.ItemID)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	} else if !c {
+		e := &chainError{"putPledge", pl.PledgeID, CODENOTFOUND, errors.New("Affiliated item not found")}
+		return errorResponse(stub, e)
+	}
+
+	// check if pledgeID is unique
+	c, cErr = checkAsset(stub, pl.PledgeID)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	} else if c {
+		e := &chainError{"putPledge", pl.PledgeID, CODEAlRDEXIST, errors.New("Asset with key already exists")}
+		return errorResponse(stub, e)
+	}
+
+	// Write pledge to ledger
+	cErr = newRepository(stub, "putPledge").save(pl)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	// Add indexkey for range query :- pledged amount is stored in form of a delta and aggregated
+	// under project's pledged fund whenever project state is read
+	cErr = putDelta(stub, "putPledge", pl.Data.ProjectID, "3", pl.Data.Currency, pl.PledgeID, pl.Data.Amount)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	// Emit transaction event for listeners
+	cErr = emitEvent(stub, "putPledge", pl.PledgeID, events.PledgeCreated, &events.Pledge{PledgeID: pl.PledgeID, ProjectID: pl.Data.ProjectID, ItemID: pl.Data.ItemID, Donor: pl.Donor, Amount: pl.Data.Amount, Currency: pl.Data.Currency})
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	r := response{CODEALLAOK, pl.PledgeID, nil}
+	return shim.Success((r.formatResponse(stub)))
+}
+
+// Read pledge state from the ledger
+func (pl *pledge) getState(stub shim.ChaincodeStubInterface) pb.Response {
+
+	plg, cErr := queryAsset(stub, pl.PledgeID)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	r := response{CODEALLAOK, "OK", plg}
+	return shim.Success((r.formatResponse(stub)))
+}
+
+// Cancel an open pledge. Outstanding amount is released from project's pledged fund,
+// donations already made against the pledge remain untouched
+func (pl *pledge) cancelState(stub shim.ChaincodeStubInterface) pb.Response {
+
+	cErr := pl.load(stub, "cancelPledge")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	if pl.Data.Status != PLGOPEN {
+		cErr = &chainError{"cancelPledge", pl.PledgeID, CODENOTALLWD, errors.New("Pledge is " + pl.Data.Status)}
+		return errorResponse(stub, cErr)
+	}
+
+	remainder := pl.outstanding()
+	pl.Data.Status = PLGCNCL
+	cErr = pl.store(stub, "cancelPledge")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	// Release the outstanding amount from project's pledged fund
+	cErr = putDelta(stub, "cancelPledge", pl.Data.ProjectID, "4", pl.Data.Currency, pl.PledgeID, remainder)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	// Emit transaction event for listeners
+	cErr = emitEvent(stub, "cancelPledge", pl.PledgeID, events.PledgeCancelled, &events.Pledge{PledgeID: pl.PledgeID, ProjectID: pl.Data.ProjectID, ItemID: pl.Data.ItemID, Donor: pl.Donor, Amount: remainder, Currency: pl.Data.Currency})
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	r := response{CODEALLAOK, pl.PledgeID, nil}
+	return shim.Success((r.formatResponse(stub)))
+}
+
+// fulfil - record a donation against an open pledge. Donation must match the pledge's donor, project
+// and item and can not exceed the outstanding amount. Partial fulfilment leaves the pledge open.
+func (pl *pledge) fulfil(stub shim.ChaincodeStubInterface, d *donation) *chainError {
+
+	cErr := pl.load(stub, "fulfilPledge")
+	if cErr != nil {
+		return cErr
+	}
+	if pl.Data.Status != PLGOPEN {
+		return &chainError{"fulfilPledge", pl.PledgeID, CODENOTALLWD, errors.New("Pledge is " + pl.Data.Status)}
+	}
+	if pl.Donor != d.Donor || pl.Data.ProjectID != d.Data.ProjectID || pl.Data.ItemID != d.Data.ItemID {
+		return &chainError{"fulfilPledge", pl.PledgeID, CODENOTALLWD, errors.New("Donation does not match pledge donor, project or item")}
+	}
+	if pl.Data.Currency != d.Data.Currency {
+		return &chainError{"fulfilPledge", pl.PledgeID, CODENOTALLWD, errors.New("Donation currency does not match pledge currency " + pl.Data.Currency)}
+	}
+	if d.Data.Amount.GreaterThan(pl.outstanding()) {
+		return &chainError{"fulfilPledge", pl.PledgeID, CODENOTALLWD, errors.New("Donation amount exceeds outstanding pledge amount")}
+	}
+
+	pl.Data.FulfilledAmt = pl.Data.FulfilledAmt.Add(d.Data.Amount)
+	pl.Data.Fulfilments = append(pl.Data.Fulfilments, d.TxnID)
+	if pl.outstanding().IsZero() {
+		pl.Data.Status = PLGFULL
+	}
+	cErr = pl.store(stub, "fulfilPledge")
+	if cErr != nil {
+		return cErr
+	}
+
+	// Release the fulfilled amount from project's pledged fund
+	return putDelta(stub, "fulfilPledge", pl.Data.ProjectID, "4", pl.Data.Currency, d.TxnID, d.Data.Amount)
+}
+
+// load - read pledge from the ledger into the receiver
+func (pl *pledge) load(stub shim.ChaincodeStubInterface, fcn string) *chainError {
+	return newRepository(stub, fcn).read(pl)
+}
+
+// store - write pledge state back to the ledger
+func (pl *pledge) store(stub shim.ChaincodeStubInterface, fcn string) *chainError {
+	return newRepository(stub, fcn).save(pl)
+}
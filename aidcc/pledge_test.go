@@ -0,0 +1,237 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// struct for parsing the shim APIs response
+type plgResp struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Payload pledge `json:"payload"`
+}
+
+// Verifies scenarios related to pledge
+func TestPledgeRW(t *testing.T) {
+	fmt.Println("Executing Test - PledgeRW")
+
+	// Test data - Refer to test narratives for test description
+	var testTable = []struct {
+		pledgeID       string
+		donorName      string
+		projectID      string
+		itemID         string
+		amount         string
+		expectedStatus int32
+		testNarrative  string
+	}{
+		{"PL101", "vishal", "P101", "Itm001", "100", 200, "Happy scenario"},
+		{"PL102", "vishal", "P101", "Itm001", "", 500, "Check for postive amount"},
+		{"PL102", "vishal", "P101", "Itm001", "0", 500, "Check for positive amount"},
+		{"", "vishal", "P101", "Itm001", "100", 500, "Check for missing pledge ID"},
+		{"PL102", "", "P101", "Itm001", "100", 500, "Check for missing donor"},
+		{"PL102", "vishal", "", "Itm001", "100", 500, "Check for missing projectID"},
+		{"PL102", "vishal", "P102", "Itm001", "100", 500, "Check for project ID existence"},
+		{"PL102", "vishal", "P101", "", "100", 500, "Check for missing item ID"},
+		{"PL102", "vishal", "P101", "Itm002", "100", 500, "Check for item ID existence"},
+		{"PL101", "vishal", "P101", "Itm001", "100", 500, "Check for duplicate pledge ID"},
+	}
+	type pResp struct {
+		Code    string  `json:"code"`
+		Message string  `json:"message"`
+		Payload project `json:"payload"`
+	}
+	assert := assert.New(t)
+
+	// Instantiate mockStub using AidChaincode as the target chaincode to unit test
+	stub := shim.NewMockStub("TestStub", new(AidChaincode))
+	assert.NotNil(stub, "Stub is nil, Test stub creation failed")
+
+	uid := uuid.New().String()
+
+	// Add test project ID i.e. P101 and item ID i.e. Itm001 to state.
+	// Pledges must be tagged to a project and an item
+	result := stub.MockInvoke(uid,
+		[][]byte{[]byte(AddProject),
+			[]byte("P101"),
+			[]byte("Prj101")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Saving test project to state failed.")
+
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(AddItem),
+			[]byte("Itm001"),
+			[]byte("Item001"),
+			[]byte("Medicine")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Saving test item to state failed.")
+
+	// Executing tests
+	for _, test := range testTable {
+		result := stub.MockInvoke(uid,
+			[][]byte{[]byte(AddPledge),
+				[]byte(test.pledgeID),
+				[]byte(test.donorName),
+				[]byte(test.projectID),
+				[]byte(test.itemID),
+				[]byte(test.amount)})
+
+		assert.Equal(test.expectedStatus, result.GetStatus(), test.testNarrative+" failed - "+(result.GetMessage()))
+
+		if result.GetStatus() == shim.OK {
+			// verify pledge read
+			result = stub.MockInvoke(uid,
+				[][]byte{[]byte(GetPledge),
+					[]byte(test.pledgeID)})
+
+			assert.EqualValues(shim.OK, result.GetStatus(), GetPledge+" failed to read the pledge data")
+
+			r := &plgResp{}
+			err := json.Unmarshal(result.GetPayload(), r)
+			if err != nil {
+				panic(err)
+			}
+			assert.Equal(test.pledgeID, r.Payload.PledgeID, "Reterived pledge ID mismatch")
+			assert.Equal(PLGOPEN, r.Payload.Data.Status, "New pledge must be open")
+
+			// Verify pledged amount reflect under project's pledged fund and not under available fund
+			result = stub.MockInvoke(uid,
+				[][]byte{[]byte(GetProject),
+					[]byte(test.projectID)})
+
+			assert.EqualValues(shim.OK, result.GetStatus(), GetProject+" failed to read the project data")
+
+			p := &pResp{}
+			err = json.Unmarshal(result.GetPayload(), p)
+			if err != nil {
+				panic(err)
+			}
+			exceptedVal, _ := decimal.NewFromString(test.amount)
+			assert.True(exceptedVal.Equal(p.Payload.Data.PledgedFund), "Pledge amount fails to reflect under project pledged fund")
+			assert.True(p.Payload.Data.AvlFund.IsZero(), "Pledge amount must not reflect under project available fund")
+		}
+	}
+}
+
+// Verifies pledge fulfilment by donations and pledge cancellation
+func TestPledgeFulfilment(t *testing.T) {
+	fmt.Println("Executing Test - PledgeFulfilment")
+
+	// Test data - Refer to test narratives for test description
+	// Donations are executed in order against pledge PL101 of 100
+	var testTable = []struct {
+		txnID           string
+		donorName       string
+		amount          string
+		expectedStatus  int32
+		expectedPledged string
+		expectedPlgStat string
+		testNarrative   string
+	}{
+		{"D101", "vishal", "40", 200, "60", PLGOPEN, "Partial fulfilment"},
+		{"D102", "vishal", "70", 500, "60", PLGOPEN, "Check for donation exceeding outstanding amount"},
+		{"D103", "someone", "10", 500, "60", PLGOPEN, "Check for donor mismatch"},
+		{"D104", "vishal", "60", 200, "0", PLGFULL, "Full fulfilment"},
+		{"D105", "vishal", "10", 500, "0", PLGFULL, "Check for donation against a fulfilled pledge"},
+	}
+	type pResp struct {
+		Code    string  `json:"code"`
+		Message string  `json:"message"`
+		Payload project `json:"payload"`
+	}
+	assert := assert.New(t)
+
+	// Instantiate mockStub using AidChaincode as the target chaincode to unit test
+	stub := shim.NewMockStub("TestStub", new(AidChaincode))
+	assert.NotNil(stub, "Stub is nil, Test stub creation failed")
+
+	uid := uuid.New().String()
+
+	// Add test project, item & pledges to state
+	result := stub.MockInvoke(uid,
+		[][]byte{[]byte(AddProject),
+			[]byte("P101"),
+			[]byte("Prj101")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Saving test project to state failed.")
+
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(AddItem),
+			[]byte("Itm001"),
+			[]byte("Item001"),
+			[]byte("Medicine")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Saving test item to state failed.")
+
+	for _, plg := range []string{"PL101", "PL102"} {
+		result = stub.MockInvoke(uid,
+			[][]byte{[]byte(AddPledge),
+				[]byte(plg),
+				[]byte("vishal"),
+				[]byte("P101"),
+				[]byte("Itm001"),
+				[]byte("100")})
+		assert.EqualValues(shim.OK, result.GetStatus(), "Saving test pledge to state failed.")
+	}
+
+	// Cancel PL102, only PL101 remains pledged
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(CancelPledge),
+			[]byte("PL102")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Pledge cancellation failed - "+result.GetMessage())
+
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(CancelPledge),
+			[]byte("PL102")})
+	assert.EqualValues(shim.ERROR, result.GetStatus(), "Check for duplicate pledge cancellation failed.")
+
+	// Executing tests
+	for _, test := range testTable {
+		result := stub.MockInvoke(uid,
+			[][]byte{[]byte(AddDonation),
+				[]byte(test.txnID),
+				[]byte(test.donorName),
+				[]byte("P101"),
+				[]byte("Itm001"),
+				[]byte(test.amount),
+				[]byte("PL101")})
+
+		assert.Equal(test.expectedStatus, result.GetStatus(), test.testNarrative+" failed - "+(result.GetMessage()))
+
+		// Verify pledge status
+		result = stub.MockInvoke(uid,
+			[][]byte{[]byte(GetPledge),
+				[]byte("PL101")})
+		assert.EqualValues(shim.OK, result.GetStatus(), GetPledge+" failed to read the pledge data")
+
+		r := &plgResp{}
+		err := json.Unmarshal(result.GetPayload(), r)
+		if err != nil {
+			panic(err)
+		}
+		assert.Equal(test.expectedPlgStat, r.Payload.Data.Status, test.testNarrative+" failed - pledge status mismatch")
+
+		// Verify outstanding pledged amount under project
+		result = stub.MockInvoke(uid,
+			[][]byte{[]byte(GetProject),
+				[]byte("P101")})
+		assert.EqualValues(shim.OK, result.GetStatus(), GetProject+" failed to read the project data")
+
+		p := &pResp{}
+		err = json.Unmarshal(result.GetPayload(), p)
+		if err != nil {
+			panic(err)
+		}
+		exceptedVal, _ := decimal.NewFromString(test.expectedPledged)
+		assert.True(exceptedVal.Equal(p.Payload.Data.PledgedFund), test.testNarrative+" failed - project pledged fund mismatch")
+	}
+
+	// Fulfilled pledge can not be cancelled
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(CancelPledge),
+			[]byte("PL101")})
+	assert.EqualValues(shim.ERROR, result.GetStatus(), "Check for cancellation of fulfilled pledge failed.")
+}
@@ -8,54 +8,117 @@ import (
 	"github.com/hyperledger/fabric/core/chaincode/shim"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/shopspring/decimal"
+	"github.com/vishal3152/AIDChaincode/aidcc/events"
 )
 
 // Asset model for project. All asset models are kept in
 // private scope i.e. they are not exported and ramin invisible to other packages
 type project struct {
-	ObjectType string      `json:"docType"`   // Project Type 'GPRJCT'
+	ObjectType string      `json:"docType"` // Project Type 'GPRJCT'
+	versioned              // schema version of the document
 	ProjectID  string      `json:"projectID"` // asset unique key
 	Data       projectBase `json:"data"`      // composition
+	// funds converted to a reporting currency, never written to the ledger
+	Converted *convertedFunds `json:"converted,omitempty"`
 }
+
+// key - ledger key of the project
+func (p *project) key() string {
+	return p.ProjectID
+}
+
+// docType - doc type of project assets
+func (p *project) docType() string {
+	return GPRJCT
+}
+
+// validate - check project invariants before it is written
+func (p *project) validate() *chainError {
+	return checkIdentity(p, p.ObjectType)
+}
+
 type projectBase struct {
-	ProjectName string          `json:"projectName"`
-	RunBy       string          `json:"runBy"`
-	StartDt     time.Time       `json:"startDt"`
-	AvlFund     decimal.Decimal `json:"avlFund"`
-	SpentFund   decimal.Decimal `json:"spentFund"`
+	ProjectName  string                 `json:"projectName"`
+	RunBy        string                 `json:"runBy"`
+	StartDt      time.Time              `json:"startDt"`
+	BaseCurrency string                 `json:"baseCurrency"`          // ISO 4217 code, amounts without currency are in base currency
+	OverheadRate decimal.Decimal        `json:"overheadRate"`          // percentage of each donation taken for overhead
+	OverheadPrj  string                 `json:"overheadPrj,omitempty"` // project receiving the overhead
+	AvlFund      decimal.Decimal        `json:"avlFund"`               // base currency balance, refer Balances for all currencies
+	SpentFund    decimal.Decimal        `json:"spentFund"`
+	PledgedFund  decimal.Decimal        `json:"pledgedFund"`          // committed but not yet paid, excluded from AvlFund
+	ReservedFund decimal.Decimal        `json:"reservedFund"`         // held against issued vouchers, excluded from AvlFund
+	Balances     map[string]fundBalance `json:"balances"`             // balances per currency
+	Milestones   []milestone            `json:"milestones,omitempty"` // spends are capped at approved milestone budgets, if any
+	Budget       []budgetLine           `json:"budget,omitempty"`     // spends are capped per category of budget lines, if any
+	Reports      map[string]fundReport  `json:"reports,omitempty"`    // funds folded per reporting currency, refer convertFunds
+}
+
+// fundBalance - project funds held in a currency
+type fundBalance struct {
+	AvlFund      decimal.Decimal `json:"avlFund"`
+	SpentFund    decimal.Decimal `json:"spentFund"`
+	PledgedFund  decimal.Decimal `json:"pledgedFund"`
+	ReservedFund decimal.Decimal `json:"reservedFund"`
+}
+
+// apply - apply a fund movement of project history to the balance, bitmasks are those of putDelta
+func (bal *fundBalance) apply(bitmask string, amount decimal.Decimal) {
+	switch bitmask {
+	case "0":
+		bal.AvlFund = bal.AvlFund.Sub(amount)
+		bal.SpentFund = bal.SpentFund.Add(amount)
+	case "1":
+		bal.AvlFund = bal.AvlFund.Add(amount)
+	case "2":
+		bal.AvlFund = bal.AvlFund.Add(amount)
+		bal.SpentFund = bal.SpentFund.Sub(amount)
+	case "3":
+		bal.PledgedFund = bal.PledgedFund.Add(amount)
+	case "4":
+		bal.PledgedFund = bal.PledgedFund.Sub(amount)
+	case "5":
+		bal.AvlFund = bal.AvlFund.Sub(amount)
+		bal.ReservedFund = bal.ReservedFund.Add(amount)
+	case "6":
+		bal.AvlFund = bal.AvlFund.Add(amount)
+		bal.ReservedFund = bal.ReservedFund.Sub(amount)
+	}
 }
 
 // Write asset state to ledger
 func (p *project) putState(stub shim.ChaincodeStubInterface) pb.Response {
 
-	// check if projectID already exists
-	c, cErr := checkAsset(stub, p.ProjectID)
-	if cErr != nil {
-		return shim.Error(cErr.Error())
-	}
-	if c {
-		e := &chainError{"putProject", p.ProjectID, CODEAlRDEXIST, errors.New("Asset with key already exists")}
-		return shim.Error(e.Error())
+	// check if base currency is supported
+	_, ok := precisionOf(p.Data.BaseCurrency)
+	if !ok {
+		e := &chainError{"putProject", p.ProjectID, CODEUNPROCESSABLEENTITY, errors.New("Unsupported currency " + p.Data.BaseCurrency)}
+		return errorResponse(stub, e)
 	}
 
-	// Marshal the project struct ti []byte
-	b, err := json.Marshal(p)
-	if err != nil {
-		cErr = &chainError{"putProject", p.ProjectID, CODEGENEXCEPTION, err}
-		return shim.Error(cErr.Error())
+	// check if overhead project exists
+	if len(p.Data.OverheadPrj) != 0 {
+		ovh := &project{ProjectID: p.Data.OverheadPrj}
+		cErr := ovh.load(stub, "putProject")
+		if cErr != nil {
+			e := &chainError{"putProject", p.ProjectID, CODENOTFOUND, errors.New("Overhead project not found")}
+			return errorResponse(stub, e)
+		}
 	}
-	// Write key-value to ledger
-	err = stub.PutState(p.ProjectID, b)
-	if err != nil {
-		cErr = &chainError{"putProject", p.ProjectID, CODEGENEXCEPTION, err}
-		return shim.Error(cErr.Error())
+
+	// Write project to ledger, projectID must be unique
+	cErr := newRepository(stub, "putProject").create(p)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
 	}
 
 	// Emit transaction event for listeners
-	txID := stub.GetTxID()
-	stub.SetEvent((p.ProjectID + "_AID_PRJADD_" + txID), nil)
+	cErr = emitEvent(stub, "putProject", p.ProjectID, events.ProjectCreated, &events.Project{ProjectID: p.ProjectID, ProjectName: p.Data.ProjectName, RunBy: p.Data.RunBy, BaseCurrency: p.Data.BaseCurrency, OverheadRate: p.Data.OverheadRate, OverheadPrj: p.Data.OverheadPrj})
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
 	r := response{CODEALLAOK, p.ProjectID, nil}
-	return shim.Success((r.formatResponse()))
+	return shim.Success((r.formatResponse(stub)))
 
 }
 
@@ -69,106 +132,274 @@ func (p *project) getState(stub shim.ChaincodeStubInterface) pb.Response {
 	// This operation should be invoked by client application at regular interval to prevent overspending of
 	// available funds under teh project.
 
-	donationAggregate, _ := decimal.NewFromString("0")
-	spendAggregate, _ := decimal.NewFromString("0")
 	//Do a range query on donations (incoming, bitmask "1")
-	indexName := string(INDXNM)
-	donItr, err := stub.GetStateByPartialCompositeKey(indexName, []string{"1"})
+	donationAggregate, cErr := aggregateDeltas(stub, p.ProjectID, "1", true)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	//Aggreate spends
+	spendAggregate, cErr := aggregateDeltas(stub, p.ProjectID, "0", true)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	//Aggreate spend reversals, reversed amount flows back from spent to available fund
+	reversalAggregate, cErr := aggregateDeltas(stub, p.ProjectID, "2", true)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	//Aggreate pledges & pledge releases (fulfilment or cancellation)
+	pledgeAggregate, cErr := aggregateDeltas(stub, p.ProjectID, "3", true)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	releaseAggregate, cErr := aggregateDeltas(stub, p.ProjectID, "4", true)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	//Aggreate voucher reservations & their releases (redemption or expiry)
+	reserveAggregate, cErr := aggregateDeltas(stub, p.ProjectID, "5", true)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	unreserveAggregate, cErr := aggregateDeltas(stub, p.ProjectID, "6", true)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	// Get current value of available fund under the project
+	prj := &project{ProjectID: p.ProjectID}
+	cErr = prj.load(stub, "readProject")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	if prj.Data.Balances == nil {
+		prj.Data.Balances = make(map[string]fundBalance)
+	}
+	// calculate the new value, deltas are aggregated per currency
+	currencies := make(map[string]bool)
+	for _, aggregate := range []map[string]decimal.Decimal{donationAggregate, spendAggregate, reversalAggregate, pledgeAggregate, releaseAggregate, reserveAggregate, unreserveAggregate} {
+		for currency := range aggregate {
+			currencies[currency] = true
+		}
+	}
+	for currency := range currencies {
+		bal := prj.Data.Balances[currency]
+		bal.AvlFund = bal.AvlFund.Add(donationAggregate[currency].Sub(spendAggregate[currency])).Add(reversalAggregate[currency])
+		bal.SpentFund = bal.SpentFund.Add(spendAggregate[currency]).Sub(reversalAggregate[currency])
+		bal.PledgedFund = bal.PledgedFund.Add(pledgeAggregate[currency]).Sub(releaseAggregate[currency])
+		bal.AvlFund = bal.AvlFund.Sub(reserveAggregate[currency]).Add(unreserveAggregate[currency])
+		bal.ReservedFund = bal.ReservedFund.Add(reserveAggregate[currency]).Sub(unreserveAggregate[currency])
+		prj.Data.Balances[currency] = bal
+	}
+	// Base currency balance is reported at top level as well
+	base := prj.Data.Balances[prj.baseCurrency()]
+	prj.Data.AvlFund = base.AvlFund
+	prj.Data.SpentFund = base.SpentFund
+	prj.Data.PledgedFund = base.PledgedFund
+	prj.Data.ReservedFund = base.ReservedFund
+
+	// Write the new value of available funds on ledger
+	cErr = prj.store(stub, "readProject")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	// Read and return updated project data
+	prjBytes, err := stub.GetState(p.ProjectID)
 	if err != nil {
 		cErr := &chainError{"readProject", p.ProjectID, CODEGENEXCEPTION, err}
-		return shim.Error(cErr.Error())
+		return errorResponse(stub, cErr)
+	}
+	r := response{CODEALLAOK, p.ProjectID, prjBytes}
+	return shim.Success((r.formatResponse(stub)))
+}
+
+// convertState - read project with its funds converted to the reporting currency, along with the
+// FX rates applied
+func (p *project) convertState(stub shim.ChaincodeStubInterface, currency string) pb.Response {
+
+	// Aggregate pending deltas under the project first
+	resp := p.getState(stub)
+	if resp.GetStatus() != shim.OK {
+		return resp
+	}
+	cErr := p.load(stub, "convertProject")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	converted, cErr := convertFunds(stub, p, currency)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	// Keep the report folded so far, so that the next read converts only months not yet folded
+	cErr = p.store(stub, "convertProject")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	p.Converted = converted
+	prjBytes, err := json.Marshal(p)
+	if err != nil {
+		cErr = &chainError{"convertProject", p.ProjectID, CODEGENEXCEPTION, err}
+		return errorResponse(stub, cErr)
+	}
+	r := response{CODEALLAOK, p.ProjectID, prjBytes}
+	return shim.Success((r.formatResponse(stub)))
+}
+
+// aggregateDeltas - sum all deltas stored under the range index for a project & bitmask, per currency.
+// Deltas are deleted once aggregated if consume is set, otherwise only pending amount is reported.
+func aggregateDeltas(stub shim.ChaincodeStubInterface, projectID string, bitmask string, consume bool) (map[string]decimal.Decimal, *chainError) {
+
+	aggregate := make(map[string]decimal.Decimal)
+	deltaItr, err := stub.GetStateByPartialCompositeKey(INDXNM, []string{projectID, bitmask})
+	if err != nil {
+		return aggregate, &chainError{"readProject", projectID, CODEGENEXCEPTION, err}
 	}
 	//Close itrerator when done reading
-	defer donItr.Close()
-	for donItr.HasNext() {
-		rangeItem, err := donItr.Next()
+	defer deltaItr.Close()
+	for deltaItr.HasNext() {
+		rangeItem, err := deltaItr.Next()
 		if err != nil {
-			cErr := &chainError{"readProject", p.ProjectID, CODEGENEXCEPTION, err}
-			return shim.Error(cErr.Error())
+			return aggregate, &chainError{"readProject", projectID, CODEGENEXCEPTION, err}
 		}
 		_, compositeKeyParts, err := stub.SplitCompositeKey(rangeItem.Key)
 		if err != nil {
-			cErr := &chainError{"readProject", p.ProjectID, CODEGENEXCEPTION, err}
-			return shim.Error(cErr.Error())
+			return aggregate, &chainError{"readProject", projectID, CODEGENEXCEPTION, err}
 		}
-		// compositeKeyParts[2] represents transaction amount
-		txAmount, _ := decimal.NewFromString(compositeKeyParts[2])
-		donationAggregate = donationAggregate.Add(txAmount)
+		// compositeKeyParts[2] represents currency & compositeKeyParts[4] transaction amount
+		txAmount, _ := decimal.NewFromString(compositeKeyParts[4])
+		aggregate[compositeKeyParts[2]] = aggregate[compositeKeyParts[2]].Add(txAmount)
 
+		if !consume {
+			continue
+		}
 		// Delete the key from index after its delta has been aggregated
 		err = stub.DelState(rangeItem.Key)
 		if err != nil {
-			cErr := &chainError{"readProject", p.ProjectID, CODEGENEXCEPTION, err}
-			return shim.Error(cErr.Error())
+			return aggregate, &chainError{"readProject", projectID, CODEGENEXCEPTION, err}
 		}
 	}
 
-	//Aggreate spends
-	spendItr, err := stub.GetStateByPartialCompositeKey(indexName, []string{"0"})
+	// Donations & spends may still be pending under the legacy index
+	if bitmask != "0" && bitmask != "1" {
+		return aggregate, nil
+	}
+	legacyAggregate, cErr := aggregateLegacyDeltas(stub, projectID, bitmask, consume)
+	if cErr != nil {
+		return aggregate, cErr
+	}
+	if !legacyAggregate.IsZero() {
+		aggregate[DEFCCY] = aggregate[DEFCCY].Add(legacyAggregate)
+	}
+	return aggregate, nil
+}
+
+// aggregateLegacyDeltas - sum deltas of a project & bitmask stored under the legacy index LGCYIDX. Legacy
+// deltas carry no project, the project is read from the donation or spend of the delta. Deltas of other
+// projects are left for those projects to aggregate.
+func aggregateLegacyDeltas(stub shim.ChaincodeStubInterface, projectID string, bitmask string, consume bool) (decimal.Decimal, *chainError) {
+
+	aggregate := decimal.Zero
+	deltaItr, err := stub.GetStateByPartialCompositeKey(LGCYIDX, []string{bitmask})
 	if err != nil {
-		cErr := &chainError{"readProject", p.ProjectID, CODEGENEXCEPTION, err}
-		return shim.Error(cErr.Error())
+		return aggregate, &chainError{"readProject", projectID, CODEGENEXCEPTION, err}
 	}
 	//Close itrerator when done reading
-	defer spendItr.Close()
-	for spendItr.HasNext() {
-		rangeItem, err := spendItr.Next()
+	defer deltaItr.Close()
+	for deltaItr.HasNext() {
+		rangeItem, err := deltaItr.Next()
 		if err != nil {
-			cErr := &chainError{"readProject", p.ProjectID, CODEGENEXCEPTION, err}
-			return shim.Error(cErr.Error())
+			return aggregate, &chainError{"readProject", projectID, CODEGENEXCEPTION, err}
 		}
 		_, compositeKeyParts, err := stub.SplitCompositeKey(rangeItem.Key)
 		if err != nil {
-			cErr := &chainError{"readProject", p.ProjectID, CODEGENEXCEPTION, err}
-			return shim.Error(cErr.Error())
+			return aggregate, &chainError{"readProject", projectID, CODEGENEXCEPTION, err}
+		}
+		// compositeKeyParts[1] represents transaction ID & compositeKeyParts[2] transaction amount
+		txnBytes, cErr := queryAsset(stub, compositeKeyParts[1])
+		if cErr != nil {
+			return aggregate, cErr
+		}
+		txn := struct {
+			Data struct {
+				ProjectID string `json:"projectID"`
+			} `json:"data"`
+		}{}
+		err = json.Unmarshal(txnBytes, &txn)
+		if err != nil {
+			return aggregate, &chainError{"readProject", compositeKeyParts[1], CODEGENEXCEPTION, err}
+		}
+		if txn.Data.ProjectID != projectID {
+			continue
 		}
-		// Delete the key from index after its delta has been aggregated
 		txAmount, _ := decimal.NewFromString(compositeKeyParts[2])
-		spendAggregate = spendAggregate.Add(txAmount)
+		aggregate = aggregate.Add(txAmount)
 
+		if !consume {
+			continue
+		}
 		// Delete the key from index after its delta has been aggregated
 		err = stub.DelState(rangeItem.Key)
 		if err != nil {
-			cErr := &chainError{"readProject", p.ProjectID, CODEGENEXCEPTION, err}
-			return shim.Error(cErr.Error())
+			return aggregate, &chainError{"readProject", projectID, CODEGENEXCEPTION, err}
 		}
 	}
+	return aggregate, nil
+}
 
-	// Get current value of available fund under the project
-	prjBytes, err := stub.GetState(p.ProjectID)
-	if err != nil {
-		cErr := &chainError{"readProject", p.ProjectID, CODEGENEXCEPTION, err}
-		return shim.Error(cErr.Error())
+// baseCurrency - project's base currency, projects created before multi-currency support are in DEFCCY
+func (p *project) baseCurrency() string {
+	if len(p.Data.BaseCurrency) == 0 {
+		return DEFCCY
 	}
-	prj := &project{}
-	err = json.Unmarshal(prjBytes, prj)
-	if err != nil {
-		cErr := &chainError{"readProject", p.ProjectID, CODEGENEXCEPTION, err}
-		return shim.Error(cErr.Error())
-	}
-	// calculate the new value
-	prj.Data.AvlFund = prj.Data.AvlFund.Add(donationAggregate.Sub(spendAggregate))
-	prj.Data.SpentFund = prj.Data.SpentFund.Add(spendAggregate)
+	return p.Data.BaseCurrency
+}
 
-	prjBytes, err = json.Marshal(prj)
-	if err != nil {
-		cErr := &chainError{"readProject", p.ProjectID, CODEGENEXCEPTION, err}
-		return shim.Error(cErr.Error())
+// approvedBudget - sum of approved milestone budgets, in project's base currency. Flag is false if project defines no milestone
+// i.e. spends are not capped by milestones
+func (p *project) approvedBudget() (decimal.Decimal, bool) {
+	budget, _ := decimal.NewFromString("0")
+	for _, m := range p.Data.Milestones {
+		if m.Approved {
+			budget = budget.Add(m.Budget)
+		}
 	}
+	return budget, len(p.Data.Milestones) != 0
+}
 
-	// Write the new value of available funds on ledger
-	err = stub.PutState(p.ProjectID, prjBytes)
-	if err != nil {
-		cErr := &chainError{"readProject", p.ProjectID, CODEGENEXCEPTION, err}
-		return shim.Error(cErr.Error())
-	}
+// load - read project from the ledger into the receiver
+func (p *project) load(stub shim.ChaincodeStubInterface, fcn string) *chainError {
+	return newRepository(stub, fcn).read(p)
+}
 
-	// Read and return updated project data
-	prjBytes, err = stub.GetState(p.ProjectID)
-	if err != nil {
-		cErr := &chainError{"readProject", p.ProjectID, CODEGENEXCEPTION, err}
-		return shim.Error(cErr.Error())
+// upgradeProject - projects written before schema versioning carry no base currency and hold their funds
+// at top level only, in base currency
+func upgradeProject(doc map[string]interface{}) {
+
+	data, ok := doc["data"].(map[string]interface{})
+	if !ok {
+		return
 	}
-	r := response{CODEALLAOK, p.ProjectID, prjBytes}
-	return shim.Success((r.formatResponse()))
+	currency, _ := data["baseCurrency"].(string)
+	if len(currency) == 0 {
+		currency = DEFCCY
+		data["baseCurrency"] = currency
+	}
+	if balances, ok := data["balances"].(map[string]interface{}); ok && len(balances) != 0 {
+		return
+	}
+	funds := make(map[string]interface{})
+	for _, field := range []string{"avlFund", "spentFund", "pledgedFund", "reservedFund"} {
+		if amount, ok := data[field]; ok {
+			funds[field] = amount
+		}
+	}
+	if len(funds) != 0 {
+		data["balances"] = map[string]interface{}{currency: funds}
+	}
+}
+
+// store - write project state to the ledger
+func (p *project) store(stub shim.ChaincodeStubInterface, fcn string) *chainError {
+	return newRepository(stub, fcn).save(p)
 }
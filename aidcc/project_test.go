@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
@@ -68,3 +69,235 @@ func TestProjectRW(t *testing.T) {
 		}
 	}
 }
+
+// Verifies response envelope of successful and failed invokes, including values needing JSON escaping
+func TestResponseEnvelope(t *testing.T) {
+	fmt.Println("Executing Test - ResponseEnvelope")
+
+	// Test data - Refer to test narratives for test description. Invokes are executed in order
+	var envelopeTable = []struct {
+		args           []string
+		expectedStatus int32
+		expectedCode   string
+		expectedMsg    string
+		testNarrative  string
+	}{
+		{[]string{AddProject, `P"101\`, `Relief "Phase 1" \ North`}, 200, CODEALLAOK, `P"101\`, "Happy scenario - key with quote & backslash"},
+		{[]string{GetProject, `P"101\`}, 200, CODEALLAOK, `P"101\`, "Happy scenario - read with payload"},
+		{[]string{AddProject, `P"101\`, "Prj101"}, 500, CODEAlRDEXIST, "Asset with key already exists", "Check for duplicate project"},
+		{[]string{GetProject, ""}, 500, CODEUNPROCESSABLEENTITY, "Project ID can not be empty", "Check for validation error"},
+		{[]string{"NoSuchFunction"}, 500, CODEUNKNOWNINVOKE, "Unknown function invoke", "Check for unknown function"},
+		{[]string{Init, "1"}, 500, CODEUNKNOWNINVOKE, "Unknown function invoke", "Check for Init not routed through invoke"},
+	}
+
+	// struct for parsing the envelope
+	type envResp struct {
+		Code      string          `json:"code"`
+		Message   string          `json:"message"`
+		Payload   json.RawMessage `json:"payload"`
+		TxID      string          `json:"txID"`
+		TimeStamp *time.Time      `json:"timeStamp"`
+		Errors    []errorDetail   `json:"errors"`
+	}
+	assert := assert.New(t)
+
+	// Instantiate mockStub using AidChaincode as the target chaincode to unit test
+	stub := shim.NewMockStub("TestStub", new(AidChaincode))
+	assert.NotNil(stub, "Stub is nil, Test stub creation failed")
+
+	// Executing envelope tests
+	for _, test := range envelopeTable {
+		uid := uuid.New().String()
+		args := make([][]byte, len(test.args))
+		for i, arg := range test.args {
+			args[i] = []byte(arg)
+		}
+		result := stub.MockInvoke(uid, args)
+		assert.Equal(test.expectedStatus, result.GetStatus(), test.testNarrative+" failed - "+(result.GetMessage()))
+
+		env := &envResp{}
+		err := json.Unmarshal(result.GetPayload(), env)
+		if !assert.NoError(err, test.testNarrative+" failed - envelope is not valid JSON") {
+			continue
+		}
+		assert.Equal(test.expectedCode, env.Code, test.testNarrative+" failed - code mismatch")
+		assert.Equal(test.expectedMsg, env.Message, test.testNarrative+" failed - message mismatch")
+		assert.Equal(uid, env.TxID, test.testNarrative+" failed - txID mismatch")
+		assert.NotNil(env.TimeStamp, test.testNarrative+" failed - timestamp missing")
+		assert.NotNil(env.Errors, test.testNarrative+" failed - errors missing")
+		if test.expectedStatus == shim.OK {
+			assert.Empty(env.Errors, test.testNarrative+" failed - unexpected errors")
+			continue
+		}
+
+		// Failed invokes return the envelope as message too, with structured error details
+		assert.JSONEq(string(result.GetPayload()), result.GetMessage(), test.testNarrative+" failed - message is not the envelope")
+		assert.Equal("null", string(env.Payload), test.testNarrative+" failed - unexpected payload")
+		if assert.Len(env.Errors, 1, test.testNarrative+" failed - error details mismatch") {
+			assert.Equal(test.expectedCode, env.Errors[0].Code, test.testNarrative+" failed - error detail code mismatch")
+			assert.Equal(test.expectedMsg, env.Errors[0].Message, test.testNarrative+" failed - error detail message mismatch")
+		}
+	}
+
+	// Verify escaped values round trip through the payload
+	result := stub.MockInvoke(uuid.New().String(),
+		[][]byte{[]byte(GetProject),
+			[]byte(`P"101\`)})
+	env := &envResp{}
+	err := json.Unmarshal(result.GetPayload(), env)
+	if err != nil {
+		panic(err)
+	}
+	p := &project{}
+	err = json.Unmarshal(env.Payload, p)
+	if err != nil {
+		panic(err)
+	}
+	assert.Equal(`Relief "Phase 1" \ North`, p.Data.ProjectName, "Project name mismatch")
+}
+
+// Verifies deltas pending under the legacy index are aggregated into the project of their donation or spend
+func TestLegacyDeltas(t *testing.T) {
+	fmt.Println("Executing Test - LegacyDeltas")
+
+	// Legacy donations & spends along with their deltas, written before deltas were kept per project
+	legacyTxns := []struct {
+		txnID     string
+		docType   string
+		bitmask   string
+		projectID string
+		amount    string
+	}{
+		{"D101", DONIN, "1", "P101", "500.0000"},
+		{"D102", DONIN, "1", "P102", "300.0000"},
+		{"S101", DONOUT, "0", "P101", "20.0000"},
+	}
+
+	// Test data - Refer to test narratives for test description. Projects are read in order
+	var readTable = []struct {
+		projectID       string
+		expectedAvlFund string
+		expectedSpent   string
+		expectedPending int
+		testNarrative   string
+	}{
+		{"P101", "480", "20", 1, "Happy scenario - legacy deltas of the project aggregated"},
+		{"P101", "480", "20", 1, "Happy scenario - legacy deltas aggregated once"},
+		{"P102", "300", "0", 0, "Happy scenario - legacy deltas of another project aggregated"},
+	}
+
+	// struct for parsing the shim APIs response
+	type resp struct {
+		Payload project `json:"payload"`
+	}
+	assert := assert.New(t)
+
+	// Instantiate mockStub using AidChaincode as the target chaincode to unit test
+	stub := shim.NewMockStub("TestStub", new(AidChaincode))
+	assert.NotNil(stub, "Stub is nil, Test stub creation failed")
+
+	for _, projectID := range []string{"P101", "P102"} {
+		result := stub.MockInvoke(uuid.New().String(), [][]byte{[]byte(AddProject), []byte(projectID), []byte("Prj" + projectID)})
+		assert.EqualValues(shim.OK, result.GetStatus(), AddProject+" failed - "+result.GetMessage())
+	}
+	stub.MockTransactionStart(uuid.New().String())
+	for _, txn := range legacyTxns {
+		doc := `{"docType":"` + txn.docType + `","txnID":"` + txn.txnID + `","donor":"vishal","data":{"projectID":"` + txn.projectID + `","itemID":"Itm001","amount":"` + txn.amount + `"}}`
+		err := stub.PutState(txn.txnID, []byte(doc))
+		if err != nil {
+			panic(err)
+		}
+		indexKey, _ := stub.CreateCompositeKey(LGCYIDX, []string{txn.bitmask, txn.txnID, txn.amount})
+		err = stub.PutState(indexKey, []byte{0x00})
+		if err != nil {
+			panic(err)
+		}
+	}
+	stub.MockTransactionEnd("")
+
+	// Executing tests
+	for _, test := range readTable {
+		result := stub.MockInvoke(uuid.New().String(), [][]byte{[]byte(GetProject), []byte(test.projectID)})
+		assert.EqualValues(shim.OK, result.GetStatus(), test.testNarrative+" failed - "+result.GetMessage())
+
+		r := &resp{}
+		err := json.Unmarshal(result.GetPayload(), r)
+		if err != nil {
+			panic(err)
+		}
+		assert.Equal(test.expectedAvlFund, r.Payload.Data.AvlFund.String(), test.testNarrative+" failed - available fund mismatch")
+		assert.Equal(test.expectedSpent, r.Payload.Data.SpentFund.String(), test.testNarrative+" failed - spent fund mismatch")
+
+		pending := 0
+		deltaItr, _ := stub.GetStateByPartialCompositeKey(LGCYIDX, []string{})
+		for deltaItr.HasNext() {
+			deltaItr.Next()
+			pending++
+		}
+		deltaItr.Close()
+		assert.Equal(test.expectedPending, pending, test.testNarrative+" failed - pending legacy deltas mismatch")
+	}
+}
+
+// Verifies funds of a project written before balances per currency are kept & spendable
+func TestLegacyProject(t *testing.T) {
+	fmt.Println("Executing Test - LegacyProject")
+
+	// Project as written before balances per currency, funds held at top level
+	legacyDoc := `{"docType":"` + GPRJCT + `","projectID":"P101","data":{"projectName":"Prj101","runBy":"Test Caller","startDt":"2019-01-01T00:00:00Z","avlFund":"500","spentFund":"20"}}`
+
+	// Test data - Refer to test narratives for test description. Invokes are executed in order
+	var invokeTable = []struct {
+		args            []string
+		expectedAvlFund string
+		expectedSpent   string
+		testNarrative   string
+	}{
+		{[]string{GetProject, "P101"}, "500", "20", "Happy scenario - legacy funds kept"},
+		{[]string{AddSpend, "S101", "vishal", "P101", "Itm001", "100"}, "", "", "Happy scenario - legacy funds spendable"},
+		{[]string{GetProject, "P101"}, "400", "120", "Happy scenario - spend aggregated into legacy funds"},
+	}
+
+	// struct for parsing the shim APIs response
+	type resp struct {
+		Payload project `json:"payload"`
+	}
+	assert := assert.New(t)
+
+	// Instantiate mockStub using AidChaincode as the target chaincode to unit test
+	stub := shim.NewMockStub("TestStub", new(AidChaincode))
+	assert.NotNil(stub, "Stub is nil, Test stub creation failed")
+
+	result := stub.MockInvoke(uuid.New().String(), [][]byte{[]byte(AddItem), []byte("Itm001"), []byte("Item001"), []byte("Medicine")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Saving test item to state failed.")
+	stub.MockTransactionStart(uuid.New().String())
+	err := stub.PutState("P101", []byte(legacyDoc))
+	if err != nil {
+		panic(err)
+	}
+	stub.MockTransactionEnd("")
+
+	// Executing tests
+	for _, test := range invokeTable {
+		args := make([][]byte, len(test.args))
+		for i, arg := range test.args {
+			args[i] = []byte(arg)
+		}
+		result := stub.MockInvoke(uuid.New().String(), args)
+		assert.EqualValues(shim.OK, result.GetStatus(), test.testNarrative+" failed - "+result.GetMessage())
+		if test.args[0] != GetProject {
+			continue
+		}
+
+		r := &resp{}
+		err := json.Unmarshal(result.GetPayload(), r)
+		if err != nil {
+			panic(err)
+		}
+		assert.Equal(test.expectedAvlFund, r.Payload.Data.AvlFund.String(), test.testNarrative+" failed - available fund mismatch")
+		assert.Equal(test.expectedSpent, r.Payload.Data.SpentFund.String(), test.testNarrative+" failed - spent fund mismatch")
+		bal := r.Payload.Data.Balances[DEFCCY]
+		assert.Equal(test.expectedAvlFund, bal.AvlFund.String(), test.testNarrative+" failed - base currency balance mismatch")
+		assert.Equal(test.expectedSpent, bal.SpentFund.String(), test.testNarrative+" failed - base currency spent mismatch")
+	}
+}
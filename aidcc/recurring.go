@@ -0,0 +1,248 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/shopspring/decimal"
+	"github.com/vishal3152/AIDChaincode/aidcc/events"
+)
+
+// Asset model for recurring donation schedule. All asset models are kept in
+// private scope i.e. they are not exported and ramin invisible to other packages
+// A schedule describes a fixed amount donated every interval between start and end date.
+// Each instalment is recorded as a donation linked to the schedule and to a period.
+type recurringBase struct {
+	ProjectID   string          `json:"projectID"`
+	ItemID      string          `json:"itemID"`
+	Amount      decimal.Decimal `json:"amount"`   // instalment amount
+	Currency    string          `json:"currency"` // ISO 4217 code of instalments
+	Interval    string          `json:"interval"` // WEEKLY, MONTHLY, QUARTERLY or YEARLY
+	StartDt     time.Time       `json:"startDt"`  // due date of the first instalment
+	EndDt       time.Time       `json:"endDt"`    // no instalment is due after end date
+	Instalments []instalment    `json:"instalments"`
+}
+
+// instalment - a period of the schedule and the donation recorded for it, if any
+type instalment struct {
+	Period int       `json:"period"` // 1 for the first instalment
+	DueDt  time.Time `json:"dueDt"`
+	TxnID  string    `json:"txnID,omitempty"`
+}
+
+type recurringDonation struct {
+	ObjectType string        `json:"docType"` // recurring donation Type 'DONREC'
+	versioned                // schema version of the document
+	ScheduleID string        `json:"scheduleID"` // asset unique key
+	Donor      string        `json:"donor"`
+	Data       recurringBase `json:"data"` // composition
+}
+
+// key - ledger key of the recurring donation
+func (rd *recurringDonation) key() string {
+	return rd.ScheduleID
+}
+
+// docType - doc type of recurring donation assets
+func (rd *recurringDonation) docType() string {
+	return DONREC
+}
+
+// validate - check recurring donation invariants before it is written
+func (rd *recurringDonation) validate() *chainError {
+	return checkIdentity(rd, rd.ObjectType)
+}
+
+// dueDate - due date of a period. Dates are always derived from the start date to avoid drifting
+// e.g. a monthly schedule starting on 31st
+func (rd *recurringDonation) dueDate(period int) time.Time {
+	n := period - 1
+	switch rd.Data.Interval {
+	case WEEKLY:
+		return rd.Data.StartDt.AddDate(0, 0, 7*n)
+	case QUARTERLY:
+		return addMonths(rd.Data.StartDt, 3*n)
+	case YEARLY:
+		return addMonths(rd.Data.StartDt, 12*n)
+	default:
+		return addMonths(rd.Data.StartDt, n)
+	}
+}
+
+// addMonths - add months to a date, clamped to the last day of the resulting month. Unlike AddDate
+// 31st Jan plus a month is 28th or 29th Feb, not 3rd Mar
+func addMonths(t time.Time, months int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	day := t.Day()
+	if lastDay := first.AddDate(0, 1, -1).Day(); day > lastDay {
+		day = lastDay
+	}
+	return first.AddDate(0, 0, day-1)
+}
+
+// periodAt - period in which the given time falls, 0 if before start date
+func (rd *recurringDonation) periodAt(t time.Time) int {
+	period := 0
+	for !rd.dueDate(period + 1).After(t) {
+		period++
+	}
+	return period
+}
+
+// Write recurring donation schedule to ledger
+func (rd *recurringDonation) putState(stub shim.ChaincodeStubInterface) pb.Response {
+
+	// check if affiliated project exists
+	c, cErr := checkAsset(stub, rd.Data.ProjectID)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	} else if !c {
+		e := &chainError{"putRecurring", rd.ScheduleID, CODENOTFOUND, errors.New("Affiliated project not found")}
+		return errorResponse(stub, e)
+	}
+
+	// resolve instalment currency, defaults to project's base currency
+	rd.Data.Currency, rd.Data.Amount, cErr = resolveCurrency(stub, "putRecurring", rd.ScheduleID, rd.Data.ProjectID, rd.Data.Currency, rd.Data.Amount)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	// check if affiliated item exists
+	c, cErr = checkAsset(stub, rd.Data.ItemID)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	} else if !c {
+		e := &chainError{"putRecurring", rd.ScheduleID, CODENOTFOUND, errors.New("Affiliated item not found")}
+		return errorResponse(stub, e)
+	}
+
+	// check if scheduleID is unique
+	c, cErr = checkAsset(stub, rd.ScheduleID)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	} else if c {
+		e := &chainError{"putRecurring", rd.ScheduleID, CODEAlRDEXIST, errors.New("Asset with key already exists")}
+		return errorResponse(stub, e)
+	}
+
+	cErr = rd.store(stub, "putRecurring")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	// Emit transaction event for listeners
+	cErr = emitEvent(stub, "putRecurring", rd.ScheduleID, events.RecurringDonationScheduled, &events.RecurringDonation{ScheduleID: rd.ScheduleID, ProjectID: rd.Data.ProjectID, ItemID: rd.Data.ItemID, Donor: rd.Donor, Amount: rd.Data.Amount, Currency: rd.Data.Currency,
+		Interval: rd.Data.Interval, StartDt: rd.Data.StartDt, EndDt: rd.Data.EndDt, Instalments: len(rd.Data.Instalments)})
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	r := response{CODEALLAOK, rd.ScheduleID, nil}
+	return shim.Success((r.formatResponse(stub)))
+}
+
+// Read recurring donation schedule from the ledger
+func (rd *recurringDonation) getState(stub shim.ChaincodeStubInterface) pb.Response {
+
+	sch, cErr := queryAsset(stub, rd.ScheduleID)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	r := response{CODEALLAOK, "OK", sch}
+	return shim.Success((r.formatResponse(stub)))
+}
+
+// Record an instalment against the schedule. The instalment is validated against the schedule
+// i.e. amount must match and only one instalment is allowed per period, then it is written as a
+// donation linked to the schedule. Period 0 records the instalment for the current period, an overdue
+// instalment is recorded against its own period.
+func (rd *recurringDonation) instalmentState(stub shim.ChaincodeStubInterface, txnID string, amount decimal.Decimal, period int, timeStamp time.Time) pb.Response {
+
+	cErr := rd.load(stub, "putInstalment")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	if !amount.Equal(rd.Data.Amount) {
+		cErr = &chainError{"putInstalment", txnID, CODENOTALLWD, errors.New("Instalment amount does not match schedule amount " + rd.Data.Amount.String() + " " + rd.Data.Currency)}
+		return errorResponse(stub, cErr)
+	}
+	current := rd.periodAt(timeStamp)
+	if current == 0 {
+		cErr = &chainError{"putInstalment", txnID, CODENOTALLWD, errors.New("Schedule not started yet")}
+		return errorResponse(stub, cErr)
+	}
+	if period == 0 {
+		period = current
+	} else if period > current {
+		cErr = &chainError{"putInstalment", txnID, CODENOTALLWD, errors.New("Instalment for period " + strconv.Itoa(period) + " not due yet")}
+		return errorResponse(stub, cErr)
+	}
+	if rd.dueDate(period).After(rd.Data.EndDt) {
+		cErr = &chainError{"putInstalment", txnID, CODENOTALLWD, errors.New("Schedule ended")}
+		return errorResponse(stub, cErr)
+	}
+	for _, inst := range rd.Data.Instalments {
+		if inst.Period == period {
+			cErr = &chainError{"putInstalment", txnID, CODEAlRDEXIST, errors.New("Instalment for period " + strconv.Itoa(period) + " already recorded by " + inst.TxnID)}
+			return errorResponse(stub, cErr)
+		}
+	}
+
+	// check if donor is not a sanctioned party before the period is taken
+	cErr = screenParty(stub, "putInstalment", txnID, "donor", rd.Donor)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	rd.Data.Instalments = append(rd.Data.Instalments, instalment{Period: period, DueDt: rd.dueDate(period), TxnID: txnID})
+	cErr = rd.store(stub, "putInstalment")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	donBase := donationBase{ProjectID: rd.Data.ProjectID, ItemID: rd.Data.ItemID, Amount: amount, Currency: rd.Data.Currency, TimeStamp: timeStamp}
+	d := &donation{ObjectType: DONIN, TxnID: txnID, Donor: rd.Donor, ScheduleID: rd.ScheduleID, Period: period, Data: donBase}
+	return saveAsset(stub, d)
+}
+
+// Read missed instalments i.e. elapsed periods of the schedule without a recorded instalment
+func (rd *recurringDonation) missedState(stub shim.ChaincodeStubInterface, timeStamp time.Time) pb.Response {
+
+	cErr := rd.load(stub, "readMissed")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	recorded := make(map[int]bool)
+	for _, inst := range rd.Data.Instalments {
+		recorded[inst.Period] = true
+	}
+	// A period is missed once the next period is due, periods after end date are never due
+	missed := []instalment{}
+	for period := 1; !rd.dueDate(period+1).After(timeStamp) && !rd.dueDate(period).After(rd.Data.EndDt); period++ {
+		if !recorded[period] {
+			missed = append(missed, instalment{Period: period, DueDt: rd.dueDate(period)})
+		}
+	}
+
+	b, err := json.Marshal(missed)
+	if err != nil {
+		cErr = &chainError{"readMissed", rd.ScheduleID, CODEGENEXCEPTION, err}
+		return errorResponse(stub, cErr)
+	}
+	r := response{CODEALLAOK, rd.ScheduleID, b}
+	return shim.Success((r.formatResponse(stub)))
+}
+
+// load - read schedule from the ledger into the receiver
+func (rd *recurringDonation) load(stub shim.ChaincodeStubInterface, fcn string) *chainError {
+	return newRepository(stub, fcn).read(rd)
+}
+
+// store - write schedule state to the ledger
+func (rd *recurringDonation) store(stub shim.ChaincodeStubInterface, fcn string) *chainError {
+	return newRepository(stub, fcn).save(rd)
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/stretchr/testify/assert"
+)
+
+// Verifies scenarios related to recurring donation schedules and instalments
+func TestRecurringRW(t *testing.T) {
+	fmt.Println("Executing Test - RecurringRW")
+
+	// Weekly schedule started 3 weeks ago i.e. 4th instalment is due today
+	today := time.Now().UTC()
+	startDt := today.AddDate(0, 0, -21).Format(DATEFMT)
+	endDt := today.AddDate(1, 0, 0).Format(DATEFMT)
+
+	// Test data - Refer to test narratives for test description
+	var scheduleTable = []struct {
+		scheduleID     string
+		projectID      string
+		amount         string
+		interval       string
+		startDt        string
+		endDt          string
+		expectedStatus int32
+		testNarrative  string
+	}{
+		{"R101", "P101", "10", WEEKLY, startDt, endDt, 200, "Happy scenario"},
+		{"R102", "P101", "10", WEEKLY, startDt, startDt, 200, "Happy scenario - ended schedule"},
+		{"R103", "P101", "0", WEEKLY, startDt, endDt, 500, "Check for positive amount"},
+		{"R103", "P101", "10", "DAILY", startDt, endDt, 500, "Check for interval"},
+		{"R103", "P101", "10", WEEKLY, "01/01/2019", endDt, 500, "Check for start date format"},
+		{"R103", "P101", "10", WEEKLY, startDt, "2019/12/31", 500, "Check for end date format"},
+		{"R103", "P101", "10", WEEKLY, endDt, startDt, 500, "Check for end date before start date"},
+		{"R103", "P102", "10", WEEKLY, startDt, endDt, 500, "Check for project ID existence"},
+		{"R101", "P101", "10", WEEKLY, startDt, endDt, 500, "Check for duplicate schedule ID"},
+	}
+	var instalmentTable = []struct {
+		txnID          string
+		scheduleID     string
+		amount         string
+		period         string // optional, current period if empty
+		expectedStatus int32
+		expectedPeriod int
+		testNarrative  string
+	}{
+		{"D101", "R101", "10", "", 200, 4, "Happy scenario"},
+		{"D102", "R101", "10", "", 500, 0, "Check for duplicate instalment for the period"},
+		{"D103", "R101", "20", "", 500, 0, "Check for instalment amount"},
+		{"D104", "R102", "10", "", 500, 0, "Check for ended schedule"},
+		{"D105", "R109", "10", "", 500, 0, "Check for schedule existence"},
+		{"D106", "R101", "10", "2", 200, 2, "Happy scenario - overdue instalment for its own period"},
+		{"D107", "R101", "10", "2", 500, 0, "Check for duplicate overdue instalment"},
+		{"D108", "R101", "10", "5", 500, 0, "Check for period not due yet"},
+		{"D109", "R101", "10", "0", 500, 0, "Check for period from 1"},
+		{"D110", "R102", "10", "1", 200, 1, "Happy scenario - overdue instalment of an ended schedule"},
+	}
+
+	// struct for parsing the shim APIs response
+	type rResp struct {
+		Code    string            `json:"code"`
+		Message string            `json:"message"`
+		Payload recurringDonation `json:"payload"`
+	}
+	type dResp struct {
+		Code    string   `json:"code"`
+		Message string   `json:"message"`
+		Payload donation `json:"payload"`
+	}
+	type mResp struct {
+		Code    string       `json:"code"`
+		Message string       `json:"message"`
+		Payload []instalment `json:"payload"`
+	}
+	assert := assert.New(t)
+
+	// Instantiate mockStub using AidChaincode as the target chaincode to unit test
+	stub := shim.NewMockStub("TestStub", new(AidChaincode))
+	assert.NotNil(stub, "Stub is nil, Test stub creation failed")
+
+	uid := uuid.New().String()
+
+	// Add test project ID i.e. P101 and item ID i.e. Itm001 to state.
+	result := stub.MockInvoke(uid,
+		[][]byte{[]byte(AddProject),
+			[]byte("P101"),
+			[]byte("Prj101")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Saving test project to state failed.")
+
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(AddItem),
+			[]byte("Itm001"),
+			[]byte("Item001"),
+			[]byte("Medicine")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Saving test item to state failed.")
+
+	// Executing schedule tests
+	for _, test := range scheduleTable {
+		result := stub.MockInvoke(uid,
+			[][]byte{[]byte(AddRecurringDonation),
+				[]byte(test.scheduleID),
+				[]byte("vishal"),
+				[]byte(test.projectID),
+				[]byte("Itm001"),
+				[]byte(test.amount),
+				[]byte(test.interval),
+				[]byte(test.startDt),
+				[]byte(test.endDt)})
+
+		assert.Equal(test.expectedStatus, result.GetStatus(), test.testNarrative+" failed - "+(result.GetMessage()))
+	}
+
+	// Executing instalment tests
+	for _, test := range instalmentTable {
+		args := [][]byte{[]byte(RecordInstalment),
+			[]byte(test.txnID),
+			[]byte(test.scheduleID),
+			[]byte(test.amount)}
+		if len(test.period) != 0 {
+			args = append(args, []byte(test.period))
+		}
+		result := stub.MockInvoke(uid, args)
+
+		assert.Equal(test.expectedStatus, result.GetStatus(), test.testNarrative+" failed - "+(result.GetMessage()))
+
+		if result.GetStatus() == shim.OK {
+			// Verify instalment is recorded as a donation linked to the schedule
+			result = stub.MockInvoke(uid,
+				[][]byte{[]byte(GetDonation),
+					[]byte(test.txnID)})
+			assert.EqualValues(shim.OK, result.GetStatus(), GetDonation+" failed to read the donation data")
+
+			d := &dResp{}
+			err := json.Unmarshal(result.GetPayload(), d)
+			if err != nil {
+				panic(err)
+			}
+			assert.Equal(test.scheduleID, d.Payload.ScheduleID, "Instalment not linked to the schedule")
+			assert.Equal(test.expectedPeriod, d.Payload.Period, "Instalment recorded for wrong period")
+
+			result = stub.MockInvoke(uid,
+				[][]byte{[]byte(GetRecurringDonation),
+					[]byte(test.scheduleID)})
+			assert.EqualValues(shim.OK, result.GetStatus(), GetRecurringDonation+" failed to read the schedule data")
+
+			r := &rResp{}
+			err = json.Unmarshal(result.GetPayload(), r)
+			if err != nil {
+				panic(err)
+			}
+			recorded := false
+			for _, inst := range r.Payload.Data.Instalments {
+				recorded = recorded || (inst.TxnID == test.txnID && inst.Period == test.expectedPeriod)
+			}
+			assert.True(recorded, "Instalment not linked to the schedule")
+		}
+	}
+
+	// Verify periods 1 & 3 are reported as missed, period 2 was recorded late
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(GetMissedInstalments),
+			[]byte("R101")})
+	assert.EqualValues(shim.OK, result.GetStatus(), GetMissedInstalments+" failed to read the missed instalments")
+
+	m := &mResp{}
+	err := json.Unmarshal(result.GetPayload(), m)
+	if err != nil {
+		panic(err)
+	}
+	if assert.Len(m.Payload, 2, "Missed instalments mismatch") {
+		assert.Equal(1, m.Payload[0].Period, "Missed instalment period mismatch")
+		assert.Equal(3, m.Payload[1].Period, "Missed instalment period mismatch")
+	}
+
+	// Verify missed instalments are capped at schedule end date
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(GetMissedInstalments),
+			[]byte("R102")})
+	assert.EqualValues(shim.OK, result.GetStatus(), GetMissedInstalments+" failed to read the missed instalments")
+
+	m = &mResp{}
+	err = json.Unmarshal(result.GetPayload(), m)
+	if err != nil {
+		panic(err)
+	}
+	assert.Len(m.Payload, 0, "Missed instalments after schedule end date")
+}
+
+// Verifies due dates of schedule periods, month end dates are clamped to the last day of the month
+func TestDueDate(t *testing.T) {
+	fmt.Println("Executing Test - DueDate")
+
+	// Test data - Refer to test narratives for test description
+	var dueTable = []struct {
+		interval      string
+		startDt       string
+		period        int
+		expectedDueDt string
+		testNarrative string
+	}{
+		{MONTHLY, "2019-01-31", 1, "2019-01-31", "Happy scenario - 1st period due on start date"},
+		{MONTHLY, "2019-01-31", 2, "2019-02-28", "Check for month end clamped in February"},
+		{MONTHLY, "2020-01-31", 2, "2020-02-29", "Check for month end clamped in a leap year"},
+		{MONTHLY, "2019-01-31", 3, "2019-03-31", "Check for no drift after a short month"},
+		{MONTHLY, "2019-01-31", 4, "2019-04-30", "Check for month end clamped in a 30 day month"},
+		{MONTHLY, "2019-11-30", 3, "2020-01-30", "Check for year rollover"},
+		{QUARTERLY, "2019-11-30", 2, "2020-02-29", "Check for quarterly month end clamped"},
+		{YEARLY, "2020-02-29", 2, "2021-02-28", "Check for leap day clamped"},
+		{WEEKLY, "2019-01-31", 2, "2019-02-07", "Happy scenario - weekly"},
+	}
+
+	assert := assert.New(t)
+
+	// Executing tests
+	for _, test := range dueTable {
+		startDt, _ := time.Parse(DATEFMT, test.startDt)
+		rd := &recurringDonation{Data: recurringBase{Interval: test.interval, StartDt: startDt}}
+		assert.Equal(test.expectedDueDt, rd.dueDate(test.period).Format(DATEFMT), test.testNarrative+" failed")
+		assert.Equal(test.period, rd.periodAt(rd.dueDate(test.period)), test.testNarrative+" failed - period mismatch")
+	}
+}
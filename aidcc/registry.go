@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+// Every chaincode function is registered along with its argument schema, the caller role it requires and
+// whether it writes to the ledger. Invoke routes functions through the registry, enforcing the role before
+// the function validates its arguments, and ListFunctions describes the registered API to clients.
+// A new function is added by a single registration in init below.
+
+// handler - chaincode function registered with its API description
+type handler struct {
+	Name  string     `json:"name"`
+	Write bool       `json:"write"`          // false for queries, nothing is written to the ledger
+	Role  string     `json:"role,omitempty"` // caller role required, open to all callers if empty
+	Args  []argField `json:"args"`           // in the order of positional arguments
+	fn    func(stub shim.ChaincodeStubInterface, args []string) pb.Response
+}
+
+// registry - registered functions by name, functions - in the order of registration
+var (
+	registry  = make(map[string]*handler)
+	functions = []*handler{}
+)
+
+// register - add functions to the registry, a function is registered once only
+func register(handlers ...handler) {
+	for i := range handlers {
+		h := handlers[i]
+		if _, ok := registry[h.Name]; ok {
+			panic("Function " + h.Name + " is already registered")
+		}
+		registry[h.Name] = &h
+		functions = append(functions, &h)
+	}
+}
+
+func init() {
+	register(
+		handler{Name: AddProject, Write: true, Args: []argField{{"projectID", ARGSTR, true}, {"projectName", ARGSTR, true}, {"baseCurrency", ARGSTR, false}, {"overheadRate", ARGAMT, false}, {"overheadPrj", ARGSTR, false}}, fn: validateProjectW},
+		handler{Name: AddItem, Write: true, Args: []argField{{"itemID", ARGSTR, true}, {"itemType", ARGSTR, true}, {"narrative", ARGSTR, true}}, fn: validateItemW},
+		handler{Name: AddDonation, Write: true, Args: []argField{{"txnID", ARGSTR, true}, {"donor", ARGSTR, true}, {"projectID", ARGSTR, true}, {"itemID", ARGSTR, true}, {"amount", ARGAMT, true}, {"pledgeID", ARGSTR, false}, {"currency", ARGSTR, false}}, fn: validateDonationW},
+		handler{Name: AddSpend, Write: true, Args: []argField{{"txnID", ARGSTR, true}, {"beneficiary", ARGSTR, true}, {"projectID", ARGSTR, true}, {"itemID", ARGSTR, true}, {"amount", ARGAMT, true}, {"currency", ARGSTR, false}, {"category", ARGSTR, false}, {"vendorID", ARGSTR, false}}, fn: validateSpendW},
+		handler{Name: ReverseSpend, Write: true, Args: []argField{{"txnID", ARGSTR, true}, {"reason", ARGSTR, true}}, fn: validateReversalW},
+		handler{Name: AddPledge, Write: true, Args: []argField{{"pledgeID", ARGSTR, true}, {"donor", ARGSTR, true}, {"projectID", ARGSTR, true}, {"itemID", ARGSTR, true}, {"amount", ARGAMT, true}, {"currency", ARGSTR, false}}, fn: validatePledgeW},
+		handler{Name: CancelPledge, Write: true, Args: []argField{{"pledgeID", ARGSTR, true}}, fn: validatePledgeC},
+		handler{Name: AddRecurringDonation, Write: true, Args: []argField{{"scheduleID", ARGSTR, true}, {"donor", ARGSTR, true}, {"projectID", ARGSTR, true}, {"itemID", ARGSTR, true}, {"amount", ARGAMT, true}, {"interval", ARGSTR, true}, {"startDt", ARGDT, true}, {"endDt", ARGDT, true}, {"currency", ARGSTR, false}}, fn: validateRecurringW},
+		handler{Name: RecordInstalment, Write: true, Args: []argField{{"txnID", ARGSTR, true}, {"scheduleID", ARGSTR, true}, {"amount", ARGAMT, true}, {"period", ARGSTR, false}}, fn: validateInstalmentW},
+		handler{Name: AddMatchingCampaign, Write: true, Args: []argField{{"campaignID", ARGSTR, true}, {"sponsor", ARGSTR, true}, {"projectID", ARGSTR, true}, {"ratio", ARGAMT, true}, {"cap", ARGAMT, true}, {"startDt", ARGDT, true}, {"endDt", ARGDT, true}, {"currency", ARGSTR, false}}, fn: validateCampaignW},
+		handler{Name: AddMilestone, Write: true, Args: []argField{{"projectID", ARGSTR, true}, {"milestoneID", ARGSTR, true}, {"narrative", ARGSTR, true}, {"budget", ARGAMT, true}}, fn: validateMilestoneW},
+		handler{Name: ApproveMilestone, Write: true, Role: ROLEVERIFIER, Args: []argField{{"projectID", ARGSTR, true}, {"milestoneID", ARGSTR, true}}, fn: validateMilestoneA},
+		handler{Name: SetFXRate, Write: true, Role: ROLEORACLE, Args: []argField{{"pair", ARGSTR, true}, {"rate", ARGAMT, true}, {"asOf", ARGDT, true}}, fn: validateFXRateW},
+		handler{Name: AddBudgetLine, Write: true, Args: []argField{{"projectID", ARGSTR, true}, {"category", ARGSTR, true}, {"allocated", ARGAMT, true}}, fn: validateBudgetLineW},
+		handler{Name: AddInKindDonation, Write: true, Args: []argField{{"txnID", ARGSTR, true}, {"donor", ARGSTR, true}, {"projectID", ARGSTR, true}, {"itemID", ARGSTR, true}, {"quantity", ARGAMT, true}, {"unit", ARGSTR, true}, {"estValue", ARGAMT, true}, {"currency", ARGSTR, false}}, fn: validateInKindW},
+		handler{Name: DistributeInKind, Write: true, Args: []argField{{"txnID", ARGSTR, true}, {"beneficiary", ARGSTR, true}, {"projectID", ARGSTR, true}, {"itemID", ARGSTR, true}, {"quantity", ARGAMT, true}, {"unit", ARGSTR, true}}, fn: validateInKindS},
+		handler{Name: AddSpendEvidence, Write: true, Role: ROLEVERIFIER, Args: []argField{{"txnID", ARGSTR, true}, {"documentHash", ARGSTR, true}, {"uri", ARGSTR, true}, {"mediaType", ARGSTR, true}}, fn: validateEvidenceW},
+		handler{Name: IssueVoucher, Write: true, Role: ROLEISSUER, Args: []argField{{"voucherID", ARGSTR, true}, {"beneficiary", ARGSTR, true}, {"projectID", ARGSTR, true}, {"itemID", ARGSTR, true}, {"amount", ARGAMT, true}, {"expiryDt", ARGDT, true}, {"currency", ARGSTR, false}, {"category", ARGSTR, false}}, fn: validateVoucherW},
+		handler{Name: RedeemVoucher, Write: true, Role: ROLEVENDOR, Args: []argField{{"voucherID", ARGSTR, true}}, fn: validateVoucherRdm},
+		handler{Name: ExpireVoucher, Write: true, Role: ROLEISSUER, Args: []argField{{"voucherID", ARGSTR, true}}, fn: validateVoucherExp},
+		handler{Name: AddVendor, Write: true, Role: ROLEVNDADMIN, Args: []argField{{"vendorID", ARGSTR, true}, {"legalName", ARGSTR, true}, {"registrationID", ARGSTR, true}, {"bankRefHash", ARGSTR, true}}, fn: validateVendorW},
+		handler{Name: SetVendorStatus, Write: true, Role: ROLEVNDADMIN, Args: []argField{{"vendorID", ARGSTR, true}, {"status", ARGSTR, true}}, fn: validateVendorS},
+		handler{Name: AddBlockedParty, Write: true, Role: ROLECMPL, Args: []argField{{"partyHash", ARGSTR, true}, {"reason", ARGSTR, true}}, fn: validateBlockedPartyW},
+		handler{Name: RemoveBlockedParty, Write: true, Role: ROLECMPL, Args: []argField{{"partyHash", ARGSTR, true}}, fn: validateBlockedPartyD},
+		handler{Name: RecordScreening, Write: true, Role: ROLECMPL, Args: []argField{{"txnID", ARGSTR, true}, {"party", ARGSTR, true}, {"partyID", ARGSTR, true}}, fn: validateScreeningW},
+		handler{Name: SetAMLLimits, Write: true, Role: ROLECMPL, Args: []argField{{"singleLimit", ARGAMT, true}, {"rollingLimit", ARGAMT, true}, {"anonymousCap", ARGAMT, true}, {"currency", ARGSTR, false}}, fn: validateAMLLimitsW},
+		handler{Name: ApproveDonation, Write: true, Role: ROLECMPL, Args: []argField{{"txnID", ARGSTR, true}, {"remarks", ARGSTR, false}}, fn: func(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+			return validateDonationRvw(stub, args, true)
+		}},
+		handler{Name: RejectDonation, Write: true, Role: ROLECMPL, Args: []argField{{"txnID", ARGSTR, true}, {"remarks", ARGSTR, true}}, fn: func(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+			return validateDonationRvw(stub, args, false)
+		}},
+		handler{Name: MigrateState, Write: true, Role: ROLEADMIN, Args: []argField{{"assetType", ARGSTR, true}, {"batchSize", ARGSTR, true}, {"bookmark", ARGSTR, false}}, fn: validateMigrateW},
+		handler{Name: GetProject, Args: []argField{{"projectID", ARGSTR, true}, {"currency", ARGSTR, false}}, fn: validateProjectR},
+		handler{Name: GetItem, Args: []argField{{"itemID", ARGSTR, true}}, fn: validateItemR},
+		handler{Name: GetDonation, Args: []argField{{"txnID", ARGSTR, true}}, fn: validateDonationR},
+		handler{Name: GetSpend, Args: []argField{{"txnID", ARGSTR, true}}, fn: validateSpendR},
+		handler{Name: GetReversal, Args: []argField{{"txnID", ARGSTR, true}}, fn: validateReversalR},
+		handler{Name: GetPledge, Args: []argField{{"pledgeID", ARGSTR, true}}, fn: validatePledgeR},
+		handler{Name: GetRecurringDonation, Args: []argField{{"scheduleID", ARGSTR, true}}, fn: validateRecurringR},
+		handler{Name: GetMissedInstalments, Args: []argField{{"scheduleID", ARGSTR, true}}, fn: validateMissedR},
+		handler{Name: GetMatchingCampaign, Args: []argField{{"campaignID", ARGSTR, true}}, fn: validateCampaignR},
+		handler{Name: GetMilestone, Args: []argField{{"projectID", ARGSTR, true}, {"milestoneID", ARGSTR, true}}, fn: validateMilestoneR},
+		handler{Name: GetFXRate, Args: []argField{{"pair", ARGSTR, true}, {"asOf", ARGDT, true}}, fn: validateFXRateR},
+		handler{Name: GetBudgetUtilisation, Args: []argField{{"projectID", ARGSTR, true}, {"category", ARGSTR, false}}, fn: validateBudgetR},
+		handler{Name: GetInKind, Args: []argField{{"txnID", ARGSTR, true}}, fn: validateInKindR},
+		handler{Name: GetInventory, Args: []argField{{"projectID", ARGSTR, true}, {"itemID", ARGSTR, false}}, fn: validateInventoryR},
+		handler{Name: VerifySpendEvidence, Args: []argField{{"txnID", ARGSTR, true}, {"documentHash", ARGSTR, true}}, fn: validateEvidenceR},
+		handler{Name: GetUnevidencedSpends, Args: []argField{{"projectID", ARGSTR, true}}, fn: validateUnevidencedR},
+		handler{Name: GetVoucher, Args: []argField{{"voucherID", ARGSTR, true}}, fn: validateVoucherR},
+		handler{Name: GetVendor, Args: []argField{{"vendorID", ARGSTR, true}}, fn: validateVendorR},
+		handler{Name: GetVendorSpending, Args: []argField{{"vendorID", ARGSTR, true}, {"projectID", ARGSTR, false}}, fn: validateVendorSpendR},
+		handler{Name: GetBlockedParty, Args: []argField{{"partyHash", ARGSTR, true}}, fn: validateBlockedPartyR},
+		handler{Name: GetScreenings, Args: []argField{{"txnID", ARGSTR, true}}, fn: validateScreeningR},
+		handler{Name: GetAMLLimits, Args: []argField{}, fn: validateAMLLimitsR},
+		handler{Name: GetReviewQueue, Args: []argField{{"projectID", ARGSTR, false}}, fn: validateReviewQueueR},
+		handler{Name: GetErrorCatalogue, Args: []argField{}, fn: validateCatalogueR},
+		handler{Name: ListFunctions, Args: []argField{}, fn: validateFunctionsR},
+	)
+}
+
+// dispatch - route the invoke to its registered function
+func (t *AidChaincode) dispatch(stub shim.ChaincodeStubInterface, function string, args []string) pb.Response {
+
+	h, ok := registry[function]
+	if !ok {
+		e := &chainError{"Invoke", function, CODEUNKNOWNINVOKE, errors.New("Unknown function invoke")}
+		return errorResponse(stub, e)
+	}
+
+	// Callers without the role of the function are turned away before their arguments are looked at
+	if len(h.Role) != 0 {
+		cErr := checkCallerRole(stub, h.Role)
+		if cErr != nil {
+			return errorResponse(stub, cErr)
+		}
+	}
+
+	// Write invokes may pass a single JSON document in place of positional arguments
+	args, fe := documentArgs(h, args)
+	if fe.failed() {
+		return fe.response(stub)
+	}
+	return h.fn(stub, args)
+}
+
+// functionsState - response listing the registered functions
+func functionsState(stub shim.ChaincodeStubInterface) pb.Response {
+	b, _ := json.Marshal(functions)
+	r := response{CODEALLAOK, "OK", b}
+	return shim.Success((r.formatResponse(stub)))
+}
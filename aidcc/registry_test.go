@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/stretchr/testify/assert"
+)
+
+// Verifies scenarios related to routing of invokes through the function registry
+func TestRegistry(t *testing.T) {
+	fmt.Println("Executing Test - Registry")
+	defer os.Unsetenv("ROLE")
+
+	// Test data - Refer to test narratives for test description. Invokes are executed in order
+	var invokeTable = []struct {
+		args           []string
+		role           string
+		expectedStatus int32
+		expectedCode   string
+		testNarrative  string
+	}{
+		{[]string{AddProject, "P101", "Prj101"}, "", 200, CODEALLAOK, "Happy scenario - write open to all callers"},
+		{[]string{GetProject, "P101"}, "", 200, CODEALLAOK, "Happy scenario - read"},
+		{[]string{SetFXRate, "EUR/USD", "1.1", "2019-03-01"}, ROLEORACLE, 200, CODEALLAOK, "Happy scenario - write with the required role"},
+		{[]string{SetFXRate, "EUR/USD", "1.1", "2019-03-01"}, ROLEVERIFIER, 500, CODEFORBIDDEN, "Check for caller role"},
+		{[]string{SetFXRate, "EUR/USD", "", ""}, ROLEVERIFIER, 500, CODEFORBIDDEN, "Check for caller role before validations"},
+		{[]string{SetFXRate, "EUR/USD", "", ""}, ROLEORACLE, 500, CODEUNPROCESSABLEENTITY, "Check for validations with the required role"},
+		{[]string{"AddDonor", "vishal"}, "", 500, CODEUNKNOWNINVOKE, "Check for unregistered function"},
+		{[]string{Init, "1"}, "", 500, CODEUNKNOWNINVOKE, "Check for Init not routed through invoke"},
+		{[]string{GetProject, `{"projectID":"P101"}`}, "", 500, CODENOTFOUND, "Check for document arguments limited to writes"},
+	}
+
+	// struct for parsing the response envelope
+	type eResp struct {
+		Code string `json:"code"`
+	}
+	assert := assert.New(t)
+
+	// Instantiate mockStub using AidChaincode as the target chaincode to unit test
+	stub := shim.NewMockStub("TestStub", new(AidChaincode))
+	assert.NotNil(stub, "Stub is nil, Test stub creation failed")
+
+	// Executing registry tests
+	for _, test := range invokeTable {
+		os.Setenv("ROLE", test.role)
+		args := make([][]byte, len(test.args))
+		for i, arg := range test.args {
+			args[i] = []byte(arg)
+		}
+		result := stub.MockInvoke(uuid.New().String(), args)
+		assert.Equal(test.expectedStatus, result.GetStatus(), test.testNarrative+" failed - "+result.GetMessage())
+
+		e := &eResp{}
+		err := json.Unmarshal(result.GetPayload(), e)
+		if err != nil {
+			panic(err)
+		}
+		assert.Equal(test.expectedCode, e.Code, test.testNarrative+" failed - code mismatch")
+	}
+}
+
+// Verifies scenarios related to the API description returned by ListFunctions
+func TestListFunctions(t *testing.T) {
+	fmt.Println("Executing Test - ListFunctions")
+
+	// Test data - Refer to test narratives for test description
+	var functionTable = []struct {
+		name          string
+		write         bool
+		role          string
+		args          []string
+		testNarrative string
+	}{
+		{AddDonation, true, "", []string{"txnID", "donor", "projectID", "itemID", "amount", "pledgeID", "currency"}, "Happy scenario - write"},
+		{ApproveMilestone, true, ROLEVERIFIER, []string{"projectID", "milestoneID"}, "Happy scenario - write with role"},
+		{GetFXRate, false, "", []string{"pair", "asOf"}, "Happy scenario - read"},
+		{GetAMLLimits, false, "", []string{}, "Happy scenario - read without arguments"},
+		{ListFunctions, false, "", []string{}, "Happy scenario - ListFunctions itself"},
+	}
+
+	// struct for parsing the shim APIs response
+	type fResp struct {
+		Code    string     `json:"code"`
+		Payload []*handler `json:"payload"`
+	}
+	assert := assert.New(t)
+
+	// Instantiate mockStub using AidChaincode as the target chaincode to unit test
+	stub := shim.NewMockStub("TestStub", new(AidChaincode))
+	assert.NotNil(stub, "Stub is nil, Test stub creation failed")
+
+	result := stub.MockInvoke(uuid.New().String(), [][]byte{[]byte(ListFunctions), []byte("P101")})
+	assert.EqualValues(shim.ERROR, result.GetStatus(), "Check for no of input args failed - error expected")
+
+	result = stub.MockInvoke(uuid.New().String(), [][]byte{[]byte(ListFunctions)})
+	assert.EqualValues(shim.OK, result.GetStatus(), ListFunctions+" failed to list functions")
+
+	f := &fResp{}
+	err := json.Unmarshal(result.GetPayload(), f)
+	if err != nil {
+		panic(err)
+	}
+	assert.Len(f.Payload, len(registry), "Registered functions not all listed")
+	listed := make(map[string]*handler)
+	for _, h := range f.Payload {
+		listed[h.Name] = h
+	}
+
+	for _, test := range functionTable {
+		h, ok := listed[test.name]
+		if !assert.True(ok, test.testNarrative+" failed - function not listed") {
+			continue
+		}
+		assert.Equal(test.write, h.Write, test.testNarrative+" failed - read/write mismatch")
+		assert.Equal(test.role, h.Role, test.testNarrative+" failed - role mismatch")
+		names := make([]string, len(h.Args))
+		for i, a := range h.Args {
+			names[i] = a.Name
+		}
+		assert.Equal(test.args, names, test.testNarrative+" failed - arguments mismatch")
+	}
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+)
+
+// repository - typed create, read, update, delete & list of assets over the stub. Assets are kept under
+// their key and indexed by doc type under ASTIDX, so that every asset type gets the same keys, listing
+// and errors. Errors are reported against the function using the repository.
+type repository struct {
+	stub shim.ChaincodeStubInterface
+	fcn  string
+}
+
+// newRepository - repository of the function
+func newRepository(stub shim.ChaincodeStubInterface, fcn string) *repository {
+	return &repository{stub: stub, fcn: fcn}
+}
+
+// exists - check if the asset key is taken on the ledger
+func (r *repository) exists(a AidAssetInterface) (bool, *chainError) {
+	return checkAsset(r.stub, a.key())
+}
+
+// create - write a new asset, key must not be taken
+func (r *repository) create(a AidAssetInterface) *chainError {
+
+	c, cErr := r.exists(a)
+	if cErr != nil {
+		return cErr
+	}
+	if c {
+		return &chainError{r.fcn, a.key(), CODEAlRDEXIST, errors.New("Asset with key already exists")}
+	}
+	return r.save(a)
+}
+
+// read - read asset state into the receiver, asset of another type under the key is not found
+func (r *repository) read(a AidAssetInterface) *chainError {
+
+	b, cErr := queryAsset(r.stub, a.key())
+	if cErr != nil {
+		return cErr
+	}
+	probe := struct {
+		ObjectType string `json:"docType"`
+	}{}
+	err := json.Unmarshal(b, &probe)
+	if err != nil {
+		return &chainError{r.fcn, a.key(), CODEGENEXCEPTION, err}
+	}
+	if probe.ObjectType != a.docType() {
+		return &chainError{r.fcn, a.key(), CODENOTFOUND, errors.New("Asset ID not found")}
+	}
+	err = json.Unmarshal(b, a)
+	if err != nil {
+		return &chainError{r.fcn, a.key(), CODEGENEXCEPTION, err}
+	}
+	return nil
+}
+
+// update - write state of an existing asset
+func (r *repository) update(a AidAssetInterface) *chainError {
+
+	c, cErr := r.exists(a)
+	if cErr != nil {
+		return cErr
+	}
+	if !c {
+		return &chainError{r.fcn, a.key(), CODENOTFOUND, errors.New("Asset ID not found")}
+	}
+	return r.save(a)
+}
+
+// save - write asset state whether the asset exists or not, asset is validated before it is written
+func (r *repository) save(a AidAssetInterface) *chainError {
+
+	cErr := a.validate()
+	if cErr != nil {
+		return &chainError{r.fcn, a.key(), cErr.code, cErr.err}
+	}
+	a.setVersion(schemaVersions[a.docType()])
+	b, err := json.Marshal(a)
+	if err != nil {
+		return &chainError{r.fcn, a.key(), CODEGENEXCEPTION, err}
+	}
+	err = r.stub.PutState(a.key(), b)
+	if err != nil {
+		return &chainError{r.fcn, a.key(), CODEGENEXCEPTION, err}
+	}
+
+	// Add indexkey for listing assets of the type
+	indexKey, err := r.stub.CreateCompositeKey(ASTIDX, []string{a.docType(), a.key()})
+	if err != nil {
+		return &chainError{r.fcn, a.key(), CODEGENEXCEPTION, err}
+	}
+	err = r.stub.PutState(indexKey, []byte{0x00})
+	if err != nil {
+		return &chainError{r.fcn, a.key(), CODEGENEXCEPTION, err}
+	}
+
+	// Add secondary index keys of the asset, if any
+	if ix, ok := a.(AidIndexedInterface); ok {
+		for _, attributes := range ix.indexKeys() {
+			indexKey, err := r.stub.CreateCompositeKey(attributes[0], attributes[1:])
+			if err != nil {
+				return &chainError{r.fcn, a.key(), CODEGENEXCEPTION, err}
+			}
+			err = r.stub.PutState(indexKey, []byte{0x00})
+			if err != nil {
+				return &chainError{r.fcn, a.key(), CODEGENEXCEPTION, err}
+			}
+		}
+	}
+	return nil
+}
+
+// delete - remove an existing asset along with its index key
+func (r *repository) delete(a AidAssetInterface) *chainError {
+
+	c, cErr := r.exists(a)
+	if cErr != nil {
+		return cErr
+	}
+	if !c {
+		return &chainError{r.fcn, a.key(), CODENOTFOUND, errors.New("Asset ID not found")}
+	}
+	err := r.stub.DelState(a.key())
+	if err != nil {
+		return &chainError{r.fcn, a.key(), CODEGENEXCEPTION, err}
+	}
+	indexKey, err := r.stub.CreateCompositeKey(ASTIDX, []string{a.docType(), a.key()})
+	if err != nil {
+		return &chainError{r.fcn, a.key(), CODEGENEXCEPTION, err}
+	}
+	err = r.stub.DelState(indexKey)
+	if err != nil {
+		return &chainError{r.fcn, a.key(), CODEGENEXCEPTION, err}
+	}
+	if ix, ok := a.(AidIndexedInterface); ok {
+		for _, attributes := range ix.indexKeys() {
+			indexKey, err := r.stub.CreateCompositeKey(attributes[0], attributes[1:])
+			if err != nil {
+				return &chainError{r.fcn, a.key(), CODEGENEXCEPTION, err}
+			}
+			err = r.stub.DelState(indexKey)
+			if err != nil {
+				return &chainError{r.fcn, a.key(), CODEGENEXCEPTION, err}
+			}
+		}
+	}
+	return nil
+}
+
+// list - read all assets of the doc type, newAsset returns an empty asset of the type to read into
+func (r *repository) list(docType string, newAsset func() AidAssetInterface) ([]AidAssetInterface, *chainError) {
+
+	astItr, err := r.stub.GetStateByPartialCompositeKey(ASTIDX, []string{docType})
+	if err != nil {
+		return nil, &chainError{r.fcn, docType, CODEGENEXCEPTION, err}
+	}
+	defer astItr.Close()
+
+	assets := []AidAssetInterface{}
+	for astItr.HasNext() {
+		kv, err := astItr.Next()
+		if err != nil {
+			return nil, &chainError{r.fcn, docType, CODEGENEXCEPTION, err}
+		}
+		_, compositeKeyParts, err := r.stub.SplitCompositeKey(kv.Key)
+		if err != nil {
+			return nil, &chainError{r.fcn, docType, CODEGENEXCEPTION, err}
+		}
+		b, cErr := queryAsset(r.stub, compositeKeyParts[1])
+		if cErr != nil {
+			return nil, cErr
+		}
+		a := newAsset()
+		err = json.Unmarshal(b, a)
+		if err != nil {
+			return nil, &chainError{r.fcn, compositeKeyParts[1], CODEGENEXCEPTION, err}
+		}
+		assets = append(assets, a)
+	}
+	return assets, nil
+}
+
+// checkIdentity - invariants common to all assets i.e. a non-empty key and the doc type of the asset type
+func checkIdentity(a AidAssetInterface, objectType string) *chainError {
+
+	if len(a.key()) == 0 {
+		return &chainError{"validate", "", CODEUNPROCESSABLEENTITY, errors.New("Asset key can not be empty")}
+	}
+	if objectType != a.docType() {
+		return &chainError{"validate", a.key(), CODEUNPROCESSABLEENTITY, errors.New("Asset doc type must be " + a.docType())}
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// Verifies scenarios related to the asset repository
+func TestRepository(t *testing.T) {
+	fmt.Println("Executing Test - Repository")
+
+	amount, _ := decimal.NewFromString("100")
+
+	// Test data - Refer to test narratives for test description. Operations are executed in order
+	var repoTable = []struct {
+		operation     func(r *repository) *chainError
+		expectedCode  string
+		testNarrative string
+	}{
+		{func(r *repository) *chainError {
+			return r.create(&item{ObjectType: GITEM, ItemID: "Itm001", Data: itemBase{ItemType: "Item001", Narrative: "Medicine"}})
+		}, "", "Happy scenario - create item"},
+		{func(r *repository) *chainError {
+			return r.create(&item{ObjectType: GITEM, ItemID: "Itm002", Data: itemBase{ItemType: "Item002", Narrative: "Food"}})
+		}, "", "Happy scenario - create another item"},
+		{func(r *repository) *chainError {
+			return r.create(&item{ObjectType: GITEM, ItemID: "Itm001"})
+		}, CODEAlRDEXIST, "Check for duplicate key"},
+		{func(r *repository) *chainError {
+			return r.create(&item{ObjectType: GITEM})
+		}, CODEUNPROCESSABLEENTITY, "Check for empty key"},
+		{func(r *repository) *chainError {
+			return r.create(&item{ObjectType: GPRJCT, ItemID: "Itm003"})
+		}, CODEUNPROCESSABLEENTITY, "Check for doc type of the asset type"},
+		{func(r *repository) *chainError {
+			return r.create(&spend{ObjectType: DONOUT, TxnID: "S101"})
+		}, CODEUNPROCESSABLEENTITY, "Check for asset specific invariants"},
+		{func(r *repository) *chainError {
+			return r.update(&item{ObjectType: GITEM, ItemID: "Itm001", Data: itemBase{ItemType: "Item001", Narrative: "Vaccines"}})
+		}, "", "Happy scenario - update item"},
+		{func(r *repository) *chainError {
+			return r.update(&item{ObjectType: GITEM, ItemID: "Itm009"})
+		}, CODENOTFOUND, "Check for update of a missing asset"},
+		{func(r *repository) *chainError {
+			return r.read(&project{ProjectID: "Itm001"})
+		}, CODENOTFOUND, "Check for read of an asset of another type"},
+		{func(r *repository) *chainError {
+			return r.create(&donation{ObjectType: DONIN, TxnID: "D101", Data: donationBase{Amount: amount}})
+		}, "", "Happy scenario - create donation"},
+		{func(r *repository) *chainError {
+			return r.delete(&item{ItemID: "Itm002"})
+		}, "", "Happy scenario - delete item"},
+		{func(r *repository) *chainError {
+			return r.delete(&item{ItemID: "Itm002"})
+		}, CODENOTFOUND, "Check for delete of a missing asset"},
+	}
+
+	assert := assert.New(t)
+
+	// Instantiate mockStub using AidChaincode as the target chaincode to unit test
+	stub := shim.NewMockStub("TestStub", new(AidChaincode))
+	assert.NotNil(stub, "Stub is nil, Test stub creation failed")
+
+	// Executing repository tests
+	for _, test := range repoTable {
+		stub.MockTransactionStart(uuid.New().String())
+		cErr := test.operation(newRepository(stub, "testRepository"))
+		stub.MockTransactionEnd("")
+		if len(test.expectedCode) == 0 {
+			assert.Nil(cErr, test.testNarrative+" failed")
+		} else if assert.NotNil(cErr, test.testNarrative+" failed - error expected") {
+			assert.Equal(test.expectedCode, cErr.code, test.testNarrative+" failed - error code mismatch")
+		}
+	}
+
+	// Verify typed read & listing by doc type
+	stub.MockTransactionStart(uuid.New().String())
+	defer stub.MockTransactionEnd("")
+	r := newRepository(stub, "testRepository")
+
+	it := &item{ItemID: "Itm001"}
+	cErr := r.read(it)
+	assert.Nil(cErr, "Repository failed to read the item")
+	assert.Equal("Vaccines", it.Data.Narrative, "Item narrative mismatch")
+
+	c, cErr := r.exists(&item{ItemID: "Itm002"})
+	assert.Nil(cErr, "Repository failed to check the item")
+	assert.False(c, "Deleted item still exists")
+
+	items, cErr := r.list(GITEM, func() AidAssetInterface { return &item{} })
+	assert.Nil(cErr, "Repository failed to list items")
+	if assert.Len(items, 1, "Item count mismatch") {
+		assert.Equal("Itm001", items[0].key(), "Listed item mismatch")
+	}
+	donations, cErr := r.list(DONIN, func() AidAssetInterface { return &donation{} })
+	assert.Nil(cErr, "Repository failed to list donations")
+	assert.Len(donations, 1, "Donation count mismatch")
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/vishal3152/AIDChaincode/aidcc/events"
+)
+
+// Asset model for spend reversal. All asset models are kept in
+// private scope i.e. they are not exported and ramin invisible to other packages
+// A reversal is a compensating entry for a spend recorded in error. The original spend
+// is never deleted, it is marked as reversed and linked to its reversal.
+type reversal struct {
+	ObjectType string       `json:"docType"` // reversal Type 'DONREV'
+	versioned               // schema version of the document
+	TxnID      string       `json:"txnID"`      // asset unique key
+	ReversalOf string       `json:"reversalOf"` // txnID of the reversed spend
+	Reason     string       `json:"reason"`
+	Data       donationBase `json:"data"` // composition
+}
+
+// key - ledger key of the spend reversal
+func (r *reversal) key() string {
+	return r.TxnID
+}
+
+// docType - doc type of spend reversal assets
+func (r *reversal) docType() string {
+	return DONREV
+}
+
+// validate - check spend reversal invariants before it is written
+func (r *reversal) validate() *chainError {
+	return checkIdentity(r, r.ObjectType)
+}
+
+// reversalKey - derive reversal key from the spend txnID. A spend can be reversed only once
+func reversalKey(spendTxnID string) string {
+	return spendTxnID + "_REV"
+}
+
+// Write reversal to ledger and mark the original spend as reversed
+func (r *reversal) putState(stub shim.ChaincodeStubInterface) pb.Response {
+
+	// check if the spend to be reversed exists
+	spendBytes, cErr := queryAsset(stub, r.ReversalOf)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	s := &spend{}
+	err := json.Unmarshal(spendBytes, s)
+	if err != nil {
+		cErr = &chainError{"putReversal", r.ReversalOf, CODEGENEXCEPTION, err}
+		return errorResponse(stub, cErr)
+	}
+	if s.ObjectType != DONOUT {
+		cErr = &chainError{"putReversal", r.ReversalOf, CODENOTALLWD, errors.New("Only spends can be reversed")}
+		return errorResponse(stub, cErr)
+	}
+	if len(s.ReversedBy) != 0 {
+		cErr = &chainError{"putReversal", r.ReversalOf, CODENOTALLWD, errors.New("Spend already reversed by " + s.ReversedBy)}
+		return errorResponse(stub, cErr)
+	}
+
+	// check if reversal txnID is unique
+	c, cErr := checkAsset(stub, r.TxnID)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	} else if c {
+		e := &chainError{"putReversal", r.TxnID, CODEAlRDEXIST, errors.New("Asset with key already exists")}
+		return errorResponse(stub, e)
+	}
+
+	// Reversal compensates the full spend amount on the same project & item
+	r.Data.ProjectID = s.Data.ProjectID
+	r.Data.ItemID = s.Data.ItemID
+	r.Data.Amount = s.Data.Amount
+	r.Data.Currency = s.Data.Currency
+
+	// Write reversal to ledger
+	cErr = newRepository(stub, "putReversal").save(r)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	// Link the original spend to its reversal
+	s.ReversedBy = r.TxnID
+	cErr = newRepository(stub, "putReversal").save(s)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	// Add indexkey for range query :- each reversal is stored in form of a delta and aggregated whenever
+	// project state is read
+	cErr = putDelta(stub, "putReversal", r.Data.ProjectID, "2", r.Data.Currency, r.TxnID, r.Data.Amount)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	// Reversed amount is released back to the spend's budget line
+	if len(s.Category) != 0 {
+		cErr = putCategorySpend(stub, "putReversal", r.Data.ProjectID, s.Category, r.TxnID, r.Data.Amount.Neg())
+		if cErr != nil {
+			return errorResponse(stub, cErr)
+		}
+	}
+
+	// Reversed amount is deducted from the vendor's totals
+	if len(s.VendorID) != 0 {
+		cErr = putVendorSpend(stub, "putReversal", s.VendorID, r.Data.ProjectID, r.Data.Currency, r.TxnID, r.Data.Amount.Neg())
+		if cErr != nil {
+			return errorResponse(stub, cErr)
+		}
+	}
+
+	// Emit transaction event for listeners
+	cErr = emitEvent(stub, "putReversal", r.TxnID, events.SpendReversed, &events.Reversal{TxnID: r.TxnID, ReversalOf: r.ReversalOf, ProjectID: r.Data.ProjectID, ItemID: r.Data.ItemID, Amount: r.Data.Amount, Currency: r.Data.Currency, Reason: r.Reason})
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	resp := response{CODEALLAOK, r.TxnID, nil}
+	return shim.Success((resp.formatResponse(stub)))
+}
+
+// Read reversal state from ledger
+func (r *reversal) getState(stub shim.ChaincodeStubInterface) pb.Response {
+
+	rev, cErr := queryAsset(stub, r.TxnID)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	resp := response{CODEALLAOK, "OK", rev}
+	return shim.Success((resp.formatResponse(stub)))
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// Verifies scenarios related to spend reversals
+func TestReversalRW(t *testing.T) {
+	fmt.Println("Executing Test - ReversalRW")
+
+	// Test data - Refer to test narratives for test description
+	var testTable = []struct {
+		txnID          string
+		reason         string
+		expectedStatus int32
+		testNarrative  string
+	}{
+		{"S101", "Mistyped amount", 200, "Happy scenario"},
+		{"", "Mistyped amount", 500, "Check for missing transaction ID"},
+		{"S102", "", 500, "Check for missing reason"},
+		{"S103", "Mistyped amount", 500, "Check for spend existence"},
+		{"D101", "Mistyped amount", 500, "Check for reversal of a donation"},
+		{"S101", "Mistyped amount", 500, "Check for duplicate reversal"},
+	}
+
+	// struct for parsing the shim APIs response
+	type sResp struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+		Payload spend  `json:"payload"`
+	}
+	type rResp struct {
+		Code    string   `json:"code"`
+		Message string   `json:"message"`
+		Payload reversal `json:"payload"`
+	}
+	type pResp struct {
+		Code    string  `json:"code"`
+		Message string  `json:"message"`
+		Payload project `json:"payload"`
+	}
+
+	assert := assert.New(t)
+
+	// Instantiate mockStub using AidChaincode as the target chaincode to unit test
+	stub := shim.NewMockStub("TestStub", new(AidChaincode))
+	assert.NotNil(stub, "Stub is nil, Test stub creation failed")
+
+	uid := uuid.New().String()
+
+	// Add test project, item, donation & spend to state.
+	result := stub.MockInvoke(uid,
+		[][]byte{[]byte(AddProject),
+			[]byte("P101"),
+			[]byte("Prj101")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Saving test project to state failed.")
+
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(AddItem),
+			[]byte("Itm001"),
+			[]byte("Item001"),
+			[]byte("Medicine")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Saving test item to state failed.")
+
+	donationAmount := "200"
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(AddDonation),
+			[]byte("D101"),
+			[]byte("vishal"),
+			[]byte("P101"),
+			[]byte("Itm001"),
+			[]byte(donationAmount)})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Saving test donation entry to state failed.")
+
+	// Refresh Available funds under Project
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(GetProject),
+			[]byte("P101")})
+	assert.EqualValues(shim.OK, result.GetStatus(), GetProject+" failed to read the project data")
+
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(AddSpend),
+			[]byte("S101"),
+			[]byte("vishal"),
+			[]byte("P101"),
+			[]byte("Itm001"),
+			[]byte("150")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Saving test spend entry to state failed.")
+
+	// Execute tests
+	for _, test := range testTable {
+		result := stub.MockInvoke(uid,
+			[][]byte{[]byte(ReverseSpend),
+				[]byte(test.txnID),
+				[]byte(test.reason)})
+
+		assert.Equal(test.expectedStatus, result.GetStatus(), test.testNarrative+" failed - "+(result.GetMessage()))
+
+		if result.GetStatus() == shim.OK {
+			// Verify reversal read, reversal must link to the original spend
+			result = stub.MockInvoke(uid,
+				[][]byte{[]byte(GetReversal),
+					[]byte(reversalKey(test.txnID))})
+
+			assert.EqualValues(shim.OK, result.GetStatus(), GetReversal+" failed to read the reversal data")
+			r := &rResp{}
+			err := json.Unmarshal(result.GetPayload(), r)
+			if err != nil {
+				panic(err)
+			}
+			assert.Equal(test.txnID, r.Payload.ReversalOf, "Reversal not linked to the original spend")
+			assert.Equal(test.reason, r.Payload.Reason, "Reterived reversal reason mismatch")
+
+			// Verify original spend is marked as reversed
+			result = stub.MockInvoke(uid,
+				[][]byte{[]byte(GetSpend),
+					[]byte(test.txnID)})
+
+			assert.EqualValues(shim.OK, result.GetStatus(), GetSpend+" failed to read the spend data")
+			s := &sResp{}
+			err = json.Unmarshal(result.GetPayload(), s)
+			if err != nil {
+				panic(err)
+			}
+			assert.Equal(reversalKey(test.txnID), s.Payload.ReversedBy, "Spend not marked as reversed")
+
+			// Verify - net effect of spend and its reversal on project funds is nil
+			result = stub.MockInvoke(uid,
+				[][]byte{[]byte(GetProject),
+					[]byte("P101")})
+
+			assert.EqualValues(shim.OK, result.GetStatus(), GetProject+" failed to read the project data")
+			p := &pResp{}
+			err = json.Unmarshal(result.GetPayload(), p)
+			if err != nil {
+				panic(err)
+			}
+			expectedFundVal, _ := decimal.NewFromString(donationAmount)
+			assert.True(expectedFundVal.Equal(p.Payload.Data.AvlFund), "Reversed amount fails to reflect under project available fund")
+			assert.True(p.Payload.Data.SpentFund.IsZero(), "Reversed amount fails to reflect under project spent fund")
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/vishal3152/AIDChaincode/aidcc/events"
+)
+
+// Asset model for blocked party. All asset models are kept in
+// private scope i.e. they are not exported and ramin invisible to other packages
+// The sanctions blocklist is maintained by compliance officers. Parties are listed by the SHA-256 hash
+// of their normalised identifier so that names of sanctioned parties never appear on the ledger.
+type blockedPartyBase struct {
+	Reason    string    `json:"reason"`
+	Active    bool      `json:"active"` // delisted parties are kept with active false
+	UpdatedBy string    `json:"updatedBy"`
+	UpdatedDt time.Time `json:"updatedDt"`
+}
+
+type blockedParty struct {
+	ObjectType string           `json:"docType"` // blocked party Type 'BLKPTY'
+	versioned                   // schema version of the document
+	PartyHash  string           `json:"partyHash"` // hex encoded SHA-256 of the party identifier
+	Data       blockedPartyBase `json:"data"`      // composition
+}
+
+// key - ledger key of the blocked party
+func (bp *blockedParty) key() string {
+	return blockedPartyKey(bp.PartyHash)
+}
+
+// docType - doc type of blocked party assets
+func (bp *blockedParty) docType() string {
+	return BLKPTY
+}
+
+// validate - check blocked party invariants before it is written
+func (bp *blockedParty) validate() *chainError {
+	return checkIdentity(bp, bp.ObjectType)
+}
+
+// screening - audit entry of a party screened against the blocklist by a donation or spend. A match
+// rejects the transaction, the peer then discards it along with its entries. Compliance records the entry
+// of a rejected transaction with RecordScreening, as a transaction of its own
+type screening struct {
+	TxnID     string    `json:"txnID"`
+	Party     string    `json:"party"` // donor, sponsor, beneficiary, vendor or vendorRegistration
+	PartyHash string    `json:"partyHash"`
+	Matched   bool      `json:"matched"`
+	TimeStamp time.Time `json:"timeStamp"`
+	TxID      string    `json:"txID,omitempty"` // ledger transaction of the screening, a rejected txnID may be retried
+}
+
+// screenedParties - parties of a transaction screened against the blocklist
+var screenedParties = map[string]bool{"donor": true, "sponsor": true, "beneficiary": true, "vendor": true, "vendorRegistration": true}
+
+// blockedPartyKey - derive blocklist key from the party hash
+func blockedPartyKey(partyHash string) string {
+	return partyHash + "_BLK"
+}
+
+// partyHash - hash of a party identifier, normalised to trimmed lower case
+func partyHash(partyID string) string {
+	h := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(partyID))))
+	return hex.EncodeToString(h[:])
+}
+
+// Write blocked party to ledger, a delisted party may be listed again
+func (bp *blockedParty) putState(stub shim.ChaincodeStubInterface) pb.Response {
+
+	existing := &blockedParty{PartyHash: bp.PartyHash}
+	cErr := existing.load(stub, "putBlockedParty")
+	if cErr == nil && existing.Data.Active {
+		cErr = &chainError{"putBlockedParty", bp.PartyHash, CODEAlRDEXIST, errors.New("Party already blocked")}
+		return errorResponse(stub, cErr)
+	} else if cErr != nil && cErr.code != CODENOTFOUND {
+		return errorResponse(stub, cErr)
+	}
+
+	cErr = bp.store(stub, "putBlockedParty")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	// Emit transaction event for listeners
+	cErr = emitEvent(stub, "putBlockedParty", bp.PartyHash, events.PartyBlocked, &events.BlockedParty{PartyHash: bp.PartyHash, Reason: bp.Data.Reason, Active: bp.Data.Active, UpdatedBy: bp.Data.UpdatedBy})
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	r := response{CODEALLAOK, bp.PartyHash, nil}
+	return shim.Success((r.formatResponse(stub)))
+}
+
+// Read blocked party state from the ledger
+func (bp *blockedParty) getState(stub shim.ChaincodeStubInterface) pb.Response {
+
+	blk, cErr := queryAsset(stub, blockedPartyKey(bp.PartyHash))
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	r := response{CODEALLAOK, "OK", blk}
+	return shim.Success((r.formatResponse(stub)))
+}
+
+// delistState - remove a party from the blocklist, the entry is kept for audit
+func (bp *blockedParty) delistState(stub shim.ChaincodeStubInterface, callerID string, timeStamp time.Time) pb.Response {
+
+	cErr := bp.load(stub, "delistBlockedParty")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	if !bp.Data.Active {
+		cErr = &chainError{"delistBlockedParty", bp.PartyHash, CODENOTALLWD, errors.New("Party already delisted")}
+		return errorResponse(stub, cErr)
+	}
+
+	bp.Data.Active = false
+	bp.Data.UpdatedBy = callerID
+	bp.Data.UpdatedDt = timeStamp
+	cErr = bp.store(stub, "delistBlockedParty")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	// Emit transaction event for listeners
+	cErr = emitEvent(stub, "delistBlockedParty", bp.PartyHash, events.PartyDelisted, &events.BlockedParty{PartyHash: bp.PartyHash, Reason: bp.Data.Reason, Active: bp.Data.Active, UpdatedBy: bp.Data.UpdatedBy})
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	r := response{CODEALLAOK, bp.PartyHash, nil}
+	return shim.Success((r.formatResponse(stub)))
+}
+
+// screenParty - screen a party of the transaction against the blocklist and record the screening.
+// Parties on the blocklist are rejected with CODESANCTIONED
+func screenParty(stub shim.ChaincodeStubInterface, fcn string, txnID string, party string, partyID string) *chainError {
+
+	sc, cErr := screen(stub, fcn, txnID, party, partyID)
+	if cErr != nil {
+		return cErr
+	}
+	if sc.Matched {
+		return &chainError{fcn, txnID, CODESANCTIONED, errors.New("Transaction " + party + " is on the sanctions blocklist")}
+	}
+	return nil
+}
+
+// screen - screen a party of the transaction against the blocklist & write the screening under SCRIDX
+func screen(stub shim.ChaincodeStubInterface, fcn string, txnID string, party string, partyID string) (*screening, *chainError) {
+
+	epochTime, _ := stub.GetTxTimestamp()
+	timeStamp := time.Unix(epochTime.GetSeconds(), 0)
+
+	bp := &blockedParty{PartyHash: partyHash(partyID)}
+	cErr := bp.load(stub, fcn)
+	if cErr != nil && cErr.code != CODENOTFOUND {
+		return nil, cErr
+	}
+	sc := &screening{TxnID: txnID, Party: party, PartyHash: bp.PartyHash, Matched: cErr == nil && bp.Data.Active, TimeStamp: timeStamp, TxID: stub.GetTxID()}
+
+	indexKey, err := stub.CreateCompositeKey(SCRIDX, []string{txnID, party, sc.TxID})
+	if err != nil {
+		return nil, &chainError{fcn, txnID, CODEGENEXCEPTION, err}
+	}
+	b, err := json.Marshal(sc)
+	if err != nil {
+		return nil, &chainError{fcn, txnID, CODEGENEXCEPTION, err}
+	}
+	err = stub.PutState(indexKey, b)
+	if err != nil {
+		return nil, &chainError{fcn, txnID, CODEGENEXCEPTION, err}
+	}
+	return sc, nil
+}
+
+// recordScreeningState - record the screening of a party for a transaction rejected by sanctions screening,
+// the rejected transaction itself is never committed. The party is screened again, hence the entry can not
+// differ from the blocklist
+func recordScreeningState(stub shim.ChaincodeStubInterface, txnID string, party string, partyID string) pb.Response {
+
+	sc, cErr := screen(stub, "recordScreening", txnID, party, partyID)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	// Emit transaction event for listeners
+	cErr = emitEvent(stub, "recordScreening", txnID, events.ScreeningRecorded, &events.Screening{TxnID: sc.TxnID, Party: sc.Party, PartyHash: sc.PartyHash, Matched: sc.Matched})
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	b, err := json.Marshal(sc)
+	if err != nil {
+		cErr = &chainError{"recordScreening", txnID, CODEGENEXCEPTION, err}
+		return errorResponse(stub, cErr)
+	}
+	r := response{CODEALLAOK, txnID, b}
+	return shim.Success((r.formatResponse(stub)))
+}
+
+// screeningState - read screenings recorded for a transaction, including those recorded by compliance
+func screeningState(stub shim.ChaincodeStubInterface, txnID string) pb.Response {
+
+	screenings, cErr := readScreenings(stub, txnID)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	if len(screenings) == 0 {
+		cErr := &chainError{"readScreenings", txnID, CODENOTFOUND, errors.New("No screening recorded for the transaction")}
+		return errorResponse(stub, cErr)
+	}
+
+	b, err := json.Marshal(screenings)
+	if err != nil {
+		cErr := &chainError{"readScreenings", txnID, CODEGENEXCEPTION, err}
+		return errorResponse(stub, cErr)
+	}
+	r := response{CODEALLAOK, txnID, b}
+	return shim.Success((r.formatResponse(stub)))
+}
+
+// load - read blocked party from the ledger into the receiver
+func (bp *blockedParty) load(stub shim.ChaincodeStubInterface, fcn string) *chainError {
+	return newRepository(stub, fcn).read(bp)
+}
+
+// store - write blocked party state to the ledger
+func (bp *blockedParty) store(stub shim.ChaincodeStubInterface, fcn string) *chainError {
+	return newRepository(stub, fcn).save(bp)
+}
+
+// readScreenings - screenings of a transaction, in ledger key order
+func readScreenings(stub shim.ChaincodeStubInterface, txnID string) ([]screening, *chainError) {
+
+	screenings := []screening{}
+	scrItr, err := stub.GetStateByPartialCompositeKey(SCRIDX, []string{txnID})
+	if err != nil {
+		return screenings, &chainError{"readScreenings", txnID, CODEGENEXCEPTION, err}
+	}
+	//Close itrerator when done reading
+	defer scrItr.Close()
+	for scrItr.HasNext() {
+		rangeItem, err := scrItr.Next()
+		if err != nil {
+			return screenings, &chainError{"readScreenings", txnID, CODEGENEXCEPTION, err}
+		}
+		sc := screening{}
+		err = json.Unmarshal(rangeItem.Value, &sc)
+		if err != nil {
+			return screenings, &chainError{"readScreenings", txnID, CODEGENEXCEPTION, err}
+		}
+		screenings = append(screenings, sc)
+	}
+	return screenings, nil
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"container/list"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+// commitInvoke - invoke the chaincode as a ledger transaction, writes of a failed invoke are discarded like
+// the peer does, which the mock stub does not
+func commitInvoke(stub *shim.MockStub, uid string, args [][]byte) pb.Response {
+	state := make(map[string][]byte, len(stub.State))
+	for k, v := range stub.State {
+		state[k] = v
+	}
+	keys := list.New()
+	keys.PushBackList(stub.Keys)
+
+	result := stub.MockInvoke(uid, args)
+	if result.GetStatus() != shim.OK {
+		stub.State, stub.Keys = state, keys
+	}
+	return result
+}
+
+// Verifies scenarios related to sanctions blocklist and screening of donors, beneficiaries & vendors
+func TestScreeningRW(t *testing.T) {
+	fmt.Println("Executing Test - ScreeningRW")
+	defer os.Unsetenv("ROLE")
+
+	// Test data - Refer to test narratives for test description
+	var blocklistTable = []struct {
+		role           string
+		partyHash      string
+		reason         string
+		expectedStatus int32
+		testNarrative  string
+	}{
+		{ROLECMPL, partyHash("Blocked Donor"), "Sanctions list 2020/12", 200, "Happy scenario"},
+		{ROLECMPL, partyHash("blocked beneficiary"), "Sanctions list 2020/12", 200, "Happy scenario"},
+		{ROLECMPL, partyHash("Supplier VN102"), "Debarred supplier", 200, "Happy scenario"},
+		{ROLECMPL, partyHash("REG-VN103"), "Debarred supplier", 200, "Happy scenario"},
+		{ROLECMPL, partyHash("Blocked Sponsor"), "Sanctions list 2020/12", 200, "Happy scenario"},
+		{ROLECMPL, partyHash("Blocked Subscriber"), "Sanctions list 2020/12", 200, "Happy scenario"},
+		{"donor", partyHash("someone"), "Sanctions list 2020/12", 500, "Check for caller authorized as compliance officer"},
+		{ROLECMPL, "abc123", "Sanctions list 2020/12", 500, "Check for SHA-256 party hash"},
+		{ROLECMPL, partyHash("someone"), "", 500, "Check for missing listing reason"},
+		{ROLECMPL, partyHash("Blocked Donor"), "Sanctions list 2021/01", 500, "Check for party already blocked"},
+	}
+	// Transactions are executed in order. Rejected transactions are discarded along with their screenings,
+	// compliance records the screening of the rejected party with RecordScreening
+	var screeningTable = []struct {
+		function       string
+		txnID          string
+		party          string
+		vendorID       string // party screened, on RecordScreening
+		expectedStatus int32
+		expectedCode   string
+		testNarrative  string
+	}{
+		{AddDonation, "D102", " BLOCKED DONOR ", "", 500, CODESANCTIONED, "Check for sanctioned donor regardless of case"},
+		{RecordScreening, "D102", "Blocked Donor", "donor", 200, CODEALLAOK, "Happy scenario - screening of a rejected donation recorded"},
+		{AddSpend, "S102", "Blocked Beneficiary", "", 500, CODESANCTIONED, "Check for sanctioned beneficiary"},
+		{RecordScreening, "S102", "Blocked Beneficiary", "beneficiary", 200, CODEALLAOK, "Happy scenario - screening of a rejected spend recorded"},
+		{AddSpend, "S103", "vishal", "VN102", 500, CODESANCTIONED, "Check for vendor sanctioned by legal name"},
+		{RecordScreening, "S103", "Supplier VN102", "vendor", 200, CODEALLAOK, "Happy scenario - screening of a rejected spend recorded"},
+		{AddSpend, "S104", "vishal", "VN103", 500, CODESANCTIONED, "Check for vendor sanctioned by registration"},
+		{RecordScreening, "S104", "REG-VN103", "vendorRegistration", 200, CODEALLAOK, "Happy scenario - screening of a rejected spend recorded"},
+		{AddSpend, "S101", "vishal", "VN101", 200, CODEALLAOK, "Happy scenario"},
+		{IssueVoucher, "V101", "Blocked Beneficiary", "", 200, CODEALLAOK, "Happy scenario - voucher issued"},
+		{RedeemVoucher, "V101", "", "", 500, CODESANCTIONED, "Check for sanctioned beneficiary of a voucher"},
+		{RecordScreening, redemptionKey("V101"), "Blocked Beneficiary", "beneficiary", 200, CODEALLAOK, "Happy scenario - screening of a rejected redemption recorded"},
+		{AddMatchingCampaign, "C101", "Blocked Sponsor", "", 200, CODEALLAOK, "Happy scenario - campaign of a sanctioned sponsor"},
+		{AddDonation, "D103", "vishal", "", 200, CODEALLAOK, "Happy scenario - donation not matched by a sanctioned sponsor"},
+		{AddRecurringDonation, "R101", "Blocked Subscriber", "", 200, CODEALLAOK, "Happy scenario - schedule of a sanctioned donor"},
+		{RecordInstalment, "I101", "R101", "", 500, CODESANCTIONED, "Check for sanctioned donor of an instalment"},
+		{RecordScreening, "I101", "Blocked Subscriber", "donor", 200, CODEALLAOK, "Happy scenario - screening of a rejected instalment recorded"},
+		{RecordScreening, "I102", "Blocked Subscriber", "subscriber", 500, CODEUNPROCESSABLEENTITY, "Check for unknown party of a screening"},
+		{RecordScreening, "", "Blocked Subscriber", "donor", 500, CODEUNPROCESSABLEENTITY, "Check for missing txn ID of a screening"},
+		{RemoveBlockedParty, "", "Blocked Donor", "", 200, CODEALLAOK, "Delisting of a party"},
+		{AddDonation, "D102", "Blocked Donor", "", 200, CODEALLAOK, "Donation by a delisted party"},
+	}
+	// Screenings kept per transaction, including those recorded by compliance for rejected transactions
+	var auditTable = []struct {
+		txnID           string
+		expectedParties []string
+		expectedMatches []string
+	}{
+		{"S101", []string{"beneficiary", "vendor", "vendorRegistration"}, []string{}},
+		{"S102", []string{"beneficiary"}, []string{"beneficiary"}},
+		{"S103", []string{"vendor"}, []string{"vendor"}},
+		{"S104", []string{"vendorRegistration"}, []string{"vendorRegistration"}},
+		{redemptionKey("V101"), []string{"beneficiary"}, []string{"beneficiary"}},
+		{matchKey("D103", "C101"), []string{"sponsor"}, []string{"sponsor"}},
+		{"D102", []string{"donor", "donor"}, []string{"donor"}},
+		{"I101", []string{"donor"}, []string{"donor"}},
+	}
+
+	// struct for parsing the shim APIs response
+	type eResp struct {
+		Code string `json:"code"`
+	}
+	type sResp struct {
+		Code    string      `json:"code"`
+		Message string      `json:"message"`
+		Payload []screening `json:"payload"`
+	}
+	assert := assert.New(t)
+
+	// Instantiate mockStub using AidChaincode as the target chaincode to unit test
+	stub := shim.NewMockStub("TestStub", new(AidChaincode))
+	assert.NotNil(stub, "Stub is nil, Test stub creation failed")
+
+	uid := uuid.New().String()
+
+	// Add test project, item, vendors & donation to state.
+	result := stub.MockInvoke(uid,
+		[][]byte{[]byte(AddProject),
+			[]byte("P101"),
+			[]byte("Prj101")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Saving test project to state failed.")
+
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(AddItem),
+			[]byte("Itm001"),
+			[]byte("Item001"),
+			[]byte("Medicine")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Saving test item to state failed.")
+
+	for _, vendorID := range []string{"VN101", "VN102", "VN103"} {
+		result = stub.MockInvoke(uid,
+			[][]byte{[]byte(AddVendor),
+				[]byte(vendorID),
+				[]byte("Supplier " + vendorID),
+				[]byte("REG-" + vendorID),
+				[]byte(partyHash("bank " + vendorID))})
+		assert.EqualValues(shim.OK, result.GetStatus(), "Saving test vendor to state failed.")
+	}
+
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(AddDonation),
+			[]byte("D101"),
+			[]byte("vishal"),
+			[]byte("P101"),
+			[]byte("Itm001"),
+			[]byte("500")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Saving test donation entry to state failed.")
+
+	// Refresh Available funds under Project
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(GetProject),
+			[]byte("P101")})
+	assert.EqualValues(shim.OK, result.GetStatus(), GetProject+" failed to read the project data")
+
+	// Executing blocklist tests
+	for _, test := range blocklistTable {
+		os.Setenv("ROLE", test.role)
+		result := stub.MockInvoke(uid,
+			[][]byte{[]byte(AddBlockedParty),
+				[]byte(test.partyHash),
+				[]byte(test.reason)})
+
+		assert.Equal(test.expectedStatus, result.GetStatus(), test.testNarrative+" failed - "+(result.GetMessage()))
+	}
+
+	// Executing screening tests, each transaction is a distinct ledger transaction
+	future := time.Now().UTC().AddDate(0, 0, 30).Format(DATEFMT)
+	for _, test := range screeningTable {
+		var args [][]byte
+		role := ROLECMPL
+		switch test.function {
+		case AddDonation:
+			args = [][]byte{[]byte(test.function), []byte(test.txnID), []byte(test.party), []byte("P101"), []byte("Itm001"), []byte("100")}
+		case AddSpend:
+			args = [][]byte{[]byte(test.function), []byte(test.txnID), []byte(test.party), []byte("P101"), []byte("Itm001"), []byte("50"), []byte(""), []byte(""), []byte(test.vendorID)}
+		case IssueVoucher:
+			role = ROLEISSUER
+			args = [][]byte{[]byte(test.function), []byte(test.txnID), []byte(test.party), []byte("P101"), []byte("Itm001"), []byte("50"), []byte(future)}
+		case RedeemVoucher:
+			role = ROLEVENDOR
+			args = [][]byte{[]byte(test.function), []byte(test.txnID)}
+		case AddMatchingCampaign:
+			args = [][]byte{[]byte(test.function), []byte(test.txnID), []byte(test.party), []byte("P101"), []byte("1"), []byte("1000"), []byte("2020-01-01"), []byte(future)}
+		case AddRecurringDonation:
+			args = [][]byte{[]byte(test.function), []byte(test.txnID), []byte(test.party), []byte("P101"), []byte("Itm001"), []byte("100"), []byte(MONTHLY), []byte("2020-01-01"), []byte(future)}
+		case RecordInstalment:
+			args = [][]byte{[]byte(test.function), []byte(test.txnID), []byte(test.party), []byte("100")}
+		case RecordScreening:
+			args = [][]byte{[]byte(test.function), []byte(test.txnID), []byte(test.vendorID), []byte(test.party)}
+		case RemoveBlockedParty:
+			args = [][]byte{[]byte(test.function), []byte(partyHash(test.party))}
+		}
+		os.Setenv("ROLE", role)
+		result := commitInvoke(stub, uuid.New().String(), args)
+
+		assert.Equal(test.expectedStatus, result.GetStatus(), test.testNarrative+" failed - "+(result.GetMessage()))
+		e := &eResp{}
+		err := json.Unmarshal(result.GetPayload(), e)
+		if err != nil {
+			panic(err)
+		}
+		assert.Equal(test.expectedCode, e.Code, test.testNarrative+" failed - response code mismatch")
+	}
+
+	// Screenings are recorded by compliance only
+	os.Setenv("ROLE", "donor")
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(RecordScreening),
+			[]byte("I101"),
+			[]byte("donor"),
+			[]byte("Blocked Subscriber")})
+	assert.EqualValues(shim.ERROR, result.GetStatus(), "Check for caller authorized as compliance officer failed")
+
+	// Verify nothing is committed by rejected transactions
+	os.Unsetenv("ROLE")
+	for _, q := range [][]string{{GetSpend, "S102"}, {GetSpend, redemptionKey("V101")}, {GetDonation, matchKey("D103", "C101")}, {GetDonation, "I101"}} {
+		result = stub.MockInvoke(uid, [][]byte{[]byte(q[0]), []byte(q[1])})
+		assert.EqualValues(shim.ERROR, result.GetStatus(), q[1]+" written by a rejected transaction")
+	}
+	result = stub.MockInvoke(uid, [][]byte{[]byte(GetVoucher), []byte("V101")})
+	assert.Contains(string(result.GetPayload()), VCHISSD, "Voucher of a sanctioned beneficiary redeemed")
+	result = stub.MockInvoke(uid, [][]byte{[]byte(GetRecurringDonation), []byte("R101")})
+	assert.NotContains(string(result.GetPayload()), "I101", "Instalment of a sanctioned donor taken")
+
+	// Verify screenings recorded per transaction are committed, matched or not
+	for _, test := range auditTable {
+		result = stub.MockInvoke(uid,
+			[][]byte{[]byte(GetScreenings),
+				[]byte(test.txnID)})
+		assert.EqualValues(shim.OK, result.GetStatus(), GetScreenings+" failed to read the screenings of "+test.txnID)
+
+		s := &sResp{}
+		err := json.Unmarshal(result.GetPayload(), s)
+		if err != nil {
+			panic(err)
+		}
+		parties, matches := []string{}, []string{}
+		for _, sc := range s.Payload {
+			parties = append(parties, sc.Party)
+			if sc.Matched {
+				matches = append(matches, sc.Party)
+			}
+		}
+		assert.ElementsMatch(test.expectedParties, parties, test.txnID+" screenings mismatch")
+		assert.ElementsMatch(test.expectedMatches, matches, test.txnID+" matched screenings mismatch")
+	}
+}
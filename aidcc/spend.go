@@ -1,21 +1,55 @@
 package main
 
 import (
-	"encoding/json"
 	"errors"
 
 	"github.com/hyperledger/fabric/core/chaincode/shim"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/shopspring/decimal"
+	"github.com/vishal3152/AIDChaincode/aidcc/events"
 )
 
 // Asset model for spend. All asset models are kept in
 // private scope i.e. they are not exported and ramin invisible to other packages
 type spend struct {
 	ObjectType string       `json:"docType"` // donation Type 'DONOUT'
-	TxnID      string       `json:"txnID"`   //asset unique key
+	versioned               // schema version of the document
+	TxnID      string       `json:"txnID"` //asset unique key
 	Benficiary string       `json:"donor"`
-	Data       donationBase `json:"data"` // composition
+	ReversedBy string       `json:"reversedBy,omitempty"` // txnID of the reversal, if any
+	Category   string       `json:"category,omitempty"`   // budget line of the spend
+	Evidence   []evidence   `json:"evidence,omitempty"`   // proof of delivery documents
+	VoucherID  string       `json:"voucherID,omitempty"`  // voucher redeemed by the spend, if any
+	VendorID   string       `json:"vendorID,omitempty"`   // vendor paid by the spend, if any
+	Data       donationBase `json:"data"`                 // composition
+}
+
+// key - ledger key of the spend
+func (s *spend) key() string {
+	return s.TxnID
+}
+
+// docType - doc type of spend assets
+func (s *spend) docType() string {
+	return DONOUT
+}
+
+// validate - check spend invariants before it is written
+func (s *spend) validate() *chainError {
+
+	cErr := checkIdentity(s, s.ObjectType)
+	if cErr != nil {
+		return cErr
+	}
+	if s.Data.Amount.Sign() <= 0 {
+		return &chainError{"validate", s.key(), CODEUNPROCESSABLEENTITY, errors.New("Amount must be positive")}
+	}
+	return nil
+}
+
+// indexKeys - spends are listed by project
+func (s *spend) indexKeys() [][]string {
+	return [][]string{{SPDIDX, s.Data.ProjectID, s.TxnID}}
 }
 
 // Write asset state to ledger
@@ -24,81 +58,162 @@ func (s *spend) putState(stub shim.ChaincodeStubInterface) pb.Response {
 	// check if affiliated project exists
 	c, cErr := checkAsset(stub, s.Data.ProjectID)
 	if cErr != nil {
-		return shim.Error(cErr.Error())
+		return errorResponse(stub, cErr)
 	} else if !c {
 		e := &chainError{"putSpend", s.TxnID, CODENOTFOUND, errors.New("Affiliated project not found")}
-		return shim.Error(e.Error())
+		return errorResponse(stub, e)
+	}
+
+	// resolve spend currency, defaults to project's base currency
+	s.Data.Currency, s.Data.Amount, cErr = resolveCurrency(stub, "putSpend", s.TxnID, s.Data.ProjectID, s.Data.Currency, s.Data.Amount)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
 	}
 
 	// check if affiliated item exists
 	c, cErr = checkAsset(stub, s.Data.ItemID)
 	if cErr != nil {
-		return shim.Error(cErr.Error())
+		return errorResponse(stub, cErr)
 	} else if !c {
 		e := &chainError{"putSpend", s.TxnID, CODENOTFOUND, errors.New("Affiliated item not found")}
-		return shim.Error(e.Error())
+		return errorResponse(stub, e)
 	}
 
 	// check if txnID is unique
 	c, cErr = checkAsset(stub, s.TxnID)
 	if cErr != nil {
-		return shim.Error(cErr.Error())
+		return errorResponse(stub, cErr)
 	} else if c {
 		e := &chainError{"putSpend", s.TxnID, CODEAlRDEXIST, errors.New("Asset with key already exists")}
-		return shim.Error(e.Error())
+		return errorResponse(stub, e)
 	}
 
-	// check if project has funds available before making a spend
-	prj, err := stub.GetState(s.Data.ProjectID)
-	if err != nil {
-		cErr = &chainError{"putSpend", s.TxnID, CODEGENEXCEPTION, err}
-		return shim.Error(cErr.Error())
+	p := &project{ProjectID: s.Data.ProjectID}
+	cErr = p.load(stub, "putSpend")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
 	}
-	p := &project{}
-	err = json.Unmarshal(prj, p)
-	if err != nil {
-		cErr = &chainError{"putSpend", s.TxnID, CODEGENEXCEPTION, err}
-		return shim.Error(cErr.Error())
+	zeroDecimal, _ := decimal.NewFromString("0")
+	cErr = s.checkSpend(stub, "putSpend", p, zeroDecimal)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	cErr = s.record(stub, "putSpend")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	r := response{CODEALLAOK, s.TxnID, nil}
+	return shim.Success((r.formatResponse(stub)))
+}
+
+// checkSpend - check the spend against parties, funds & budgets of the project before it is recorded. Held
+// is the part of the amount already reserved for the spend i.e. by a voucher being redeemed
+func (s *spend) checkSpend(stub shim.ChaincodeStubInterface, fcn string, p *project, held decimal.Decimal) *chainError {
+
+	// check if beneficiary & payee vendor are not sanctioned parties
+	cErr := screenParty(stub, fcn, s.TxnID, "beneficiary", s.Benficiary)
+	if cErr != nil {
+		return cErr
+	}
+
+	// check if payee vendor is registered & not suspended, vendor is screened by its legal name & registration
+	if len(s.VendorID) != 0 {
+		vd, cErr := checkPayee(stub, fcn, s.VendorID)
+		if cErr != nil {
+			return cErr
+		}
+		cErr = screenParty(stub, fcn, s.TxnID, "vendor", vd.Data.LegalName)
+		if cErr != nil {
+			return cErr
+		}
+		cErr = screenParty(stub, fcn, s.TxnID, "vendorRegistration", vd.Data.RegistrationID)
+		if cErr != nil {
+			return cErr
+		}
+	}
+
+	// check if project has funds available in spend currency before making a spend. Funds held in
+	// another currency can not be spent i.e. mixed currency spends are not allowed
+	bal, ok := p.Data.Balances[s.Data.Currency]
+	if !ok {
+		return &chainError{fcn, s.TxnID, CODENOTALLWD, errors.New("Project holds no funds in " + s.Data.Currency + ", mixed currency spend not allowed")}
 	}
 	zeroDecimal, _ := decimal.NewFromString("0")
-	if p.Data.AvlFund.Sub(s.Data.Amount).LessThanOrEqual(zeroDecimal) {
-		cErr = &chainError{"putSpend", s.TxnID, CODENOTALLWD, errors.New("Overwithdrawal for funds not allowed")}
-		return shim.Error(cErr.Error())
+	if held.IsZero() && bal.AvlFund.Sub(s.Data.Amount).LessThanOrEqual(zeroDecimal) {
+		return &chainError{fcn, s.TxnID, CODENOTALLWD, errors.New("Overwithdrawal for funds not allowed")}
 	}
 
-	// Convert spend struct to []byte
-	b, err := json.Marshal(s)
-	if err != nil {
-		cErr = &chainError{"putSpend", s.TxnID, CODEGENEXCEPTION, err}
-		return shim.Error(cErr.Error())
+	cErr = checkMilestoneCap(stub, fcn, s.TxnID, p, s.Data.Currency, s.Data.Amount, held)
+	if cErr != nil {
+		return cErr
 	}
+
+	// check if spend stays within its budget line, when project defines a budget
+	return checkBudget(stub, fcn, p, s, held)
+}
+
+// record - write the spend to the ledger along with its delta & index keys
+func (s *spend) record(stub shim.ChaincodeStubInterface, fcn string) *chainError {
+
 	// Write spend to ledger
-	err = stub.PutState(s.TxnID, b)
-	if err != nil {
-		cErr = &chainError{"putSpend", s.TxnID, CODEGENEXCEPTION, err}
-		return shim.Error(cErr.Error())
+	cErr := newRepository(stub, fcn).save(s)
+	if cErr != nil {
+		return cErr
 	}
 
 	// Add indexkey for range query :- each spend is stored in form of a delta and aggregated whenever
 	// project state is read
-	indexName := string(INDXNM)
-	indexKey, err := stub.CreateCompositeKey(indexName, []string{"0", s.TxnID, s.Data.Amount.StringFixedBank(int32(FIXEDPT))})
-	if err != nil {
-		cErr = &chainError{"putSpend", s.TxnID, CODEGENEXCEPTION, err}
-		return shim.Error(cErr.Error())
+	cErr = putDelta(stub, fcn, s.Data.ProjectID, "0", s.Data.Currency, s.TxnID, s.Data.Amount)
+	if cErr != nil {
+		return cErr
+	}
+
+	if len(s.Category) != 0 {
+		cErr = putCategorySpend(stub, fcn, s.Data.ProjectID, s.Category, s.TxnID, s.Data.Amount)
+		if cErr != nil {
+			return cErr
+		}
 	}
-	value := []byte{0x00}
 
-	err = stub.PutState(indexKey, value)
-	if err != nil {
-		cErr = &chainError{"putSpend", s.TxnID, CODEGENEXCEPTION, err}
-		return shim.Error(cErr.Error())
+	if len(s.VendorID) != 0 {
+		cErr = putVendorSpend(stub, fcn, s.VendorID, s.Data.ProjectID, s.Data.Currency, s.TxnID, s.Data.Amount)
+		if cErr != nil {
+			return cErr
+		}
 	}
 
 	// Emit transaction event for listeners
-	stub.SetEvent((s.TxnID + "_AID_SPND_" + s.Data.Amount.StringFixed(FIXEDPT)), nil)
-	r := response{CODEALLAOK, s.TxnID, nil}
-	return shim.Success((r.formatResponse()))
+	return emitEvent(stub, fcn, s.TxnID, events.SpendRecorded, &events.Spend{TxnID: s.TxnID, ProjectID: s.Data.ProjectID, ItemID: s.Data.ItemID, Beneficiary: s.Benficiary,
+		Amount: s.Data.Amount, Currency: s.Data.Currency, Category: s.Category, VendorID: s.VendorID, VoucherID: s.VoucherID})
+}
+
+// checkMilestoneCap - check if cumulative spends & voucher reservations stay within approved milestone budgets,
+// when project defines milestones. Deltas not yet aggregated under project are accounted for, held is the part
+// of the amount already reserved. Budgets are in base currency
+func checkMilestoneCap(stub shim.ChaincodeStubInterface, fcn string, txnID string, p *project, currency string, amount decimal.Decimal, held decimal.Decimal) *chainError {
+
+	budget, capped := p.approvedBudget()
+	if !capped {
+		return nil
+	}
+	if currency != p.baseCurrency() {
+		return &chainError{fcn, txnID, CODENOTALLWD, errors.New("Spend under milestone budgets must be in base currency " + p.baseCurrency())}
+	}
+	pending := make(map[string]map[string]decimal.Decimal)
+	for _, bitmask := range []string{"0", "2", "5", "6"} {
+		aggregate, cErr := aggregateDeltas(stub, p.ProjectID, bitmask, false)
+		if cErr != nil {
+			return cErr
+		}
+		pending[bitmask] = aggregate
+	}
+	bal := p.Data.Balances[currency]
+	spent := bal.SpentFund.Add(pending["0"][currency]).Sub(pending["2"][currency])
+	reserved := bal.ReservedFund.Add(pending["5"][currency]).Sub(pending["6"][currency])
+	if spent.Add(reserved).Add(amount).Sub(held).GreaterThan(budget) {
+		return &chainError{fcn, txnID, CODENOTALLWD, errors.New("Spend exceeds approved milestone budgets of " + budget.String())}
+	}
+	return nil
 }
 
 // Read spend state from ledger
@@ -106,8 +221,18 @@ func (s *spend) getState(stub shim.ChaincodeStubInterface) pb.Response {
 
 	spend, cErr := queryAsset(stub, s.TxnID)
 	if cErr != nil {
-		return shim.Error(cErr.Error())
+		return errorResponse(stub, cErr)
 	}
 	r := response{CODEALLAOK, "OK", spend}
-	return shim.Success((r.formatResponse()))
+	return shim.Success((r.formatResponse(stub)))
+}
+
+// load - read spend from the ledger into the receiver
+func (s *spend) load(stub shim.ChaincodeStubInterface, fcn string) *chainError {
+	return newRepository(stub, fcn).read(s)
+}
+
+// store - write spend state back to the ledger
+func (s *spend) store(stub shim.ChaincodeStubInterface, fcn string) *chainError {
+	return newRepository(stub, fcn).save(s)
 }
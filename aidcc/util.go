@@ -1,40 +1,121 @@
 package main
 
 import (
-	"bytes"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/hyperledger/fabric/core/chaincode/lib/cid"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/shopspring/decimal"
+	"github.com/vishal3152/AIDChaincode/aidcc/events"
 )
 
 // respnse struct to have consistent response structure for all chaincode invokes
 type response struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
-	Payload []byte `json:"payload"`
-}
-
-func (r *response) formatResponse() []byte {
-	var buffer bytes.Buffer
-	buffer.WriteString("{\"code\":")
-	buffer.WriteString("\"")
-	buffer.WriteString(r.Code)
-	buffer.WriteString("\",")
-	buffer.WriteString("\"message\":")
-	buffer.WriteString("\"")
-	buffer.WriteString(r.Message)
-	if r.Payload == nil {
-		buffer.WriteString("\"}")
-	} else {
-		buffer.WriteString("\",")
-		buffer.WriteString("\"payload\":")
-		buffer.Write(r.Payload)
-		buffer.WriteString("}")
+	Code    string          `json:"code"`
+	Message string          `json:"message"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// envelope - response as returned to clients, failed invokes return the same envelope with error details
+type envelope struct {
+	Code      string          `json:"code"`
+	Message   string          `json:"message"`
+	Payload   json.RawMessage `json:"payload"` // null if none
+	TxID      string          `json:"txID"`
+	TimeStamp time.Time       `json:"timeStamp"` // transaction timestamp
+	Errors    []errorDetail   `json:"errors"`    // empty on success
+}
+
+// errorDetail - structured detail of an error
+type errorDetail struct {
+	Code     string `json:"code"`
+	Function string `json:"function"`        // function/method reporting the error
+	Key      string `json:"key,omitempty"`   // associated key, if any
+	Field    string `json:"field,omitempty"` // input field in error, if any
+	Message  string `json:"message"`
+}
+
+// formatResponse - marshal the response into the envelope of the transaction
+func (r *response) formatResponse(stub shim.ChaincodeStubInterface) []byte {
+	env := newEnvelope(stub, r.Code, r.Message)
+	env.Payload = r.Payload
+	b, err := json.Marshal(env)
+	if err != nil {
+		// payload is not valid JSON
+		env = newEnvelope(stub, CODEGENEXCEPTION, err.Error())
+		b, _ = json.Marshal(env)
+	}
+	return b
+}
+
+// errorResponse - failed response carrying the error envelope. The envelope is returned as message too,
+// since clients often surface only the message of a failed proposal
+func errorResponse(stub shim.ChaincodeStubInterface, cErr *chainError) pb.Response {
+	env := newEnvelope(stub, cErr.code, cErr.err.Error())
+	env.Errors = []errorDetail{{Code: cErr.code, Function: cErr.fcn, Key: cErr.key, Message: cErr.err.Error()}}
+	b, _ := json.Marshal(env)
+	return pb.Response{Status: shim.ERROR, Message: string(b), Payload: b}
+}
+
+// fieldErrors - field errors collected by a validator, validations go on past the first failure so that
+// all invalid fields are reported at once
+type fieldErrors struct {
+	fcn    string
+	errors []errorDetail
+}
+
+// newFieldErrors - collector of field errors reported by the function
+func newFieldErrors(fcn string) *fieldErrors {
+	return &fieldErrors{fcn: fcn}
+}
+
+// add - add an error of the field. Only the first error of a field is kept, later checks of a field
+// usually fail because of the first one e.g. an empty amount is not a positive amount either
+func (fe *fieldErrors) add(field string, message string) {
+	fe.addCode(field, CODEUNPROCESSABLEENTITY, message)
+}
+
+// addCode - add an error of the field with a code more specific than invalid input
+func (fe *fieldErrors) addCode(field string, code string, message string) {
+	for _, e := range fe.errors {
+		if e.Field == field {
+			return
+		}
 	}
-	return buffer.Bytes()
+	fe.errors = append(fe.errors, errorDetail{Code: code, Function: fe.fcn, Field: field, Message: message})
+}
+
+// failed - check if any field error is collected
+func (fe *fieldErrors) failed() bool {
+	return fe != nil && len(fe.errors) != 0
+}
+
+// response - failed response listing all field errors
+func (fe *fieldErrors) response(stub shim.ChaincodeStubInterface) pb.Response {
+	messages := make([]string, len(fe.errors))
+	for i, e := range fe.errors {
+		messages[i] = e.Message
+	}
+	env := newEnvelope(stub, CODEUNPROCESSABLEENTITY, strings.Join(messages, "; "))
+	env.Errors = fe.errors
+	b, _ := json.Marshal(env)
+	return pb.Response{Status: shim.ERROR, Message: string(b), Payload: b}
+}
+
+// newEnvelope - envelope of the transaction
+func newEnvelope(stub shim.ChaincodeStubInterface, code string, message string) *envelope {
+	env := &envelope{Code: code, Message: message, TxID: stub.GetTxID(), Errors: []errorDetail{}}
+	epochTime, err := stub.GetTxTimestamp()
+	if err == nil && epochTime != nil {
+		env.TimeStamp = time.Unix(epochTime.GetSeconds(), 0).UTC()
+	}
+	return env
 }
 
 // chainError - custom error
@@ -76,11 +157,109 @@ func queryAsset(stub shim.ChaincodeStubInterface, assetID string) ([]byte, *chai
 		e := &chainError{"queryAsset", assetID, CODENOTFOUND, errors.New("Asset ID not found")}
 		return nil, e
 	}
+
+	// documents of an older schema version are upgraded on the fly, stored state is left as is
+	assetBytes, _, err = upgradeDoc(assetBytes)
+	if err != nil {
+		e := &chainError{"queryAsset", assetID, CODEGENEXCEPTION, err}
+		return nil, e
+	}
 	return assetBytes, nil
 }
 
+// putDelta - add indexkey for range query :- each fund movement is stored in form of a delta under
+// the affiliated project and aggregated whenever project state is read
+func putDelta(stub shim.ChaincodeStubInterface, fcn string, projectID string, bitmask string, currency string, txnID string, amount decimal.Decimal) *chainError {
+
+	indexKey, err := stub.CreateCompositeKey(INDXNM, []string{projectID, bitmask, currency, txnID, amount.String()})
+	if err != nil {
+		return &chainError{fcn, txnID, CODEGENEXCEPTION, err}
+	}
+	value := []byte{0x00}
+
+	err = stub.PutState(indexKey, value)
+	if err != nil {
+		return &chainError{fcn, txnID, CODEGENEXCEPTION, err}
+	}
+
+	// Fund movement is kept in project history by month along with its timestamp, history is never
+	// aggregated. Months of history are listed so that reports read only months not yet folded
+	epochTime, _ := stub.GetTxTimestamp()
+	txTime := time.Unix(epochTime.GetSeconds(), 0).UTC()
+	month := txTime.Format(MONTHFMT)
+	historyKey, err := stub.CreateCompositeKey(HSTIDX, []string{projectID, month, txTime.Format(time.RFC3339), bitmask, currency, txnID, amount.String()})
+	if err != nil {
+		return &chainError{fcn, txnID, CODEGENEXCEPTION, err}
+	}
+	err = stub.PutState(historyKey, value)
+	if err != nil {
+		return &chainError{fcn, txnID, CODEGENEXCEPTION, err}
+	}
+	monthKey, err := stub.CreateCompositeKey(MTHIDX, []string{projectID, month})
+	if err != nil {
+		return &chainError{fcn, txnID, CODEGENEXCEPTION, err}
+	}
+	err = stub.PutState(monthKey, value)
+	if err != nil {
+		return &chainError{fcn, txnID, CODEGENEXCEPTION, err}
+	}
+	return nil
+}
+
+// emitEvent - emit chaincode event with a JSON payload, header of the payload is filled from the transaction
+func emitEvent(stub shim.ChaincodeStubInterface, fcn string, key string, name string, e events.Event) *chainError {
+
+	epochTime, _ := stub.GetTxTimestamp()
+	h := e.Base()
+	h.Name = name
+	h.Version = events.Version
+	h.TxID = stub.GetTxID()
+	h.TimeStamp = time.Unix(epochTime.GetSeconds(), 0).UTC()
+
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return &chainError{fcn, key, CODEGENEXCEPTION, err}
+	}
+	err = stub.SetEvent(name, payload)
+	if err != nil {
+		return &chainError{fcn, key, CODEGENEXCEPTION, err}
+	}
+	return nil
+}
+
+// eventCollector - stub collecting events emitted by an invoke. Fabric keeps only the last event set by
+// a transaction, hence collected events are flushed as one composite event at the end of Invoke
+type eventCollector struct {
+	shim.ChaincodeStubInterface
+	entries []events.Entry
+}
+
+// SetEvent - collect event instead of setting it on the transaction
+func (ec *eventCollector) SetEvent(name string, payload []byte) error {
+	if len(name) == 0 {
+		return errors.New("Event name can not be empty")
+	}
+	ec.entries = append(ec.entries, events.Entry{Name: name, Payload: payload})
+	return nil
+}
+
+// flush - set collected events on the transaction as one composite event
+func (ec *eventCollector) flush() *chainError {
+	if len(ec.entries) == 0 {
+		return nil
+	}
+	return emitEvent(ec.ChaincodeStubInterface, "Invoke", ec.GetTxID(), events.Transaction, &events.Composite{Events: ec.entries})
+}
+
 // getCallerID - reterive caller id from ECert
 func getCallerID(stub shim.ChaincodeStubInterface) (string, *chainError) {
+	// Bypass whilst running unit test, unless test sets the caller
+	if os.Getenv("MODE") == "TEST" {
+		if caller := os.Getenv("CALLER"); len(caller) != 0 {
+			return caller, nil
+		}
+		return "Test Caller", nil
+	}
 	id, err := cid.New(stub)
 	callerID, err := id.GetID()
 	if err != nil {
@@ -96,3 +275,40 @@ func getCallerID(stub shim.ChaincodeStubInterface) (string, *chainError) {
 	l := strings.Split(string(data), "::")
 	return l[1], nil
 }
+
+// checkCallerRole - verify caller's ECert carries the role attribute required by an invoke
+func checkCallerRole(stub shim.ChaincodeStubInterface, role string) *chainError {
+	var callerRole string
+	if os.Getenv("MODE") != "TEST" {
+		val, found, err := cid.GetAttributeValue(stub, ROLEATTR)
+		if err != nil {
+			return &chainError{"checkCallerRole", "", CODEGENEXCEPTION, err}
+		}
+		if !found {
+			return &chainError{"checkCallerRole", "", CODEFORBIDDEN, errors.New("Caller has no role, excepting " + role)}
+		}
+		callerRole = val
+	} else {
+		// Bypass whilst running unit test, unless test sets the caller role
+		callerRole = os.Getenv("ROLE")
+		if len(callerRole) == 0 {
+			return nil
+		}
+	}
+	if callerRole != role {
+		return &chainError{"checkCallerRole", callerRole, CODEFORBIDDEN, errors.New("Caller role not authorized, excepting " + role)}
+	}
+	return nil
+}
+
+// checkProjectOwner - verify caller runs the project, callers with admin role may act on any project
+func checkProjectOwner(stub shim.ChaincodeStubInterface, fcn string, p *project) *chainError {
+	callerID, cErr := getCallerID(stub)
+	if cErr != nil {
+		return cErr
+	}
+	if callerID == p.Data.RunBy || checkCallerRole(stub, ROLEADMIN) == nil {
+		return nil
+	}
+	return &chainError{fcn, p.ProjectID, CODEFORBIDDEN, errors.New("Caller does not run the project")}
+}
@@ -1,8 +1,11 @@
 package main
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
-	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/hyperledger/fabric/core/chaincode/shim"
@@ -14,35 +17,47 @@ import (
 
 func validateProjectW(stub shim.ChaincodeStubInterface, args []string) pb.Response {
 
-	if len(args) != 2 {
-		cErr := &chainError{"validateProjectW", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting 2")}
-		return shim.Error(cErr.Error())
+	// 3rd argument i.e. base currency is optional, may be left empty when followed by
+	// 4th & 5th arguments i.e. overhead rate & overhead project
+	if len(args) != 2 && len(args) != 3 && len(args) != 5 {
+		cErr := &chainError{"validateProjectW", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting 2, 3 or 5")}
+		return errorResponse(stub, cErr)
 	}
+	fe := newFieldErrors("validateProjectW")
 	if len(args[0]) == 0 {
-		cErr := &chainError{"validateProjectW", "", CODEUNPROCESSABLEENTITY, errors.New("Project ID can not be empty")}
-		return shim.Error(cErr.Error())
+		fe.add("projectID", "Project ID can not be empty")
 	}
 	if len(args[1]) == 0 {
-		cErr := &chainError{"validateProjectW", "", CODEUNPROCESSABLEENTITY, errors.New("Project name can not be empty")}
-		//logger.Error(cErr.Error())
-		return shim.Error(cErr.Error())
+		fe.add("projectName", "Project name can not be empty")
 	}
 
 	epochTime, _ := stub.GetTxTimestamp()
 	startDt := time.Unix(epochTime.GetSeconds(), 0)
-	// Bypass whilst running unit test
-	var callerID string
-	if os.Getenv("MODE") != "TEST" {
-		var err *chainError
-		callerID, err = getCallerID(stub)
-		if err != nil {
-			return shim.Error(err.Error())
-		}
-	} else {
-		callerID = "Test Caller"
+	callerID, cErr := getCallerID(stub)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
 	}
 
-	prjBase := projectBase{ProjectName: args[1], StartDt: startDt, RunBy: callerID}
+	prjBase := projectBase{ProjectName: args[1], StartDt: startDt, RunBy: callerID, BaseCurrency: DEFCCY}
+	if len(args) > 2 && len(args[2]) != 0 {
+		prjBase.BaseCurrency = args[2]
+	}
+	if len(args) == 5 {
+		zeroDecimal, _ := decimal.NewFromString("0")
+		hundred, _ := decimal.NewFromString("100")
+		rate := fe.amount("overheadRate", args[3], hundred)
+		if rate.LessThanOrEqual(zeroDecimal) || rate.GreaterThanOrEqual(hundred) {
+			fe.add("overheadRate", "Overhead rate must be a percentage between 0 and 100")
+		}
+		if len(args[4]) == 0 || args[4] == args[0] {
+			fe.add("overheadPrj", "Overhead project ID can not be empty or the project itself")
+		}
+		prjBase.OverheadRate = rate
+		prjBase.OverheadPrj = args[4]
+	}
+	if fe.failed() {
+		return fe.response(stub)
+	}
 	p := &project{ObjectType: GPRJCT, ProjectID: args[0], Data: prjBase}
 	return saveAsset(stub, p)
 }
@@ -51,19 +66,20 @@ func validateItemW(stub shim.ChaincodeStubInterface, args []string) pb.Response
 
 	if len(args) != 3 {
 		cErr := &chainError{"validateItemW", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting 3")}
-		return shim.Error(cErr.Error())
+		return errorResponse(stub, cErr)
 	}
+	fe := newFieldErrors("validateItemW")
 	if len(args[0]) == 0 {
-		cErr := &chainError{"validateItemW", "", CODEUNPROCESSABLEENTITY, errors.New("Item ID can not be empty")}
-		return shim.Error(cErr.Error())
+		fe.add("itemID", "Item ID can not be empty")
 	}
 	if len(args[1]) == 0 {
-		cErr := &chainError{"validateItemW", "", CODEUNPROCESSABLEENTITY, errors.New("Item type can not be empty")}
-		return shim.Error(cErr.Error())
+		fe.add("itemType", "Item type can not be empty")
 	}
 	if len(args[2]) == 0 {
-		cErr := &chainError{"validateItemW", "", CODEUNPROCESSABLEENTITY, errors.New("Item narrative can not be empty")}
-		return shim.Error(cErr.Error())
+		fe.add("narrative", "Item narrative can not be empty")
+	}
+	if fe.failed() {
+		return fe.response(stub)
 	}
 
 	itmBase := itemBase{ItemType: args[1], Narrative: args[2]}
@@ -74,145 +90,1402 @@ func validateItemW(stub shim.ChaincodeStubInterface, args []string) pb.Response
 
 func validateDonationW(stub shim.ChaincodeStubInterface, args []string) pb.Response {
 
-	if len(args) != 5 {
-		cErr := &chainError{"validateDonationW", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting 5")}
-		return shim.Error(cErr.Error())
+	// 6th argument i.e. pledge ID & 7th argument i.e. currency are optional, pledge ID may be left empty
+	if len(args) < 5 || len(args) > 7 {
+		cErr := &chainError{"validateDonationW", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting 5 to 7")}
+		return errorResponse(stub, cErr)
 	}
+	fe := newFieldErrors("validateDonationW")
 	if len(args[0]) == 0 {
-		cErr := &chainError{"validateDonationW", "", CODEUNPROCESSABLEENTITY, errors.New("Txn ID can not be empty")}
-		return shim.Error(cErr.Error())
+		fe.add("txnID", "Txn ID can not be empty")
 	}
 	if len(args[1]) == 0 {
-		cErr := &chainError{"validateDonationW", "", CODEUNPROCESSABLEENTITY, errors.New("Donor name can not be empty")}
-		return shim.Error(cErr.Error())
+		fe.add("donor", "Donor name can not be empty")
 	}
 	if len(args[2]) == 0 {
-		cErr := &chainError{"validateDonationW", "", CODEUNPROCESSABLEENTITY, errors.New("Affiliated project ID can not be empty")}
-		return shim.Error(cErr.Error())
+		fe.add("projectID", "Affiliated project ID can not be empty")
 	}
 	if len(args[3]) == 0 {
-		cErr := &chainError{"validateDonationW", "", CODEUNPROCESSABLEENTITY, errors.New("Item ID can not be empty")}
-		return shim.Error(cErr.Error())
+		fe.add("itemID", "Item ID can not be empty")
 	}
 	if len(args[4]) == 0 {
-		cErr := &chainError{"validateDonationW", "", CODEUNPROCESSABLEENTITY, errors.New("Donation amount can not be empty")}
-		return shim.Error(cErr.Error())
+		fe.add("amount", "Donation amount can not be empty")
 	}
+	maxAmount := amountLimit(stub)
 	zeroDecimal, _ := decimal.NewFromString("0")
-	amount, _ := decimal.NewFromString(args[4])
+	amount := fe.amount("amount", args[4], maxAmount)
 	if amount.LessThanOrEqual(zeroDecimal) {
-		cErr := &chainError{"validateDonationW", "", CODEUNPROCESSABLEENTITY, errors.New("Donation amount can not less than or equal to zero")}
-		return shim.Error(cErr.Error())
+		fe.add("amount", "Donation amount can not less than or equal to zero")
+	}
+	if fe.failed() {
+		return fe.response(stub)
 	}
 
 	epochTime, _ := stub.GetTxTimestamp()
 	timeStamp := time.Unix(epochTime.GetSeconds(), 0)
 
-	donBase := donationBase{ProjectID: args[2], ItemID: args[3], Amount: amount.RoundBank(FIXEDPT), TimeStamp: timeStamp}
+	donBase := donationBase{ProjectID: args[2], ItemID: args[3], Amount: amount, TimeStamp: timeStamp}
 	d := &donation{ObjectType: DONIN, TxnID: args[0], Donor: args[1], Data: donBase}
+	if len(args) > 5 {
+		d.PledgeID = args[5]
+	}
+	if len(args) > 6 {
+		d.Data.Currency = args[6]
+	}
 
 	return saveAsset(stub, d)
 }
 
 func validateSpendW(stub shim.ChaincodeStubInterface, args []string) pb.Response {
 
-	if len(args) != 5 {
-		cErr := &chainError{"validateSpendW", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting 5")}
-		return shim.Error(cErr.Error())
+	// 6th argument i.e. currency, 7th argument i.e. budget category & 8th argument i.e. payee vendor ID
+	// are optional, currency & category may be left empty
+	if len(args) < 5 || len(args) > 8 {
+		cErr := &chainError{"validateSpendW", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting 5 to 8")}
+		return errorResponse(stub, cErr)
 	}
+	fe := newFieldErrors("validateSpendW")
 	if len(args[0]) == 0 {
-		cErr := &chainError{"validateSpendW", "", CODEUNPROCESSABLEENTITY, errors.New("Txn ID can not be empty")}
-		return shim.Error(cErr.Error())
+		fe.add("txnID", "Txn ID can not be empty")
 	}
 	if len(args[1]) == 0 {
-		cErr := &chainError{"validateSpendW", "", CODEUNPROCESSABLEENTITY, errors.New("Beneficiary can not be empty")}
-		return shim.Error(cErr.Error())
+		fe.add("beneficiary", "Beneficiary can not be empty")
 	}
 	if len(args[2]) == 0 {
-		cErr := &chainError{"validateSpendW", "", CODEUNPROCESSABLEENTITY, errors.New("Affiliated project ID can not be empty")}
-		return shim.Error(cErr.Error())
+		fe.add("projectID", "Affiliated project ID can not be empty")
 	}
 	if len(args[3]) == 0 {
-		cErr := &chainError{"validateSpendW", "", CODEUNPROCESSABLEENTITY, errors.New("Item ID can not be empty")}
-		return shim.Error(cErr.Error())
+		fe.add("itemID", "Item ID can not be empty")
 	}
 	if len(args[4]) == 0 {
-		cErr := &chainError{"validateSpendW", "", CODEUNPROCESSABLEENTITY, errors.New("Donation amount can not be empty")}
-		return shim.Error(cErr.Error())
+		fe.add("amount", "Donation amount can not be empty")
 	}
+	maxAmount := amountLimit(stub)
 	zeroDecimal, _ := decimal.NewFromString("0")
-	amount, _ := decimal.NewFromString(args[4])
+	amount := fe.amount("amount", args[4], maxAmount)
 	if amount.LessThanOrEqual(zeroDecimal) {
-		cErr := &chainError{"validateSpendW", "", CODEUNPROCESSABLEENTITY, errors.New("Donation amount can not less than or equal to zero")}
-		return shim.Error(cErr.Error())
+		fe.add("amount", "Donation amount can not less than or equal to zero")
+	}
+	if fe.failed() {
+		return fe.response(stub)
 	}
 
 	epochTime, _ := stub.GetTxTimestamp()
 	timeStamp := time.Unix(epochTime.GetSeconds(), 0)
 
-	spendData := donationBase{ProjectID: args[2], ItemID: args[3], Amount: amount.RoundBank(FIXEDPT), TimeStamp: timeStamp}
+	spendData := donationBase{ProjectID: args[2], ItemID: args[3], Amount: amount, TimeStamp: timeStamp}
+	if len(args) > 5 {
+		spendData.Currency = args[5]
+	}
 	s := &spend{ObjectType: DONOUT, TxnID: args[0], Benficiary: args[1], Data: spendData}
+	if len(args) > 6 {
+		s.Category = args[6]
+	}
+	if len(args) > 7 {
+		s.VendorID = args[7]
+	}
 
 	return saveAsset(stub, s)
 }
 
-func validateProjectR(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+func validateReversalW(stub shim.ChaincodeStubInterface, args []string) pb.Response {
 
-	if len(args) != 1 {
-		cErr := &chainError{"validateProjectR", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting project ID only")}
-		return shim.Error(cErr.Error())
+	if len(args) != 2 {
+		cErr := &chainError{"validateReversalW", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting 2")}
+		return errorResponse(stub, cErr)
+	}
+	fe := newFieldErrors("validateReversalW")
+	if len(args[0]) == 0 {
+		fe.add("txnID", "Txn ID can not be empty")
 	}
+	if len(args[1]) == 0 {
+		fe.add("reason", "Reversal reason can not be empty")
+	}
+	if fe.failed() {
+		return fe.response(stub)
+	}
+
+	epochTime, _ := stub.GetTxTimestamp()
+	timeStamp := time.Unix(epochTime.GetSeconds(), 0)
+
+	revData := donationBase{TimeStamp: timeStamp}
+	r := &reversal{ObjectType: DONREV, TxnID: reversalKey(args[0]), ReversalOf: args[0], Reason: args[1], Data: revData}
+
+	return saveAsset(stub, r)
+}
+
+func validatePledgeW(stub shim.ChaincodeStubInterface, args []string) pb.Response {
 
+	// 6th argument i.e. currency is optional
+	if len(args) != 5 && len(args) != 6 {
+		cErr := &chainError{"validatePledgeW", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting 5 or 6")}
+		return errorResponse(stub, cErr)
+	}
+	fe := newFieldErrors("validatePledgeW")
 	if len(args[0]) == 0 {
-		cErr := &chainError{"validateProjectR", "", CODEUNPROCESSABLEENTITY, errors.New("Project ID can not be empty")}
-		return shim.Error(cErr.Error())
+		fe.add("pledgeID", "Pledge ID can not be empty")
+	}
+	if len(args[1]) == 0 {
+		fe.add("donor", "Donor name can not be empty")
+	}
+	if len(args[2]) == 0 {
+		fe.add("projectID", "Affiliated project ID can not be empty")
+	}
+	if len(args[3]) == 0 {
+		fe.add("itemID", "Item ID can not be empty")
+	}
+	if len(args[4]) == 0 {
+		fe.add("amount", "Pledge amount can not be empty")
+	}
+	maxAmount := amountLimit(stub)
+	zeroDecimal, _ := decimal.NewFromString("0")
+	amount := fe.amount("amount", args[4], maxAmount)
+	if amount.LessThanOrEqual(zeroDecimal) {
+		fe.add("amount", "Pledge amount can not less than or equal to zero")
+	}
+	if fe.failed() {
+		return fe.response(stub)
 	}
 
-	p := &project{ProjectID: args[0]}
-	return readAsset(stub, p)
+	epochTime, _ := stub.GetTxTimestamp()
+	timeStamp := time.Unix(epochTime.GetSeconds(), 0)
+
+	plgBase := pledgeBase{ProjectID: args[2], ItemID: args[3], Amount: amount, FulfilledAmt: zeroDecimal, Status: PLGOPEN, TimeStamp: timeStamp}
+	if len(args) == 6 {
+		plgBase.Currency = args[5]
+	}
+	pl := &pledge{ObjectType: PLEDGE, PledgeID: args[0], Donor: args[1], Data: plgBase}
+
+	return saveAsset(stub, pl)
 }
 
-func validateItemR(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+func validatePledgeC(stub shim.ChaincodeStubInterface, args []string) pb.Response {
 
 	if len(args) != 1 {
-		cErr := &chainError{"validateItemR", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting Item ID only")}
-		return shim.Error(cErr.Error())
+		cErr := &chainError{"validatePledgeC", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting Pledge ID only")}
+		return errorResponse(stub, cErr)
 	}
+	fe := newFieldErrors("validatePledgeC")
 	if len(args[0]) == 0 {
-		cErr := &chainError{"validateItemR", "", CODEUNPROCESSABLEENTITY, errors.New("Item ID can not be empty")}
-		return shim.Error(cErr.Error())
+		fe.add("pledgeID", "Pledge ID can not be empty")
+	}
+	if fe.failed() {
+		return fe.response(stub)
 	}
 
-	it := &item{ItemID: args[0]}
-	return readAsset(stub, it)
+	pl := &pledge{PledgeID: args[0]}
+	return pl.cancelState(stub)
 }
 
-func validateDonationR(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+func validateRecurringW(stub shim.ChaincodeStubInterface, args []string) pb.Response {
 
-	if len(args) != 1 {
-		cErr := &chainError{"validateDonationR", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting Transaction ID only")}
-		return shim.Error(cErr.Error())
+	// 9th argument i.e. currency is optional
+	if len(args) != 8 && len(args) != 9 {
+		cErr := &chainError{"validateRecurringW", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting 8 or 9")}
+		return errorResponse(stub, cErr)
+	}
+	fe := newFieldErrors("validateRecurringW")
+	if len(args[0]) == 0 {
+		fe.add("scheduleID", "Schedule ID can not be empty")
+	}
+	if len(args[1]) == 0 {
+		fe.add("donor", "Donor name can not be empty")
+	}
+	if len(args[2]) == 0 {
+		fe.add("projectID", "Affiliated project ID can not be empty")
 	}
+	if len(args[3]) == 0 {
+		fe.add("itemID", "Item ID can not be empty")
+	}
+	if len(args[4]) == 0 {
+		fe.add("amount", "Instalment amount can not be empty")
+	}
+	maxAmount := amountLimit(stub)
+	zeroDecimal, _ := decimal.NewFromString("0")
+	amount := fe.amount("amount", args[4], maxAmount)
+	if amount.LessThanOrEqual(zeroDecimal) {
+		fe.add("amount", "Instalment amount can not less than or equal to zero")
+	}
+	if args[5] != WEEKLY && args[5] != MONTHLY && args[5] != QUARTERLY && args[5] != YEARLY {
+		fe.add("interval", "Interval must be one of WEEKLY, MONTHLY, QUARTERLY or YEARLY")
+	}
+	startDt, err := time.Parse(DATEFMT, args[6])
+	if err != nil {
+		fe.add("startDt", "Start date must be in YYYY-MM-DD format")
+	}
+	endDt, err := time.Parse(DATEFMT, args[7])
+	if err != nil {
+		fe.add("endDt", "End date must be in YYYY-MM-DD format")
+	}
+	if endDt.Before(startDt) {
+		fe.add("endDt", "End date can not be before start date")
+	}
+	if fe.failed() {
+		return fe.response(stub)
+	}
+
+	recBase := recurringBase{ProjectID: args[2], ItemID: args[3], Amount: amount, Interval: args[5], StartDt: startDt, EndDt: endDt}
+	if len(args) == 9 {
+		recBase.Currency = args[8]
+	}
+	rd := &recurringDonation{ObjectType: DONREC, ScheduleID: args[0], Donor: args[1], Data: recBase}
+
+	return saveAsset(stub, rd)
+}
+
+func validateInstalmentW(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+
+	// 4th argument i.e. period is optional, defaults to the current period
+	if len(args) != 3 && len(args) != 4 {
+		cErr := &chainError{"validateInstalmentW", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting 3 or 4")}
+		return errorResponse(stub, cErr)
+	}
+	fe := newFieldErrors("validateInstalmentW")
 	if len(args[0]) == 0 {
-		cErr := &chainError{"validateDonationR", "", CODEUNPROCESSABLEENTITY, errors.New("Txn ID can not be empty")}
-		return shim.Error(cErr.Error())
+		fe.add("txnID", "Txn ID can not be empty")
+	}
+	if len(args[1]) == 0 {
+		fe.add("scheduleID", "Schedule ID can not be empty")
+	}
+	if len(args[2]) == 0 {
+		fe.add("amount", "Instalment amount can not be empty")
+	}
+	maxAmount := amountLimit(stub)
+	zeroDecimal, _ := decimal.NewFromString("0")
+	amount := fe.amount("amount", args[2], maxAmount)
+	if amount.LessThanOrEqual(zeroDecimal) {
+		fe.add("amount", "Instalment amount can not less than or equal to zero")
+	}
+	period := 0
+	if len(args) == 4 {
+		var err error
+		period, err = strconv.Atoi(args[3])
+		if err != nil || period < 1 {
+			fe.add("period", "Period must be a whole number from 1")
+		}
+	}
+	if fe.failed() {
+		return fe.response(stub)
 	}
 
-	d := &donation{TxnID: args[0]}
-	return readAsset(stub, d)
+	epochTime, _ := stub.GetTxTimestamp()
+	timeStamp := time.Unix(epochTime.GetSeconds(), 0)
+
+	rd := &recurringDonation{ScheduleID: args[1]}
+	return rd.instalmentState(stub, args[0], amount, period, timeStamp)
 }
 
-func validateSpendR(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+func validateCampaignW(stub shim.ChaincodeStubInterface, args []string) pb.Response {
 
-	if len(args) != 1 {
-		cErr := &chainError{"validateSpendR", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting Transaction ID only")}
-		return shim.Error(cErr.Error())
+	// 8th argument i.e. currency is optional
+	if len(args) != 7 && len(args) != 8 {
+		cErr := &chainError{"validateCampaignW", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting 7 or 8")}
+		return errorResponse(stub, cErr)
 	}
+	fe := newFieldErrors("validateCampaignW")
 	if len(args[0]) == 0 {
-		cErr := &chainError{"validateSpendR", "", CODEUNPROCESSABLEENTITY, errors.New("Txn ID can not be empty")}
-		return shim.Error(cErr.Error())
+		fe.add("campaignID", "Campaign ID can not be empty")
+	}
+	if len(args[1]) == 0 {
+		fe.add("sponsor", "Sponsor name can not be empty")
+	}
+	if len(args[2]) == 0 {
+		fe.add("projectID", "Affiliated project ID can not be empty")
+	}
+	maxAmount := amountLimit(stub)
+	zeroDecimal, _ := decimal.NewFromString("0")
+	ratio := fe.amount("ratio", args[3], maxAmount)
+	if ratio.LessThanOrEqual(zeroDecimal) {
+		fe.add("ratio", "Match ratio can not less than or equal to zero")
+	}
+	matchCap := fe.amount("cap", args[4], maxAmount)
+	if matchCap.LessThanOrEqual(zeroDecimal) {
+		fe.add("cap", "Match cap can not less than or equal to zero")
+	}
+	startDt, err := time.Parse(DATEFMT, args[5])
+	if err != nil {
+		fe.add("startDt", "Start date must be in YYYY-MM-DD format")
+	}
+	endDt, err := time.Parse(DATEFMT, args[6])
+	if err != nil {
+		fe.add("endDt", "End date must be in YYYY-MM-DD format")
+	}
+	if endDt.Before(startDt) {
+		fe.add("endDt", "End date can not be before start date")
+	}
+	if fe.failed() {
+		return fe.response(stub)
 	}
 
-	s := &spend{TxnID: args[0]}
-	return readAsset(stub, s)
+	mtcBase := matchingBase{ProjectID: args[2], Ratio: ratio, Cap: matchCap, MatchedAmt: zeroDecimal, RemainingCap: matchCap, StartDt: startDt, EndDt: endDt}
+	if len(args) == 8 {
+		mtcBase.Currency = args[7]
+	}
+	m := &matchingCampaign{ObjectType: DONMTC, CampaignID: args[0], Sponsor: args[1], Data: mtcBase}
+
+	return saveAsset(stub, m)
+}
+
+func validateMilestoneW(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+
+	if len(args) != 4 {
+		cErr := &chainError{"validateMilestoneW", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting 4")}
+		return errorResponse(stub, cErr)
+	}
+	fe := newFieldErrors("validateMilestoneW")
+	if len(args[0]) == 0 {
+		fe.add("projectID", "Project ID can not be empty")
+	}
+	if len(args[1]) == 0 {
+		fe.add("milestoneID", "Milestone ID can not be empty")
+	}
+	if len(args[2]) == 0 {
+		fe.add("narrative", "Milestone narrative can not be empty")
+	}
+	maxAmount := amountLimit(stub)
+	zeroDecimal, _ := decimal.NewFromString("0")
+	budget := fe.amount("budget", args[3], maxAmount)
+	if budget.LessThanOrEqual(zeroDecimal) {
+		fe.add("budget", "Milestone budget can not less than or equal to zero")
+	}
+	if fe.failed() {
+		return fe.response(stub)
+	}
+
+	mlsBase := milestone{MilestoneID: args[1], Narrative: args[2], Budget: budget}
+	pm := &projectMilestone{ProjectID: args[0], Data: mlsBase}
+
+	return saveAsset(stub, pm)
+}
+
+func validateMilestoneA(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+
+	if len(args) != 2 {
+		cErr := &chainError{"validateMilestoneA", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting 2")}
+		return errorResponse(stub, cErr)
+	}
+	fe := newFieldErrors("validateMilestoneA")
+	if len(args[0]) == 0 {
+		fe.add("projectID", "Project ID can not be empty")
+	}
+	if len(args[1]) == 0 {
+		fe.add("milestoneID", "Milestone ID can not be empty")
+	}
+	if fe.failed() {
+		return fe.response(stub)
+	}
+
+	callerID, cErr := getCallerID(stub)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	epochTime, _ := stub.GetTxTimestamp()
+	timeStamp := time.Unix(epochTime.GetSeconds(), 0)
+
+	pm := &projectMilestone{ProjectID: args[0], Data: milestone{MilestoneID: args[1]}}
+	return pm.approveState(stub, callerID, timeStamp)
+}
+
+func validateBudgetLineW(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+
+	if len(args) != 3 {
+		cErr := &chainError{"validateBudgetLineW", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting 3")}
+		return errorResponse(stub, cErr)
+	}
+	fe := newFieldErrors("validateBudgetLineW")
+	if len(args[0]) == 0 {
+		fe.add("projectID", "Project ID can not be empty")
+	}
+	if len(args[1]) == 0 {
+		fe.add("category", "Budget category can not be empty")
+	}
+	maxAmount := amountLimit(stub)
+	zeroDecimal, _ := decimal.NewFromString("0")
+	allocated := fe.amount("allocated", args[2], maxAmount)
+	if allocated.LessThanOrEqual(zeroDecimal) {
+		fe.add("allocated", "Allocated amount can not less than or equal to zero")
+	}
+	if fe.failed() {
+		return fe.response(stub)
+	}
+
+	bl := &projectBudgetLine{ProjectID: args[0], Data: budgetLine{Category: args[1], Allocated: allocated}}
+	return saveAsset(stub, bl)
+}
+
+func validateFXRateW(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+
+	if len(args) != 3 {
+		cErr := &chainError{"validateFXRateW", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting 3")}
+		return errorResponse(stub, cErr)
+	}
+	fe := newFieldErrors("validateFXRateW")
+	currencies := strings.Split(args[0], "/")
+	if len(currencies) != 2 || currencies[0] == currencies[1] {
+		fe.add("pair", "Currency pair must be in BASE/QUOTE format e.g. EUR/USD")
+	}
+	for _, currency := range currencies {
+		if _, ok := precisionOf(currency); !ok {
+			fe.add("pair", "Unsupported currency "+currency)
+		}
+	}
+	maxAmount := amountLimit(stub)
+	zeroDecimal, _ := decimal.NewFromString("0")
+	rate := fe.amount("rate", args[1], maxAmount)
+	if rate.LessThanOrEqual(zeroDecimal) {
+		fe.add("rate", "FX rate can not less than or equal to zero")
+	}
+	asOf, err := time.Parse(DATEFMT, args[2])
+	if err != nil {
+		fe.add("asOf", "Effective date must be in YYYY-MM-DD format")
+	}
+	if fe.failed() {
+		return fe.response(stub)
+	}
+
+	callerID, cErr := getCallerID(stub)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	fxBase := fxRateBase{Rate: rate, AsOf: asOf, SetBy: callerID}
+	fx := &fxRate{ObjectType: FXRATE, Pair: args[0], Data: fxBase}
+
+	return saveAsset(stub, fx)
+}
+
+func validateInKindW(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+
+	// 8th argument i.e. currency of estimated value is optional
+	if len(args) != 7 && len(args) != 8 {
+		cErr := &chainError{"validateInKindW", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting 7 or 8")}
+		return errorResponse(stub, cErr)
+	}
+	fe := newFieldErrors("validateInKindW")
+	if len(args[0]) == 0 {
+		fe.add("txnID", "Txn ID can not be empty")
+	}
+	if len(args[1]) == 0 {
+		fe.add("donor", "Donor name can not be empty")
+	}
+	if len(args[2]) == 0 {
+		fe.add("projectID", "Affiliated project ID can not be empty")
+	}
+	if len(args[3]) == 0 {
+		fe.add("itemID", "Item ID can not be empty")
+	}
+	maxAmount := amountLimit(stub)
+	zeroDecimal, _ := decimal.NewFromString("0")
+	quantity := fe.amount("quantity", args[4], maxAmount)
+	if quantity.LessThanOrEqual(zeroDecimal) {
+		fe.add("quantity", "Quantity can not less than or equal to zero")
+	}
+	if len(args[5]) == 0 {
+		fe.add("unit", "Unit of measure can not be empty")
+	}
+	estValue := fe.amount("estValue", args[6], maxAmount)
+	if estValue.LessThanOrEqual(zeroDecimal) {
+		fe.add("estValue", "Estimated value can not less than or equal to zero")
+	}
+	if fe.failed() {
+		return fe.response(stub)
+	}
+
+	epochTime, _ := stub.GetTxTimestamp()
+	timeStamp := time.Unix(epochTime.GetSeconds(), 0)
+
+	inkBase := inKindBase{ProjectID: args[2], ItemID: args[3], Quantity: quantity, Unit: args[5], TimeStamp: timeStamp}
+	d := &inKindDonation{ObjectType: INKIN, TxnID: args[0], Donor: args[1], EstValue: estValue, Data: inkBase}
+	if len(args) == 8 {
+		d.Currency = args[7]
+	}
+
+	return saveAsset(stub, d)
+}
+
+func validateInKindS(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+
+	if len(args) != 6 {
+		cErr := &chainError{"validateInKindS", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting 6")}
+		return errorResponse(stub, cErr)
+	}
+	fe := newFieldErrors("validateInKindS")
+	if len(args[0]) == 0 {
+		fe.add("txnID", "Txn ID can not be empty")
+	}
+	if len(args[1]) == 0 {
+		fe.add("beneficiary", "Beneficiary can not be empty")
+	}
+	if len(args[2]) == 0 {
+		fe.add("projectID", "Affiliated project ID can not be empty")
+	}
+	if len(args[3]) == 0 {
+		fe.add("itemID", "Item ID can not be empty")
+	}
+	maxAmount := amountLimit(stub)
+	zeroDecimal, _ := decimal.NewFromString("0")
+	quantity := fe.amount("quantity", args[4], maxAmount)
+	if quantity.LessThanOrEqual(zeroDecimal) {
+		fe.add("quantity", "Quantity can not less than or equal to zero")
+	}
+	if len(args[5]) == 0 {
+		fe.add("unit", "Unit of measure can not be empty")
+	}
+	if fe.failed() {
+		return fe.response(stub)
+	}
+
+	epochTime, _ := stub.GetTxTimestamp()
+	timeStamp := time.Unix(epochTime.GetSeconds(), 0)
+
+	inkBase := inKindBase{ProjectID: args[2], ItemID: args[3], Quantity: quantity, Unit: args[5], TimeStamp: timeStamp}
+	s := &inKindSpend{ObjectType: INKOUT, TxnID: args[0], Benficiary: args[1], Data: inkBase}
+
+	return saveAsset(stub, s)
+}
+
+func validateEvidenceW(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+
+	if len(args) != 4 {
+		cErr := &chainError{"validateEvidenceW", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting 4")}
+		return errorResponse(stub, cErr)
+	}
+	fe := newFieldErrors("validateEvidenceW")
+	if len(args[0]) == 0 {
+		fe.add("txnID", "Txn ID can not be empty")
+	}
+	documentHash := strings.ToLower(args[1])
+	hash, err := hex.DecodeString(documentHash)
+	if err != nil || len(hash) != sha256.Size {
+		fe.add("documentHash", "Document hash must be a hex encoded SHA-256 digest")
+	}
+	if len(args[2]) == 0 {
+		fe.add("uri", "Document URI can not be empty")
+	}
+	if !strings.Contains(args[3], "/") {
+		fe.add("mediaType", "Media type must be in type/subtype format e.g. image/jpeg")
+	}
+	if fe.failed() {
+		return fe.response(stub)
+	}
+
+	epochTime, _ := stub.GetTxTimestamp()
+	timeStamp := time.Unix(epochTime.GetSeconds(), 0)
+	callerID, cErr := getCallerID(stub)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	evdBase := evidence{DocumentHash: documentHash, URI: args[2], MediaType: args[3], AddedBy: callerID, TimeStamp: timeStamp}
+	se := &spendEvidence{TxnID: args[0], Data: evdBase}
+
+	return saveAsset(stub, se)
+}
+
+func validateVoucherW(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+
+	// 7th argument i.e. currency & 8th argument i.e. budget category are optional, currency may be left empty
+	if len(args) < 6 || len(args) > 8 {
+		cErr := &chainError{"validateVoucherW", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting 6 to 8")}
+		return errorResponse(stub, cErr)
+	}
+	fe := newFieldErrors("validateVoucherW")
+	if len(args[0]) == 0 {
+		fe.add("voucherID", "Voucher ID can not be empty")
+	}
+	if len(args[1]) == 0 {
+		fe.add("beneficiary", "Beneficiary can not be empty")
+	}
+	if len(args[2]) == 0 {
+		fe.add("projectID", "Affiliated project ID can not be empty")
+	}
+	if len(args[3]) == 0 {
+		fe.add("itemID", "Item ID can not be empty")
+	}
+	maxAmount := amountLimit(stub)
+	zeroDecimal, _ := decimal.NewFromString("0")
+	amount := fe.amount("amount", args[4], maxAmount)
+	if amount.LessThanOrEqual(zeroDecimal) {
+		fe.add("amount", "Voucher amount can not less than or equal to zero")
+	}
+	expiryDt, err := time.Parse(DATEFMT, args[5])
+	if err != nil {
+		fe.add("expiryDt", "Expiry date must be in YYYY-MM-DD format")
+	}
+
+	epochTime, _ := stub.GetTxTimestamp()
+	timeStamp := time.Unix(epochTime.GetSeconds(), 0)
+
+	vchBase := voucherBase{ProjectID: args[2], ItemID: args[3], Amount: amount, Status: VCHISSD, IssuedDt: timeStamp, ExpiryDt: expiryDt}
+	if len(args) > 6 {
+		vchBase.Currency = args[6]
+	}
+	if len(args) > 7 {
+		vchBase.Category = args[7]
+	}
+	v := &voucher{ObjectType: VOUCHR, VoucherID: args[0], Benficiary: args[1], Data: vchBase}
+	if v.expired(timeStamp) {
+		fe.add("expiryDt", "Expiry date can not be in the past")
+	}
+	if fe.failed() {
+		return fe.response(stub)
+	}
+
+	return saveAsset(stub, v)
+}
+
+func validateVoucherRdm(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+
+	if len(args) != 1 {
+		cErr := &chainError{"validateVoucherRdm", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting Voucher ID only")}
+		return errorResponse(stub, cErr)
+	}
+	fe := newFieldErrors("validateVoucherRdm")
+	if len(args[0]) == 0 {
+		fe.add("voucherID", "Voucher ID can not be empty")
+	}
+	if fe.failed() {
+		return fe.response(stub)
+	}
+
+	callerID, cErr := getCallerID(stub)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	epochTime, _ := stub.GetTxTimestamp()
+	timeStamp := time.Unix(epochTime.GetSeconds(), 0)
+
+	v := &voucher{VoucherID: args[0]}
+	return v.redeemState(stub, callerID, timeStamp)
+}
+
+func validateVoucherExp(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+
+	if len(args) != 1 {
+		cErr := &chainError{"validateVoucherExp", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting Voucher ID only")}
+		return errorResponse(stub, cErr)
+	}
+	fe := newFieldErrors("validateVoucherExp")
+	if len(args[0]) == 0 {
+		fe.add("voucherID", "Voucher ID can not be empty")
+	}
+	if fe.failed() {
+		return fe.response(stub)
+	}
+
+	epochTime, _ := stub.GetTxTimestamp()
+	timeStamp := time.Unix(epochTime.GetSeconds(), 0)
+
+	v := &voucher{VoucherID: args[0]}
+	return v.expireState(stub, timeStamp)
+}
+
+func validateVendorW(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+
+	if len(args) != 4 {
+		cErr := &chainError{"validateVendorW", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting 4")}
+		return errorResponse(stub, cErr)
+	}
+	fe := newFieldErrors("validateVendorW")
+	if len(args[0]) == 0 {
+		fe.add("vendorID", "Vendor ID can not be empty")
+	}
+	if len(args[1]) == 0 {
+		fe.add("legalName", "Legal name can not be empty")
+	}
+	if len(args[2]) == 0 {
+		fe.add("registrationID", "Registration ID can not be empty")
+	}
+	bankRefHash := strings.ToLower(args[3])
+	hash, err := hex.DecodeString(bankRefHash)
+	if err != nil || len(hash) != sha256.Size {
+		fe.add("bankRefHash", "Bank reference hash must be a hex encoded SHA-256 digest")
+	}
+	if fe.failed() {
+		return fe.response(stub)
+	}
+
+	callerID, cErr := getCallerID(stub)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	epochTime, _ := stub.GetTxTimestamp()
+	timeStamp := time.Unix(epochTime.GetSeconds(), 0)
+
+	vndBase := vendorBase{LegalName: args[1], RegistrationID: args[2], BankRefHash: bankRefHash, Status: VNDACTV, UpdatedBy: callerID, UpdatedDt: timeStamp}
+	vd := &vendor{ObjectType: VENDOR, VendorID: args[0], Data: vndBase}
+
+	return saveAsset(stub, vd)
+}
+
+func validateVendorS(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+
+	if len(args) != 2 {
+		cErr := &chainError{"validateVendorS", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting 2")}
+		return errorResponse(stub, cErr)
+	}
+	fe := newFieldErrors("validateVendorS")
+	if len(args[0]) == 0 {
+		fe.add("vendorID", "Vendor ID can not be empty")
+	}
+	status := strings.ToUpper(args[1])
+	if status != VNDACTV && status != VNDSUSP {
+		fe.add("status", "Vendor status must be "+VNDACTV+" or "+VNDSUSP)
+	}
+	if fe.failed() {
+		return fe.response(stub)
+	}
+
+	callerID, cErr := getCallerID(stub)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	epochTime, _ := stub.GetTxTimestamp()
+	timeStamp := time.Unix(epochTime.GetSeconds(), 0)
+
+	vd := &vendor{VendorID: args[0]}
+	return vd.statusState(stub, status, callerID, timeStamp)
+}
+
+func validateBlockedPartyW(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+
+	if len(args) != 2 {
+		cErr := &chainError{"validateBlockedPartyW", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting 2")}
+		return errorResponse(stub, cErr)
+	}
+	fe := newFieldErrors("validateBlockedPartyW")
+	partyHash := strings.ToLower(args[0])
+	hash, err := hex.DecodeString(partyHash)
+	if err != nil || len(hash) != sha256.Size {
+		fe.add("partyHash", "Party hash must be a hex encoded SHA-256 digest")
+	}
+	if len(args[1]) == 0 {
+		fe.add("reason", "Listing reason can not be empty")
+	}
+	if fe.failed() {
+		return fe.response(stub)
+	}
+
+	callerID, cErr := getCallerID(stub)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	epochTime, _ := stub.GetTxTimestamp()
+	timeStamp := time.Unix(epochTime.GetSeconds(), 0)
+
+	blkBase := blockedPartyBase{Reason: args[1], Active: true, UpdatedBy: callerID, UpdatedDt: timeStamp}
+	bp := &blockedParty{ObjectType: BLKPTY, PartyHash: partyHash, Data: blkBase}
+
+	return saveAsset(stub, bp)
+}
+
+func validateScreeningW(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+
+	if len(args) != 3 {
+		cErr := &chainError{"validateScreeningW", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting 3")}
+		return errorResponse(stub, cErr)
+	}
+	fe := newFieldErrors("validateScreeningW")
+	if len(args[0]) == 0 {
+		fe.add("txnID", "Txn ID can not be empty")
+	}
+	if !screenedParties[args[1]] {
+		fe.add("party", "Party must be donor, sponsor, beneficiary, vendor or vendorRegistration")
+	}
+	if len(args[2]) == 0 {
+		fe.add("partyID", "Party ID can not be empty")
+	}
+	if fe.failed() {
+		return fe.response(stub)
+	}
+
+	return recordScreeningState(stub, args[0], args[1], args[2])
+}
+
+func validateBlockedPartyD(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+
+	if len(args) != 1 {
+		cErr := &chainError{"validateBlockedPartyD", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting Party hash only")}
+		return errorResponse(stub, cErr)
+	}
+	fe := newFieldErrors("validateBlockedPartyD")
+	if len(args[0]) == 0 {
+		fe.add("partyHash", "Party hash can not be empty")
+	}
+	if fe.failed() {
+		return fe.response(stub)
+	}
+
+	callerID, cErr := getCallerID(stub)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	epochTime, _ := stub.GetTxTimestamp()
+	timeStamp := time.Unix(epochTime.GetSeconds(), 0)
+
+	bp := &blockedParty{PartyHash: strings.ToLower(args[0])}
+	return bp.delistState(stub, callerID, timeStamp)
+}
+
+func validateAMLLimitsW(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+
+	// 4th argument i.e. currency is optional
+	if len(args) != 3 && len(args) != 4 {
+		cErr := &chainError{"validateAMLLimitsW", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting 3 or 4")}
+		return errorResponse(stub, cErr)
+	}
+	fe := newFieldErrors("validateAMLLimitsW")
+	limits := make([]decimal.Decimal, 3)
+	limitFields := []string{"singleLimit", "rollingLimit", "anonymousCap"}
+	maxAmount := amountLimit(stub)
+	for i := range limits {
+		limit := fe.amount(limitFields[i], args[i], maxAmount)
+		if limit.Sign() < 0 {
+			fe.add(limitFields[i], "AML limits must be zero or positive amounts")
+		}
+		limits[i] = limit
+	}
+	currency := DEFCCY
+	if len(args) == 4 && len(args[3]) != 0 {
+		currency = args[3]
+	}
+	if _, ok := precisionOf(currency); !ok {
+		fe.add("currency", "Unsupported currency "+currency)
+	}
+	if fe.failed() {
+		return fe.response(stub)
+	}
+
+	callerID, cErr := getCallerID(stub)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	epochTime, _ := stub.GetTxTimestamp()
+	timeStamp := time.Unix(epochTime.GetSeconds(), 0)
+
+	amlBase := amlLimitsBase{SingleLimit: limits[0], RollingLimit: limits[1], AnonymousCap: limits[2], Currency: currency, UpdatedBy: callerID, UpdatedDt: timeStamp}
+	al := &amlLimits{ObjectType: AMLCFG, Data: amlBase}
+
+	return saveAsset(stub, al)
+}
+
+func validateDonationRvw(stub shim.ChaincodeStubInterface, args []string, approve bool) pb.Response {
+
+	// 2nd argument i.e. remarks is optional on approval & mandatory on rejection
+	if len(args) != 1 && len(args) != 2 {
+		cErr := &chainError{"validateDonationRvw", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting Txn ID & remarks")}
+		return errorResponse(stub, cErr)
+	}
+	fe := newFieldErrors("validateDonationRvw")
+	if len(args[0]) == 0 {
+		fe.add("txnID", "Txn ID can not be empty")
+	}
+	remarks := ""
+	if len(args) == 2 {
+		remarks = args[1]
+	}
+	if !approve && len(remarks) == 0 {
+		fe.add("remarks", "Rejection remarks can not be empty")
+	}
+	if fe.failed() {
+		return fe.response(stub)
+	}
+
+	callerID, cErr := getCallerID(stub)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	epochTime, _ := stub.GetTxTimestamp()
+	timeStamp := time.Unix(epochTime.GetSeconds(), 0)
+
+	d := &donation{TxnID: args[0]}
+	return d.reviewState(stub, approve, remarks, callerID, timeStamp)
+}
+
+func validateProjectR(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+
+	// 2nd argument i.e. reporting currency is optional
+	if len(args) != 1 && len(args) != 2 {
+		cErr := &chainError{"validateProjectR", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting project ID and optional currency")}
+		return errorResponse(stub, cErr)
+	}
+	fe := newFieldErrors("validateProjectR")
+
+	if len(args[0]) == 0 {
+		fe.add("projectID", "Project ID can not be empty")
+	}
+	if fe.failed() {
+		return fe.response(stub)
+	}
+
+	p := &project{ProjectID: args[0]}
+	if len(args) == 2 && len(args[1]) != 0 {
+		return p.convertState(stub, args[1])
+	}
+	return readAsset(stub, p)
+}
+
+func validateItemR(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+
+	if len(args) != 1 {
+		cErr := &chainError{"validateItemR", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting Item ID only")}
+		return errorResponse(stub, cErr)
+	}
+	fe := newFieldErrors("validateItemR")
+	if len(args[0]) == 0 {
+		fe.add("itemID", "Item ID can not be empty")
+	}
+	if fe.failed() {
+		return fe.response(stub)
+	}
+
+	it := &item{ItemID: args[0]}
+	return readAsset(stub, it)
+}
+
+func validateDonationR(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+
+	if len(args) != 1 {
+		cErr := &chainError{"validateDonationR", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting Transaction ID only")}
+		return errorResponse(stub, cErr)
+	}
+	fe := newFieldErrors("validateDonationR")
+	if len(args[0]) == 0 {
+		fe.add("txnID", "Txn ID can not be empty")
+	}
+	if fe.failed() {
+		return fe.response(stub)
+	}
+
+	d := &donation{TxnID: args[0]}
+	return readAsset(stub, d)
+}
+
+func validateSpendR(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+
+	if len(args) != 1 {
+		cErr := &chainError{"validateSpendR", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting Transaction ID only")}
+		return errorResponse(stub, cErr)
+	}
+	fe := newFieldErrors("validateSpendR")
+	if len(args[0]) == 0 {
+		fe.add("txnID", "Txn ID can not be empty")
+	}
+	if fe.failed() {
+		return fe.response(stub)
+	}
+
+	s := &spend{TxnID: args[0]}
+	return readAsset(stub, s)
+}
+
+func validateReversalR(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+
+	if len(args) != 1 {
+		cErr := &chainError{"validateReversalR", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting Transaction ID only")}
+		return errorResponse(stub, cErr)
+	}
+	fe := newFieldErrors("validateReversalR")
+	if len(args[0]) == 0 {
+		fe.add("txnID", "Txn ID can not be empty")
+	}
+	if fe.failed() {
+		return fe.response(stub)
+	}
+
+	r := &reversal{TxnID: args[0]}
+	return readAsset(stub, r)
+}
+
+func validatePledgeR(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+
+	if len(args) != 1 {
+		cErr := &chainError{"validatePledgeR", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting Pledge ID only")}
+		return errorResponse(stub, cErr)
+	}
+	fe := newFieldErrors("validatePledgeR")
+	if len(args[0]) == 0 {
+		fe.add("pledgeID", "Pledge ID can not be empty")
+	}
+	if fe.failed() {
+		return fe.response(stub)
+	}
+
+	pl := &pledge{PledgeID: args[0]}
+	return readAsset(stub, pl)
+}
+
+func validateRecurringR(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+
+	if len(args) != 1 {
+		cErr := &chainError{"validateRecurringR", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting Schedule ID only")}
+		return errorResponse(stub, cErr)
+	}
+	fe := newFieldErrors("validateRecurringR")
+	if len(args[0]) == 0 {
+		fe.add("scheduleID", "Schedule ID can not be empty")
+	}
+	if fe.failed() {
+		return fe.response(stub)
+	}
+
+	rd := &recurringDonation{ScheduleID: args[0]}
+	return readAsset(stub, rd)
+}
+
+func validateMissedR(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+
+	if len(args) != 1 {
+		cErr := &chainError{"validateMissedR", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting Schedule ID only")}
+		return errorResponse(stub, cErr)
+	}
+	fe := newFieldErrors("validateMissedR")
+	if len(args[0]) == 0 {
+		fe.add("scheduleID", "Schedule ID can not be empty")
+	}
+	if fe.failed() {
+		return fe.response(stub)
+	}
+
+	epochTime, _ := stub.GetTxTimestamp()
+	timeStamp := time.Unix(epochTime.GetSeconds(), 0)
+
+	rd := &recurringDonation{ScheduleID: args[0]}
+	return rd.missedState(stub, timeStamp)
+}
+
+func validateCampaignR(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+
+	if len(args) != 1 {
+		cErr := &chainError{"validateCampaignR", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting Campaign ID only")}
+		return errorResponse(stub, cErr)
+	}
+	fe := newFieldErrors("validateCampaignR")
+	if len(args[0]) == 0 {
+		fe.add("campaignID", "Campaign ID can not be empty")
+	}
+	if fe.failed() {
+		return fe.response(stub)
+	}
+
+	m := &matchingCampaign{CampaignID: args[0]}
+	return readAsset(stub, m)
+}
+
+func validateMilestoneR(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+
+	if len(args) != 2 {
+		cErr := &chainError{"validateMilestoneR", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting project ID & milestone ID only")}
+		return errorResponse(stub, cErr)
+	}
+	fe := newFieldErrors("validateMilestoneR")
+	if len(args[0]) == 0 {
+		fe.add("projectID", "Project ID can not be empty")
+	}
+	if len(args[1]) == 0 {
+		fe.add("milestoneID", "Milestone ID can not be empty")
+	}
+	if fe.failed() {
+		return fe.response(stub)
+	}
+
+	pm := &projectMilestone{ProjectID: args[0], Data: milestone{MilestoneID: args[1]}}
+	return readAsset(stub, pm)
+}
+
+func validateFXRateR(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+
+	if len(args) != 2 {
+		cErr := &chainError{"validateFXRateR", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting currency pair & date only")}
+		return errorResponse(stub, cErr)
+	}
+	fe := newFieldErrors("validateFXRateR")
+	if len(args[0]) == 0 {
+		fe.add("pair", "Currency pair can not be empty")
+	}
+	asOf, err := time.Parse(DATEFMT, args[1])
+	if err != nil {
+		fe.add("asOf", "Date must be in YYYY-MM-DD format")
+	}
+	if fe.failed() {
+		return fe.response(stub)
+	}
+
+	fx := &fxRate{Pair: args[0], Data: fxRateBase{AsOf: asOf}}
+	return readAsset(stub, fx)
+}
+
+func validateBudgetR(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+
+	// 2nd argument i.e. budget category is optional, all budget lines are reported otherwise
+	if len(args) != 1 && len(args) != 2 {
+		cErr := &chainError{"validateBudgetR", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting project ID and optional category")}
+		return errorResponse(stub, cErr)
+	}
+	fe := newFieldErrors("validateBudgetR")
+	if len(args[0]) == 0 {
+		fe.add("projectID", "Project ID can not be empty")
+	}
+	if fe.failed() {
+		return fe.response(stub)
+	}
+
+	bl := &projectBudgetLine{ProjectID: args[0]}
+	if len(args) == 2 {
+		bl.Data.Category = args[1]
+	}
+	return readAsset(stub, bl)
+}
+
+func validateInKindR(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+
+	if len(args) != 1 {
+		cErr := &chainError{"validateInKindR", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting Txn ID only")}
+		return errorResponse(stub, cErr)
+	}
+	fe := newFieldErrors("validateInKindR")
+	if len(args[0]) == 0 {
+		fe.add("txnID", "Txn ID can not be empty")
+	}
+	if fe.failed() {
+		return fe.response(stub)
+	}
+
+	// in-kind donations & distributions share the key space, either is returned as stored
+	d := &inKindDonation{TxnID: args[0]}
+	return readAsset(stub, d)
+}
+
+func validateInventoryR(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+
+	// 2nd argument i.e. item ID is optional, all items are reported otherwise
+	if len(args) != 1 && len(args) != 2 {
+		cErr := &chainError{"validateInventoryR", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting project ID and optional item ID")}
+		return errorResponse(stub, cErr)
+	}
+	fe := newFieldErrors("validateInventoryR")
+	if len(args[0]) == 0 {
+		fe.add("projectID", "Project ID can not be empty")
+	}
+	if fe.failed() {
+		return fe.response(stub)
+	}
+
+	p := &project{ProjectID: args[0]}
+	itemID := ""
+	if len(args) == 2 {
+		itemID = args[1]
+	}
+	return p.inventoryState(stub, itemID)
+}
+
+func validateEvidenceR(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+
+	if len(args) != 2 {
+		cErr := &chainError{"validateEvidenceR", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting Txn ID & document hash only")}
+		return errorResponse(stub, cErr)
+	}
+	fe := newFieldErrors("validateEvidenceR")
+	if len(args[0]) == 0 {
+		fe.add("txnID", "Txn ID can not be empty")
+	}
+	if len(args[1]) == 0 {
+		fe.add("documentHash", "Document hash can not be empty")
+	}
+	if fe.failed() {
+		return fe.response(stub)
+	}
+
+	se := &spendEvidence{TxnID: args[0], Data: evidence{DocumentHash: strings.ToLower(args[1])}}
+	return readAsset(stub, se)
+}
+
+func validateUnevidencedR(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+
+	if len(args) != 1 {
+		cErr := &chainError{"validateUnevidencedR", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting project ID only")}
+		return errorResponse(stub, cErr)
+	}
+	fe := newFieldErrors("validateUnevidencedR")
+	if len(args[0]) == 0 {
+		fe.add("projectID", "Project ID can not be empty")
+	}
+	if fe.failed() {
+		return fe.response(stub)
+	}
+
+	p := &project{ProjectID: args[0]}
+	return p.unevidencedState(stub)
+}
+
+func validateVoucherR(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+
+	if len(args) != 1 {
+		cErr := &chainError{"validateVoucherR", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting Voucher ID only")}
+		return errorResponse(stub, cErr)
+	}
+	fe := newFieldErrors("validateVoucherR")
+	if len(args[0]) == 0 {
+		fe.add("voucherID", "Voucher ID can not be empty")
+	}
+	if fe.failed() {
+		return fe.response(stub)
+	}
+
+	v := &voucher{VoucherID: args[0]}
+	return readAsset(stub, v)
+}
+
+func validateVendorR(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+
+	if len(args) != 1 {
+		cErr := &chainError{"validateVendorR", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting Vendor ID only")}
+		return errorResponse(stub, cErr)
+	}
+	fe := newFieldErrors("validateVendorR")
+	if len(args[0]) == 0 {
+		fe.add("vendorID", "Vendor ID can not be empty")
+	}
+	if fe.failed() {
+		return fe.response(stub)
+	}
+
+	vd := &vendor{VendorID: args[0]}
+	return readAsset(stub, vd)
+}
+
+func validateVendorSpendR(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+
+	// 2nd argument i.e. project ID is optional
+	if len(args) != 1 && len(args) != 2 {
+		cErr := &chainError{"validateVendorSpendR", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting Vendor ID & optional project ID")}
+		return errorResponse(stub, cErr)
+	}
+	fe := newFieldErrors("validateVendorSpendR")
+	if len(args[0]) == 0 {
+		fe.add("vendorID", "Vendor ID can not be empty")
+	}
+	if fe.failed() {
+		return fe.response(stub)
+	}
+
+	vd := &vendor{VendorID: args[0]}
+	if len(args) == 2 {
+		return vd.spendingState(stub, args[1])
+	}
+	return vd.spendingState(stub, "")
+}
+
+func validateBlockedPartyR(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+
+	if len(args) != 1 {
+		cErr := &chainError{"validateBlockedPartyR", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting Party hash only")}
+		return errorResponse(stub, cErr)
+	}
+	fe := newFieldErrors("validateBlockedPartyR")
+	if len(args[0]) == 0 {
+		fe.add("partyHash", "Party hash can not be empty")
+	}
+	if fe.failed() {
+		return fe.response(stub)
+	}
+
+	bp := &blockedParty{PartyHash: strings.ToLower(args[0])}
+	return readAsset(stub, bp)
+}
+
+func validateScreeningR(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+
+	if len(args) != 1 {
+		cErr := &chainError{"validateScreeningR", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting Txn ID only")}
+		return errorResponse(stub, cErr)
+	}
+	fe := newFieldErrors("validateScreeningR")
+	if len(args[0]) == 0 {
+		fe.add("txnID", "Txn ID can not be empty")
+	}
+	if fe.failed() {
+		return fe.response(stub)
+	}
+
+	return screeningState(stub, args[0])
+}
+
+func validateAMLLimitsR(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+
+	if len(args) != 0 {
+		cErr := &chainError{"validateAMLLimitsR", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting none")}
+		return errorResponse(stub, cErr)
+	}
+
+	al := &amlLimits{}
+	return readAsset(stub, al)
+}
+
+func validateReviewQueueR(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+
+	// 1st argument i.e. project ID is optional
+	if len(args) > 1 {
+		cErr := &chainError{"validateReviewQueueR", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting optional project ID only")}
+		return errorResponse(stub, cErr)
+	}
+
+	if len(args) == 1 {
+		return reviewQueueState(stub, args[0])
+	}
+	return reviewQueueState(stub, "")
+}
+
+func validateCatalogueR(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+
+	if len(args) != 0 {
+		cErr := &chainError{"validateCatalogueR", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting none")}
+		return errorResponse(stub, cErr)
+	}
+
+	return errorCatalogueState(stub)
+}
+
+func validateFunctionsR(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+
+	if len(args) != 0 {
+		cErr := &chainError{"validateFunctionsR", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting none")}
+		return errorResponse(stub, cErr)
+	}
+
+	return functionsState(stub)
+}
+
+func validateMigrateW(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+
+	// 3rd argument i.e. bookmark is optional
+	if len(args) < 2 || len(args) > 3 {
+		cErr := &chainError{"validateMigrateW", "", CODEUNPROCESSABLEENTITY, errors.New("Incorrect no of input args, excepting 2 or 3")}
+		return errorResponse(stub, cErr)
+	}
+	fe := newFieldErrors("validateMigrateW")
+	if _, ok := assetTypes[args[0]]; !ok {
+		fe.add("assetType", "Unknown asset type "+args[0])
+	}
+	batchSize, err := strconv.Atoi(args[1])
+	if err != nil || batchSize < 1 || batchSize > MAXBATCH {
+		fe.add("batchSize", "Batch size must be a whole number from 1 to "+strconv.Itoa(MAXBATCH))
+	}
+	if fe.failed() {
+		return fe.response(stub)
+	}
+
+	bookmark := ""
+	if len(args) == 3 {
+		bookmark = args[2]
+	}
+	return migrateState(stub, args[0], batchSize, bookmark)
 }
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/stretchr/testify/assert"
+)
+
+// Verifies scenarios related to field errors collected by validations
+func TestFieldErrors(t *testing.T) {
+	fmt.Println("Executing Test - FieldErrors")
+
+	// Test data - Refer to test narratives for test description. Invokes are executed in order
+	var fieldTable = []struct {
+		args           []string
+		expectedFields []string
+		testNarrative  string
+	}{
+		{[]string{AddDonation, "D101", "", "P101", "Itm001", "0"}, []string{"donor", "amount"}, "Check for all invalid fields reported"},
+		{[]string{AddDonation, "", "", "", "", ""}, []string{"txnID", "donor", "projectID", "itemID", "amount"}, "Check for single error per field"},
+		{[]string{IssueVoucher, "V101", "beneficiary", "P101", "Itm001", "10", "2019-02-30"}, []string{"expiryDt"}, "Check for unparsable date reported once"},
+		{[]string{AddDonation, `{"txnID":105,"donor":"vishal","projectID":"P101","amount":true,"memo":"x"}`}, []string{"memo", "txnID", "itemID", "amount"}, "Check for all invalid document fields reported"},
+		{[]string{AddDonation, `{"txnID":"D105",`}, []string{""}, "Check for malformed document"},
+	}
+
+	// struct for parsing the failed response envelope
+	type eResp struct {
+		Code   string        `json:"code"`
+		Errors []errorDetail `json:"errors"`
+	}
+	assert := assert.New(t)
+
+	// Instantiate mockStub using AidChaincode as the target chaincode to unit test
+	stub := shim.NewMockStub("TestStub", new(AidChaincode))
+	assert.NotNil(stub, "Stub is nil, Test stub creation failed")
+
+	// Executing field error tests
+	for _, test := range fieldTable {
+		args := make([][]byte, len(test.args))
+		for i, arg := range test.args {
+			args[i] = []byte(arg)
+		}
+		result := stub.MockInvoke(uuid.New().String(), args)
+		assert.EqualValues(shim.ERROR, result.GetStatus(), test.testNarrative+" failed - error expected")
+
+		e := &eResp{}
+		err := json.Unmarshal(result.GetPayload(), e)
+		if err != nil {
+			panic(err)
+		}
+		assert.Equal(CODEUNPROCESSABLEENTITY, e.Code, test.testNarrative+" failed - error code mismatch")
+		fields := make([]string, len(e.Errors))
+		for i, d := range e.Errors {
+			fields[i] = d.Field
+			assert.Equal(CODEUNPROCESSABLEENTITY, d.Code, test.testNarrative+" failed - field error code mismatch")
+		}
+		assert.Equal(test.expectedFields, fields, test.testNarrative+" failed - fields mismatch")
+	}
+}
+
+// Verifies scenarios related to the error catalogue
+func TestErrorCatalogue(t *testing.T) {
+	fmt.Println("Executing Test - ErrorCatalogue")
+
+	// struct for parsing the shim APIs response
+	type cResp struct {
+		Code    string      `json:"code"`
+		Payload []errorCode `json:"payload"`
+	}
+	assert := assert.New(t)
+
+	// Instantiate mockStub using AidChaincode as the target chaincode to unit test
+	stub := shim.NewMockStub("TestStub", new(AidChaincode))
+	assert.NotNil(stub, "Stub is nil, Test stub creation failed")
+
+	result := stub.MockInvoke(uuid.New().String(), [][]byte{[]byte(GetErrorCatalogue), []byte("P101")})
+	assert.EqualValues(shim.ERROR, result.GetStatus(), "Check for no of input args failed - error expected")
+
+	result = stub.MockInvoke(uuid.New().String(), [][]byte{[]byte(GetErrorCatalogue)})
+	assert.EqualValues(shim.OK, result.GetStatus(), GetErrorCatalogue+" failed to read the catalogue")
+
+	c := &cResp{}
+	err := json.Unmarshal(result.GetPayload(), c)
+	if err != nil {
+		panic(err)
+	}
+	codes := make(map[string]string)
+	for _, ec := range c.Payload {
+		assert.NotEmpty(ec.Meaning, "Meaning of "+ec.Code+" is empty")
+		codes[ec.Code] = ec.Name
+	}
+	for _, code := range []string{CODEALLAOK, CODENOTFOUND, CODEUNKNOWNINVOKE, CODEUNPROCESSABLEENTITY, CODENOTALLWD,
+		CODEFORBIDDEN, CODESANCTIONED, CODEGENEXCEPTION, CODEAlRDEXIST} {
+		assert.Contains(codes, code, "Code "+code+" missing in the catalogue")
+	}
+	assert.Equal("CODENOTFOUND", codes[CODENOTFOUND], "Code name mismatch")
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/shopspring/decimal"
+	"github.com/vishal3152/AIDChaincode/aidcc/events"
+)
+
+// Asset model for vendor. All asset models are kept in
+// private scope i.e. they are not exported and ramin invisible to other packages
+// Vendors are suppliers paid by spends. The registry is managed by authorized orgs, bank details stay
+// off-chain and only their hash is recorded. A suspended vendor can not be paid.
+type vendorBase struct {
+	LegalName      string    `json:"legalName"`
+	RegistrationID string    `json:"registrationID"` // company or tax registration number
+	BankRefHash    string    `json:"bankRefHash"`    // hex encoded SHA-256 of the bank reference
+	Status         string    `json:"status"`         // ACTIVE or SUSPENDED
+	UpdatedBy      string    `json:"updatedBy"`
+	UpdatedDt      time.Time `json:"updatedDt"`
+}
+
+type vendor struct {
+	ObjectType string     `json:"docType"` // vendor Type 'VENDOR'
+	versioned             // schema version of the document
+	VendorID   string     `json:"vendorID"` // asset unique key
+	Data       vendorBase `json:"data"`     // composition
+}
+
+// key - ledger key of the vendor
+func (vd *vendor) key() string {
+	return vd.VendorID
+}
+
+// docType - doc type of vendor assets
+func (vd *vendor) docType() string {
+	return VENDOR
+}
+
+// validate - check vendor invariants before it is written
+func (vd *vendor) validate() *chainError {
+	return checkIdentity(vd, vd.ObjectType)
+}
+
+// vendorSpend - total paid to a vendor under a project, in a currency
+type vendorSpend struct {
+	ProjectID string          `json:"projectID"`
+	Currency  string          `json:"currency"`
+	Amount    decimal.Decimal `json:"amount"`
+}
+
+// Write vendor to ledger
+func (vd *vendor) putState(stub shim.ChaincodeStubInterface) pb.Response {
+
+	// check if vendorID is unique
+	c, cErr := checkAsset(stub, vd.VendorID)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	} else if c {
+		e := &chainError{"putVendor", vd.VendorID, CODEAlRDEXIST, errors.New("Asset with key already exists")}
+		return errorResponse(stub, e)
+	}
+
+	cErr = vd.store(stub, "putVendor")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	// Emit transaction event for listeners
+	cErr = emitEvent(stub, "putVendor", vd.VendorID, events.VendorRegistered, vd.event())
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	r := response{CODEALLAOK, vd.VendorID, nil}
+	return shim.Success((r.formatResponse(stub)))
+}
+
+// Read vendor state from the ledger
+func (vd *vendor) getState(stub shim.ChaincodeStubInterface) pb.Response {
+
+	vnd, cErr := queryAsset(stub, vd.VendorID)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	r := response{CODEALLAOK, "OK", vnd}
+	return shim.Success((r.formatResponse(stub)))
+}
+
+// statusState - suspend or reinstate a vendor
+func (vd *vendor) statusState(stub shim.ChaincodeStubInterface, status string, callerID string, timeStamp time.Time) pb.Response {
+
+	cErr := vd.load(stub, "setVendorStatus")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	if vd.Data.Status == status {
+		cErr = &chainError{"setVendorStatus", vd.VendorID, CODENOTALLWD, errors.New("Vendor is already " + status)}
+		return errorResponse(stub, cErr)
+	}
+
+	vd.Data.Status = status
+	vd.Data.UpdatedBy = callerID
+	vd.Data.UpdatedDt = timeStamp
+	cErr = vd.store(stub, "setVendorStatus")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	// Emit transaction event for listeners
+	cErr = emitEvent(stub, "setVendorStatus", vd.VendorID, events.VendorStatusChanged, vd.event())
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	r := response{CODEALLAOK, vd.VendorID, nil}
+	return shim.Success((r.formatResponse(stub)))
+}
+
+// spendingState - read totals paid to the vendor per project & currency, optionally for a single project
+func (vd *vendor) spendingState(stub shim.ChaincodeStubInterface, projectID string) pb.Response {
+
+	cErr := vd.load(stub, "readVendorSpending")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	keys := []string{vd.VendorID}
+	if len(projectID) != 0 {
+		keys = append(keys, projectID)
+	}
+	vndItr, err := stub.GetStateByPartialCompositeKey(VNDIDX, keys)
+	if err != nil {
+		cErr = &chainError{"readVendorSpending", vd.VendorID, CODEGENEXCEPTION, err}
+		return errorResponse(stub, cErr)
+	}
+	//Close itrerator when done reading
+	defer vndItr.Close()
+	totals := make(map[string]*vendorSpend)
+	for vndItr.HasNext() {
+		rangeItem, err := vndItr.Next()
+		if err != nil {
+			cErr = &chainError{"readVendorSpending", vd.VendorID, CODEGENEXCEPTION, err}
+			return errorResponse(stub, cErr)
+		}
+		_, compositeKeyParts, err := stub.SplitCompositeKey(rangeItem.Key)
+		if err != nil {
+			cErr = &chainError{"readVendorSpending", vd.VendorID, CODEGENEXCEPTION, err}
+			return errorResponse(stub, cErr)
+		}
+		// compositeKeyParts[1] represents project ID, [2] currency & [4] amount
+		totalKey := compositeKeyParts[1] + "~" + compositeKeyParts[2]
+		if _, ok := totals[totalKey]; !ok {
+			totals[totalKey] = &vendorSpend{ProjectID: compositeKeyParts[1], Currency: compositeKeyParts[2]}
+		}
+		amount, _ := decimal.NewFromString(compositeKeyParts[4])
+		totals[totalKey].Amount = totals[totalKey].Amount.Add(amount)
+	}
+
+	// report totals in a deterministic order
+	totalKeys := make([]string, 0, len(totals))
+	for k := range totals {
+		totalKeys = append(totalKeys, k)
+	}
+	sort.Strings(totalKeys)
+	spending := make([]vendorSpend, 0, len(totals))
+	for _, k := range totalKeys {
+		spending = append(spending, *totals[k])
+	}
+
+	b, err := json.Marshal(spending)
+	if err != nil {
+		cErr = &chainError{"readVendorSpending", vd.VendorID, CODEGENEXCEPTION, err}
+		return errorResponse(stub, cErr)
+	}
+	r := response{CODEALLAOK, vd.VendorID, b}
+	return shim.Success((r.formatResponse(stub)))
+}
+
+// event - event payload of the vendor
+func (vd *vendor) event() *events.Vendor {
+	return &events.Vendor{VendorID: vd.VendorID, LegalName: vd.Data.LegalName, RegistrationID: vd.Data.RegistrationID, Status: vd.Data.Status, UpdatedBy: vd.Data.UpdatedBy}
+}
+
+// checkPayee - verify the vendor paid by a spend exists and is not suspended
+func checkPayee(stub shim.ChaincodeStubInterface, fcn string, vendorID string) (*vendor, *chainError) {
+
+	vd := &vendor{VendorID: vendorID}
+	cErr := vd.load(stub, fcn)
+	if cErr != nil {
+		return nil, cErr
+	}
+	if vd.Data.Status != VNDACTV {
+		return nil, &chainError{fcn, vendorID, CODENOTALLWD, errors.New("Vendor is " + vd.Data.Status + ", payment not allowed")}
+	}
+	return vd, nil
+}
+
+// putVendorSpend - add a spend to the vendor's totals, reversals are added with a negative amount
+func putVendorSpend(stub shim.ChaincodeStubInterface, fcn string, vendorID string, projectID string, currency string, txnID string, amount decimal.Decimal) *chainError {
+
+	indexKey, err := stub.CreateCompositeKey(VNDIDX, []string{vendorID, projectID, currency, txnID, amount.String()})
+	if err != nil {
+		return &chainError{fcn, txnID, CODEGENEXCEPTION, err}
+	}
+	err = stub.PutState(indexKey, []byte{0x00})
+	if err != nil {
+		return &chainError{fcn, txnID, CODEGENEXCEPTION, err}
+	}
+	return nil
+}
+
+// load - read vendor from the ledger into the receiver
+func (vd *vendor) load(stub shim.ChaincodeStubInterface, fcn string) *chainError {
+	return newRepository(stub, fcn).read(vd)
+}
+
+// store - write vendor state to the ledger
+func (vd *vendor) store(stub shim.ChaincodeStubInterface, fcn string) *chainError {
+	return newRepository(stub, fcn).save(vd)
+}
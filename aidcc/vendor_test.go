@@ -0,0 +1,179 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// Verifies scenarios related to vendor registry and spends paid to vendors
+func TestVendorRW(t *testing.T) {
+	fmt.Println("Executing Test - VendorRW")
+	defer os.Unsetenv("ROLE")
+
+	bankRef := sha256.Sum256([]byte("IBAN DE89370400440532013000"))
+	bankRefHash := hex.EncodeToString(bankRef[:])
+
+	// Test data - Refer to test narratives for test description
+	var vendorTable = []struct {
+		role           string
+		vendorID       string
+		legalName      string
+		registrationID string
+		bankRefHash    string
+		expectedStatus int32
+		testNarrative  string
+	}{
+		{ROLEVNDADMIN, "VN101", "Acme Supplies Ltd", "REG-001", bankRefHash, 200, "Happy scenario"},
+		{ROLEVNDADMIN, "VN102", "Relief Logistics", "REG-002", bankRefHash, 200, "Happy scenario"},
+		{"donor", "VN103", "Rogue Traders", "REG-003", bankRefHash, 500, "Check for caller authorized as vendor admin"},
+		{ROLEVNDADMIN, "VN103", "", "REG-003", bankRefHash, 500, "Check for missing legal name"},
+		{ROLEVNDADMIN, "VN103", "Rogue Traders", "", bankRefHash, 500, "Check for missing registration ID"},
+		{ROLEVNDADMIN, "VN103", "Rogue Traders", "REG-003", "DE89370400440532013000", 500, "Check for SHA-256 bank reference hash"},
+		{ROLEVNDADMIN, "VN101", "Acme Supplies Ltd", "REG-001", bankRefHash, 500, "Check for duplicate vendor ID"},
+	}
+	// Status changes & spends are executed in order
+	var spendTable = []struct {
+		txnID          string
+		vendorID       string
+		status         string
+		amount         string
+		expectedStatus int32
+		testNarrative  string
+	}{
+		{"S101", "VN101", "", "100", 200, "Happy scenario"},
+		{"S102", "VN101", "", "40", 200, "Happy scenario"},
+		{"S103", "VN102", "", "30", 200, "Happy scenario"},
+		{"S104", "VN109", "", "30", 500, "Check for vendor existence"},
+		{"S105", "VN102", VNDSUSP, "30", 500, "Check for payment to suspended vendor"},
+		{"S106", "VN102", VNDACTV, "20", 200, "Payment to reinstated vendor"},
+	}
+	// Expected totals paid to the vendors, S102 is reversed
+	var totalTable = []struct {
+		vendorID       string
+		expectedAmount string
+	}{
+		{"VN101", "100"},
+		{"VN102", "50"},
+	}
+
+	// struct for parsing the shim APIs response
+	type sResp struct {
+		Code    string        `json:"code"`
+		Message string        `json:"message"`
+		Payload []vendorSpend `json:"payload"`
+	}
+	assert := assert.New(t)
+
+	// Instantiate mockStub using AidChaincode as the target chaincode to unit test
+	stub := shim.NewMockStub("TestStub", new(AidChaincode))
+	assert.NotNil(stub, "Stub is nil, Test stub creation failed")
+
+	uid := uuid.New().String()
+
+	// Add test project, item & donation to state.
+	result := stub.MockInvoke(uid,
+		[][]byte{[]byte(AddProject),
+			[]byte("P101"),
+			[]byte("Prj101")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Saving test project to state failed.")
+
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(AddItem),
+			[]byte("Itm001"),
+			[]byte("Item001"),
+			[]byte("Medicine")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Saving test item to state failed.")
+
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(AddDonation),
+			[]byte("D101"),
+			[]byte("vishal"),
+			[]byte("P101"),
+			[]byte("Itm001"),
+			[]byte("500")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Saving test donation entry to state failed.")
+
+	// Refresh Available funds under Project
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(GetProject),
+			[]byte("P101")})
+	assert.EqualValues(shim.OK, result.GetStatus(), GetProject+" failed to read the project data")
+
+	// Executing vendor registry tests
+	for _, test := range vendorTable {
+		os.Setenv("ROLE", test.role)
+		result := stub.MockInvoke(uid,
+			[][]byte{[]byte(AddVendor),
+				[]byte(test.vendorID),
+				[]byte(test.legalName),
+				[]byte(test.registrationID),
+				[]byte(test.bankRefHash)})
+
+		assert.Equal(test.expectedStatus, result.GetStatus(), test.testNarrative+" failed - "+(result.GetMessage()))
+	}
+
+	// Executing vendor payment tests
+	os.Setenv("ROLE", ROLEVNDADMIN)
+	for _, test := range spendTable {
+		if len(test.status) != 0 {
+			result := stub.MockInvoke(uid,
+				[][]byte{[]byte(SetVendorStatus),
+					[]byte(test.vendorID),
+					[]byte(test.status)})
+			assert.EqualValues(shim.OK, result.GetStatus(), SetVendorStatus+" failed - "+(result.GetMessage()))
+		}
+
+		result := stub.MockInvoke(uid,
+			[][]byte{[]byte(AddSpend),
+				[]byte(test.txnID),
+				[]byte("vishal"),
+				[]byte("P101"),
+				[]byte("Itm001"),
+				[]byte(test.amount),
+				[]byte(""),
+				[]byte(""),
+				[]byte(test.vendorID)})
+
+		assert.Equal(test.expectedStatus, result.GetStatus(), test.testNarrative+" failed - "+(result.GetMessage()))
+	}
+
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(SetVendorStatus),
+			[]byte("VN102"),
+			[]byte(VNDACTV)})
+	assert.EqualValues(shim.ERROR, result.GetStatus(), "Check for vendor already active failed")
+
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(ReverseSpend),
+			[]byte("S102"),
+			[]byte("Paid to wrong vendor")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Reversing test spend entry failed.")
+
+	// Verify totals paid to vendors
+	for _, test := range totalTable {
+		result := stub.MockInvoke(uid,
+			[][]byte{[]byte(GetVendorSpending),
+				[]byte(test.vendorID),
+				[]byte("P101")})
+		assert.EqualValues(shim.OK, result.GetStatus(), GetVendorSpending+" failed to read the vendor spending")
+
+		s := &sResp{}
+		err := json.Unmarshal(result.GetPayload(), s)
+		if err != nil {
+			panic(err)
+		}
+		expectedAmount, _ := decimal.NewFromString(test.expectedAmount)
+		assert.Len(s.Payload, 1, test.vendorID+" spending lines mismatch")
+		assert.Equal(DEFCCY, s.Payload[0].Currency, test.vendorID+" spending currency mismatch")
+		assert.True(expectedAmount.Equal(s.Payload[0].Amount), test.vendorID+" spending total mismatch")
+	}
+}
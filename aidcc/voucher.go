@@ -0,0 +1,307 @@
+package main
+
+import (
+	"errors"
+	"time"
+
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/shopspring/decimal"
+	"github.com/vishal3152/AIDChaincode/aidcc/events"
+)
+
+// Asset model for beneficiary voucher. All asset models are kept in
+// private scope i.e. they are not exported and ramin invisible to other packages
+// Issuing a voucher reserves project funds for a beneficiary. An authorized vendor redeeming the
+// voucher converts the reservation into a spend, an unredeemed voucher expires and its reservation
+// returns to the project's available fund.
+type voucherBase struct {
+	ProjectID  string          `json:"projectID"`
+	ItemID     string          `json:"itemID"`
+	Amount     decimal.Decimal `json:"amount"`
+	Currency   string          `json:"currency"`           // ISO 4217 code
+	Category   string          `json:"category,omitempty"` // budget line the voucher is spent under, if any
+	Status     string          `json:"status"`             // ISSUED, REDEEMED or EXPIRED
+	IssuedDt   time.Time       `json:"issuedDt"`
+	ExpiryDt   time.Time       `json:"expiryDt"`             // voucher can be redeemed until end of the day
+	RedeemedBy string          `json:"redeemedBy,omitempty"` // vendor identity
+	RedeemedDt time.Time       `json:"redeemedDt"`
+	SpendTxnID string          `json:"spendTxnID,omitempty"` // spend recorded on redemption
+}
+
+type voucher struct {
+	ObjectType string      `json:"docType"` // voucher Type 'VOUCHR'
+	versioned              // schema version of the document
+	VoucherID  string      `json:"voucherID"` // asset unique key
+	Benficiary string      `json:"beneficiary"`
+	Data       voucherBase `json:"data"` // composition
+}
+
+// key - ledger key of the voucher
+func (v *voucher) key() string {
+	return v.VoucherID
+}
+
+// docType - doc type of voucher assets
+func (v *voucher) docType() string {
+	return VOUCHR
+}
+
+// validate - check voucher invariants before it is written
+func (v *voucher) validate() *chainError {
+
+	cErr := checkIdentity(v, v.ObjectType)
+	if cErr != nil {
+		return cErr
+	}
+	if v.Data.Amount.Sign() <= 0 {
+		return &chainError{"validate", v.key(), CODEUNPROCESSABLEENTITY, errors.New("Amount must be positive")}
+	}
+	return nil
+}
+
+// redemptionKey - derive redemption spend key from the voucher ID
+func redemptionKey(voucherID string) string {
+	return voucherID + "_RDM"
+}
+
+// redemption - spend recorded on redemption of the voucher
+func (v *voucher) redemption(timeStamp time.Time) *spend {
+	spendData := donationBase{ProjectID: v.Data.ProjectID, ItemID: v.Data.ItemID, Amount: v.Data.Amount, Currency: v.Data.Currency, TimeStamp: timeStamp}
+	return &spend{ObjectType: DONOUT, TxnID: redemptionKey(v.VoucherID), Benficiary: v.Benficiary, Category: v.Data.Category, VoucherID: v.VoucherID, Data: spendData}
+}
+
+// expired - check if voucher is past its expiry date at the given time
+func (v *voucher) expired(t time.Time) bool {
+	return !t.Before(v.Data.ExpiryDt.AddDate(0, 0, 1))
+}
+
+// Write voucher to ledger, reserving its amount from project's available fund
+func (v *voucher) putState(stub shim.ChaincodeStubInterface) pb.Response {
+
+	// check if affiliated project exists
+	c, cErr := checkAsset(stub, v.Data.ProjectID)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	} else if !c {
+		e := &chainError{"putVoucher", v.VoucherID, CODENOTFOUND, errors.New("Affiliated project not found")}
+		return errorResponse(stub, e)
+	}
+
+	// resolve voucher currency, defaults to project's base currency
+	v.Data.Currency, v.Data.Amount, cErr = resolveCurrency(stub, "putVoucher", v.VoucherID, v.Data.ProjectID, v.Data.Currency, v.Data.Amount)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	// check if affiliated item exists
+	c, cErr = checkAsset(stub, v.Data.ItemID)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	} else if !c {
+		e := &chainError{"putVoucher", v.VoucherID, CODENOTFOUND, errors.New("Affiliated item not found")}
+		return errorResponse(stub, e)
+	}
+
+	// check if voucherID is unique
+	c, cErr = checkAsset(stub, v.VoucherID)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	} else if c {
+		e := &chainError{"putVoucher", v.VoucherID, CODEAlRDEXIST, errors.New("Asset with key already exists")}
+		return errorResponse(stub, e)
+	}
+
+	// check if project has funds available in voucher currency before reserving
+	p := &project{ProjectID: v.Data.ProjectID}
+	cErr = p.load(stub, "putVoucher")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	zeroDecimal, _ := decimal.NewFromString("0")
+	bal, ok := p.Data.Balances[v.Data.Currency]
+	if !ok || bal.AvlFund.Sub(v.Data.Amount).LessThanOrEqual(zeroDecimal) {
+		cErr = &chainError{"putVoucher", v.VoucherID, CODENOTALLWD, errors.New("Overwithdrawal for funds not allowed")}
+		return errorResponse(stub, cErr)
+	}
+
+	// check if the reservation stays within approved milestone budgets & the voucher's budget line, the
+	// same caps are checked again when the voucher is redeemed
+	cErr = checkMilestoneCap(stub, "putVoucher", v.VoucherID, p, v.Data.Currency, v.Data.Amount, zeroDecimal)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	cErr = checkBudget(stub, "putVoucher", p, v.redemption(v.Data.IssuedDt), zeroDecimal)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	cErr = v.store(stub, "putVoucher")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	// Reserve the voucher amount under project funds & hold it against its budget line
+	cErr = putDelta(stub, "putVoucher", v.Data.ProjectID, "5", v.Data.Currency, v.VoucherID, v.Data.Amount)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	if len(v.Data.Category) != 0 {
+		cErr = putCategorySpend(stub, "putVoucher", v.Data.ProjectID, v.Data.Category, v.VoucherID, v.Data.Amount)
+		if cErr != nil {
+			return errorResponse(stub, cErr)
+		}
+	}
+
+	// Emit transaction event for listeners
+	cErr = emitEvent(stub, "putVoucher", v.VoucherID, events.VoucherIssued, v.event())
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	r := response{CODEALLAOK, v.VoucherID, nil}
+	return shim.Success((r.formatResponse(stub)))
+}
+
+// Read voucher state from the ledger
+func (v *voucher) getState(stub shim.ChaincodeStubInterface) pb.Response {
+
+	vch, cErr := queryAsset(stub, v.VoucherID)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	r := response{CODEALLAOK, "OK", vch}
+	return shim.Success((r.formatResponse(stub)))
+}
+
+// Redeem an issued voucher, converting its reservation into a spend to the beneficiary
+func (v *voucher) redeemState(stub shim.ChaincodeStubInterface, vendor string, timeStamp time.Time) pb.Response {
+
+	cErr := v.load(stub, "redeemVoucher")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	if v.Data.Status != VCHISSD {
+		cErr = &chainError{"redeemVoucher", v.VoucherID, CODENOTALLWD, errors.New("Voucher is " + v.Data.Status)}
+		return errorResponse(stub, cErr)
+	}
+	if v.expired(timeStamp) {
+		cErr = &chainError{"redeemVoucher", v.VoucherID, CODENOTALLWD, errors.New("Voucher expired on " + v.Data.ExpiryDt.Format(DATEFMT))}
+		return errorResponse(stub, cErr)
+	}
+
+	// check if redemption spend txnID is unique
+	s := v.redemption(timeStamp)
+	c, cErr := checkAsset(stub, s.TxnID)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	} else if c {
+		e := &chainError{"redeemVoucher", s.TxnID, CODEAlRDEXIST, errors.New("Asset with key already exists")}
+		return errorResponse(stub, e)
+	}
+
+	// check the redemption as any other spend, the reserved amount is already held back from available
+	// fund hence only caps & parties are checked
+	p := &project{ProjectID: v.Data.ProjectID}
+	cErr = p.load(stub, "redeemVoucher")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	cErr = s.checkSpend(stub, "redeemVoucher", p, v.Data.Amount)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	v.Data.Status = VCHRDMD
+	v.Data.RedeemedBy = vendor
+	v.Data.RedeemedDt = timeStamp
+	v.Data.SpendTxnID = s.TxnID
+	cErr = v.store(stub, "redeemVoucher")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	// Release the reservation and record the spend
+	cErr = putDelta(stub, "redeemVoucher", v.Data.ProjectID, "6", v.Data.Currency, v.VoucherID, v.Data.Amount)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	cErr = v.release(stub, "redeemVoucher")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	cErr = s.record(stub, "redeemVoucher")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	// Emit transaction event for listeners
+	cErr = emitEvent(stub, "redeemVoucher", v.VoucherID, events.VoucherRedeemed, v.event())
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	r := response{CODEALLAOK, s.TxnID, nil}
+	return shim.Success((r.formatResponse(stub)))
+}
+
+// Expire an issued voucher past its expiry date, returning its reservation to project's available fund
+func (v *voucher) expireState(stub shim.ChaincodeStubInterface, timeStamp time.Time) pb.Response {
+
+	cErr := v.load(stub, "expireVoucher")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	if v.Data.Status != VCHISSD {
+		cErr = &chainError{"expireVoucher", v.VoucherID, CODENOTALLWD, errors.New("Voucher is " + v.Data.Status)}
+		return errorResponse(stub, cErr)
+	}
+	if !v.expired(timeStamp) {
+		cErr = &chainError{"expireVoucher", v.VoucherID, CODENOTALLWD, errors.New("Voucher is valid until " + v.Data.ExpiryDt.Format(DATEFMT))}
+		return errorResponse(stub, cErr)
+	}
+
+	v.Data.Status = VCHEXPD
+	cErr = v.store(stub, "expireVoucher")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	cErr = putDelta(stub, "expireVoucher", v.Data.ProjectID, "6", v.Data.Currency, v.VoucherID, v.Data.Amount)
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	cErr = v.release(stub, "expireVoucher")
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+
+	// Emit transaction event for listeners
+	cErr = emitEvent(stub, "expireVoucher", v.VoucherID, events.VoucherExpired, v.event())
+	if cErr != nil {
+		return errorResponse(stub, cErr)
+	}
+	r := response{CODEALLAOK, v.VoucherID, nil}
+	return shim.Success((r.formatResponse(stub)))
+}
+
+// release - clear the amount held by the voucher against its budget line, if any
+func (v *voucher) release(stub shim.ChaincodeStubInterface, fcn string) *chainError {
+	if len(v.Data.Category) == 0 {
+		return nil
+	}
+	return delCategorySpend(stub, fcn, v.Data.ProjectID, v.Data.Category, v.VoucherID, v.Data.Amount)
+}
+
+// event - event payload of the voucher
+func (v *voucher) event() *events.Voucher {
+	return &events.Voucher{VoucherID: v.VoucherID, ProjectID: v.Data.ProjectID, ItemID: v.Data.ItemID, Beneficiary: v.Benficiary, Amount: v.Data.Amount,
+		Currency: v.Data.Currency, Status: v.Data.Status, ExpiryDt: v.Data.ExpiryDt.Format(DATEFMT), RedeemedBy: v.Data.RedeemedBy, SpendTxnID: v.Data.SpendTxnID}
+}
+
+// load - read voucher from the ledger into the receiver
+func (v *voucher) load(stub shim.ChaincodeStubInterface, fcn string) *chainError {
+	return newRepository(stub, fcn).read(v)
+}
+
+// store - write voucher state to the ledger
+func (v *voucher) store(stub shim.ChaincodeStubInterface, fcn string) *chainError {
+	return newRepository(stub, fcn).save(v)
+}
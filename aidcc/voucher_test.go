@@ -0,0 +1,290 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// Verifies scenarios related to beneficiary vouchers, their redemption & expiry
+func TestVoucherRW(t *testing.T) {
+	fmt.Println("Executing Test - VoucherRW")
+	defer os.Unsetenv("ROLE")
+
+	today := time.Now().UTC()
+	pastDt := today.AddDate(0, 0, -1).Format(DATEFMT)
+	futureDt := today.AddDate(0, 0, 30).Format(DATEFMT)
+
+	// Test data - Refer to test narratives for test description
+	var issueTable = []struct {
+		role           string
+		voucherID      string
+		itemID         string
+		amount         string
+		expiryDt       string
+		expectedStatus int32
+		testNarrative  string
+	}{
+		{ROLEISSUER, "V101", "Itm001", "100", futureDt, 200, "Happy scenario"},
+		{ROLEISSUER, "V102", "Itm001", "50", today.Format(DATEFMT), 200, "Happy scenario - voucher valid until end of today"},
+		{ROLEISSUER, "V103", "Itm001", "400", futureDt, 500, "Check for overwithdrawal of funds"},
+		{ROLEISSUER, "V103", "Itm009", "50", futureDt, 500, "Check for item ID existence"},
+		{ROLEISSUER, "V103", "Itm001", "50", pastDt, 500, "Check for expiry date in the past"},
+		{ROLEISSUER, "V103", "Itm001", "50", "31-12-2099", 500, "Check for expiry date format"},
+		{ROLEISSUER, "V103", "Itm001", "0", futureDt, 500, "Check for positive amount"},
+		{ROLEISSUER, "V101", "Itm001", "50", futureDt, 500, "Check for duplicate voucher ID"},
+		{"donor", "V103", "Itm001", "50", futureDt, 500, "Check for caller authorized as voucher issuer"},
+	}
+	// Redemptions & expiries are executed in order
+	var redeemTable = []struct {
+		function       string
+		role           string
+		voucherID      string
+		expectedStatus int32
+		testNarrative  string
+	}{
+		{RedeemVoucher, "donor", "V101", 500, "Check for caller authorized as vendor"},
+		{RedeemVoucher, ROLEVENDOR, "V101", 200, "Happy scenario"},
+		{RedeemVoucher, ROLEVENDOR, "V101", 500, "Check for voucher already redeemed"},
+		{ExpireVoucher, ROLEVENDOR, "V102", 500, "Check for caller authorized as voucher issuer"},
+		{ExpireVoucher, ROLEISSUER, "V101", 500, "Check for expiry of a redeemed voucher"},
+		{ExpireVoucher, ROLEISSUER, "V102", 500, "Check for expiry of a voucher still valid"},
+		{RedeemVoucher, ROLEVENDOR, "V109", 500, "Check for voucher existence"},
+	}
+
+	// struct for parsing the shim APIs response
+	type vResp struct {
+		Code    string  `json:"code"`
+		Message string  `json:"message"`
+		Payload voucher `json:"payload"`
+	}
+	type pResp struct {
+		Code    string  `json:"code"`
+		Message string  `json:"message"`
+		Payload project `json:"payload"`
+	}
+	assert := assert.New(t)
+
+	// Instantiate mockStub using AidChaincode as the target chaincode to unit test
+	stub := shim.NewMockStub("TestStub", new(AidChaincode))
+	assert.NotNil(stub, "Stub is nil, Test stub creation failed")
+
+	uid := uuid.New().String()
+
+	// Add test project, item & donation to state.
+	result := stub.MockInvoke(uid,
+		[][]byte{[]byte(AddProject),
+			[]byte("P101"),
+			[]byte("Prj101")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Saving test project to state failed.")
+
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(AddItem),
+			[]byte("Itm001"),
+			[]byte("Item001"),
+			[]byte("Food")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Saving test item to state failed.")
+
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(AddDonation),
+			[]byte("D101"),
+			[]byte("vishal"),
+			[]byte("P101"),
+			[]byte("Itm001"),
+			[]byte("500")})
+	assert.EqualValues(shim.OK, result.GetStatus(), "Saving test donation entry to state failed.")
+
+	// Executing issue tests, project funds are refreshed before each voucher
+	for _, test := range issueTable {
+		result = stub.MockInvoke(uid,
+			[][]byte{[]byte(GetProject),
+				[]byte("P101")})
+		assert.EqualValues(shim.OK, result.GetStatus(), GetProject+" failed to read the project data")
+
+		os.Setenv("ROLE", test.role)
+		result := stub.MockInvoke(uid,
+			[][]byte{[]byte(IssueVoucher),
+				[]byte(test.voucherID),
+				[]byte("beneficiary"),
+				[]byte("P101"),
+				[]byte(test.itemID),
+				[]byte(test.amount),
+				[]byte(test.expiryDt)})
+
+		assert.Equal(test.expectedStatus, result.GetStatus(), test.testNarrative+" failed - "+(result.GetMessage()))
+		os.Unsetenv("ROLE")
+	}
+
+	// Executing redemption & expiry tests
+	for _, test := range redeemTable {
+		os.Setenv("ROLE", test.role)
+		result := stub.MockInvoke(uid,
+			[][]byte{[]byte(test.function),
+				[]byte(test.voucherID)})
+
+		assert.Equal(test.expectedStatus, result.GetStatus(), test.testNarrative+" failed - "+(result.GetMessage()))
+	}
+
+	// Verify redeemed voucher & the spend recorded on redemption
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(GetVoucher),
+			[]byte("V101")})
+	assert.EqualValues(shim.OK, result.GetStatus(), GetVoucher+" failed to read the voucher")
+
+	v := &vResp{}
+	err := json.Unmarshal(result.GetPayload(), v)
+	if err != nil {
+		panic(err)
+	}
+	assert.Equal(VCHRDMD, v.Payload.Data.Status, "Voucher status mismatch")
+	assert.Equal(redemptionKey("V101"), v.Payload.Data.SpendTxnID, "Redemption spend mismatch")
+
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(GetSpend),
+			[]byte(redemptionKey("V101"))})
+	assert.EqualValues(shim.OK, result.GetStatus(), GetSpend+" failed to read the redemption spend")
+
+	// Verify reserved, spent & available funds under project
+	result = stub.MockInvoke(uid,
+		[][]byte{[]byte(GetProject),
+			[]byte("P101")})
+	assert.EqualValues(shim.OK, result.GetStatus(), GetProject+" failed to read the project data")
+
+	p := &pResp{}
+	err = json.Unmarshal(result.GetPayload(), p)
+	if err != nil {
+		panic(err)
+	}
+	expectedReserved, _ := decimal.NewFromString("50")
+	expectedSpent, _ := decimal.NewFromString("100")
+	expectedAvl, _ := decimal.NewFromString("350")
+	assert.True(expectedReserved.Equal(p.Payload.Data.ReservedFund), "Reserved fund mismatch")
+	assert.True(expectedSpent.Equal(p.Payload.Data.SpentFund), "Spent fund mismatch")
+	assert.True(expectedAvl.Equal(p.Payload.Data.AvlFund), "Available fund mismatch")
+}
+
+// Verifies vouchers are held to approved milestone budgets & budget lines, when issued and when redeemed
+func TestVoucherCaps(t *testing.T) {
+	fmt.Println("Executing Test - VoucherCaps")
+	defer os.Unsetenv("ROLE")
+
+	futureDt := time.Now().UTC().AddDate(0, 0, 30).Format(DATEFMT)
+
+	// Test data - Refer to test narratives for test description. P101 has milestone budgets of 100
+	// approved & P102 a Food budget line of 100, invokes are executed in order
+	var capTable = []struct {
+		args           []string
+		role           string
+		expectedStatus int32
+		testNarrative  string
+	}{
+		{[]string{IssueVoucher, "V101", "beneficiary", "P101", "Itm001", "80", futureDt}, ROLEISSUER, 200, "Happy scenario - voucher within milestone budgets"},
+		{[]string{IssueVoucher, "V102", "beneficiary", "P101", "Itm001", "30", futureDt}, ROLEISSUER, 500, "Check for reservations beyond milestone budgets"},
+		{[]string{AddSpend, "S101", "vishal", "P101", "Itm001", "30"}, "", 500, "Check for spend beyond milestone budgets net of reservations"},
+		{[]string{RedeemVoucher, "V101"}, ROLEVENDOR, 200, "Happy scenario - redemption within milestone budgets"},
+		{[]string{IssueVoucher, "V201", "beneficiary", "P102", "Itm001", "50", futureDt}, ROLEISSUER, 500, "Check for budget category of voucher under a budget"},
+		{[]string{IssueVoucher, "V201", "beneficiary", "P102", "Itm001", "110", futureDt, "", "Food"}, ROLEISSUER, 500, "Check for voucher beyond budget line"},
+		{[]string{IssueVoucher, "V201", "beneficiary", "P102", "Itm001", "50", futureDt, "", "Food"}, ROLEISSUER, 200, "Happy scenario - voucher within budget line"},
+		{[]string{IssueVoucher, "V202", "beneficiary", "P102", "Itm001", "40", futureDt, "", "Food"}, ROLEISSUER, 200, "Happy scenario - vouchers together within budget line"},
+		{[]string{IssueVoucher, "V203", "beneficiary", "P102", "Itm001", "20", futureDt, "", "Food"}, ROLEISSUER, 500, "Check for vouchers together beyond budget line"},
+		{[]string{AddSpend, "S201", "vishal", "P102", "Itm001", "20", "", "Food"}, "", 500, "Check for spend beyond budget line net of outstanding vouchers"},
+		{[]string{AddSpend, "S201", "vishal", "P102", "Itm001", "10", "", "Food"}, "", 200, "Happy scenario - spend within budget line"},
+		{[]string{RedeemVoucher, "V201"}, ROLEVENDOR, 200, "Happy scenario - redemption held under its own voucher"},
+	}
+
+	// struct for parsing the shim APIs response
+	type vResp struct {
+		Payload voucher `json:"payload"`
+	}
+	type uResp struct {
+		Payload []budgetUtilisation `json:"payload"`
+	}
+	assert := assert.New(t)
+
+	// Instantiate mockStub using AidChaincode as the target chaincode to unit test
+	stub := shim.NewMockStub("TestStub", new(AidChaincode))
+	assert.NotNil(stub, "Stub is nil, Test stub creation failed")
+
+	// Add test projects, item, donations, milestone & budget line to state.
+	setup := []struct {
+		args []string
+		role string
+	}{
+		{[]string{AddProject, "P101", "Prj101"}, ""},
+		{[]string{AddProject, "P102", "Prj102"}, ""},
+		{[]string{AddItem, "Itm001", "Item001", "Food"}, ""},
+		{[]string{AddDonation, "D101", "vishal", "P101", "Itm001", "500"}, ""},
+		{[]string{AddDonation, "D102", "vishal", "P102", "Itm001", "500"}, ""},
+		{[]string{AddMilestone, "P101", "M1", "Distribution", "100"}, ""},
+		{[]string{ApproveMilestone, "P101", "M1"}, ROLEVERIFIER},
+		{[]string{AddBudgetLine, "P102", "Food", "100"}, ""},
+		{[]string{GetProject, "P101"}, ""},
+		{[]string{GetProject, "P102"}, ""},
+	}
+	for _, step := range setup {
+		args := make([][]byte, len(step.args))
+		for i, arg := range step.args {
+			args[i] = []byte(arg)
+		}
+		os.Setenv("ROLE", step.role)
+		result := stub.MockInvoke(uuid.New().String(), args)
+		assert.EqualValues(shim.OK, result.GetStatus(), "Saving test data "+step.args[0]+" to state failed - "+result.GetMessage())
+	}
+
+	// Executing tests
+	for _, test := range capTable {
+		args := make([][]byte, len(test.args))
+		for i, arg := range test.args {
+			args[i] = []byte(arg)
+		}
+		os.Setenv("ROLE", test.role)
+		result := stub.MockInvoke(uuid.New().String(), args)
+		assert.Equal(test.expectedStatus, result.GetStatus(), test.testNarrative+" failed - "+(result.GetMessage()))
+	}
+
+	// Expire V202, rewritten with an expiry date in the past, its amount is no longer held under the budget line
+	os.Unsetenv("ROLE")
+	result := stub.MockInvoke(uuid.New().String(), [][]byte{[]byte(GetVoucher), []byte("V202")})
+	assert.EqualValues(shim.OK, result.GetStatus(), GetVoucher+" failed to read the voucher")
+	v := &vResp{}
+	err := json.Unmarshal(result.GetPayload(), v)
+	if err != nil {
+		panic(err)
+	}
+	assert.Equal("Food", v.Payload.Data.Category, "Voucher budget line mismatch")
+	v.Payload.Data.ExpiryDt = time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+	b, err := json.Marshal(&v.Payload)
+	if err != nil {
+		panic(err)
+	}
+	stub.MockTransactionStart(uuid.New().String())
+	err = stub.PutState("V202", b)
+	if err != nil {
+		panic(err)
+	}
+	stub.MockTransactionEnd("")
+	os.Setenv("ROLE", ROLEISSUER)
+	result = stub.MockInvoke(uuid.New().String(), [][]byte{[]byte(ExpireVoucher), []byte("V202")})
+	assert.EqualValues(shim.OK, result.GetStatus(), ExpireVoucher+" failed to expire the voucher - "+result.GetMessage())
+	os.Unsetenv("ROLE")
+
+	// Verify redeemed voucher is counted once & expired voucher is released
+	result = stub.MockInvoke(uuid.New().String(), [][]byte{[]byte(GetBudgetUtilisation), []byte("P102"), []byte("Food")})
+	assert.EqualValues(shim.OK, result.GetStatus(), GetBudgetUtilisation+" failed to read the budget utilisation")
+	u := &uResp{}
+	err = json.Unmarshal(result.GetPayload(), u)
+	if err != nil {
+		panic(err)
+	}
+	if assert.Len(u.Payload, 1, "Budget lines mismatch") {
+		assert.Equal("60", u.Payload[0].Spent.String(), "Budget line spent mismatch")
+		assert.Equal("40", u.Payload[0].Remaining.String(), "Budget line remaining mismatch")
+	}
+}